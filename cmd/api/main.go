@@ -2,29 +2,43 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/adapter/controller"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/adapter/presenter"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
 	ucase "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/usecase"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/audit"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/auth"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/datasource"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/logger"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/notifier"
 )
 
 type appDeps struct {
-	ctrl port.UserController
-	pres port.Presenter
-	jwt  port.JWTSigner
+	ctrl  port.UserController
+	oauth port.OAuthController
+	pres  port.Presenter
+	jwt   port.JWTSigner
+	audit port.AuditLog // nil when impersonation is disabled
+
+	limiter    port.RateLimiter // nil disables rate limiting
+	ipLimit    domain.RateLimit
+	emailLimit domain.RateLimit
 }
 
 type UserResponse struct {
@@ -43,23 +57,211 @@ func build(ctx context.Context) (appDeps, error) {
 	if err != nil {
 		return appDeps{}, err
 	}
-	jwtSigner := auth.NewJWTSigner(cfg)
-	uc := ucase.NewUserUseCase(repo, jwtSigner)
+	refreshRepo, err := datasource.NewDynamoRefreshTokenRepository(ctx, cfg)
+	if err != nil {
+		return appDeps{}, err
+	}
+	revocations, err := datasource.NewDynamoTokenRevocationStore(ctx, cfg)
+	if err != nil {
+		return appDeps{}, err
+	}
+	jwtSigner, err := auth.NewJWTSigner(cfg, revocations)
+	if err != nil {
+		return appDeps{}, err
+	}
+	oneTimeTokens, err := datasource.NewDynamoOneTimeTokenRepository(ctx, cfg)
+	if err != nil {
+		return appDeps{}, err
+	}
+	sessions, err := datasource.NewDynamoSessionRepository(ctx, cfg)
+	if err != nil {
+		return appDeps{}, err
+	}
+	var notify port.Notifier
+	switch cfg.Notifier {
+	case "":
+		// opt-in: without a notifier the flows that email one-time tokens stay disabled
+	case "ses":
+		if notify, err = notifier.NewSESNotifier(ctx, cfg); err != nil {
+			return appDeps{}, err
+		}
+	case "log":
+		if cfg.Environment == "production" {
+			return appDeps{}, errors.New("NOTIFIER=log is for development only")
+		}
+		notify = notifier.NewLogNotifier(log)
+	default:
+		return appDeps{}, fmt.Errorf("unknown NOTIFIER %q", cfg.Notifier)
+	}
+	opts := []ucase.Option{
+		ucase.WithRefreshTokens(refreshRepo, cfg.RefreshTokenExpiration),
+		ucase.WithSessions(sessions),
+		ucase.WithPasswordPolicy(ucase.PasswordPolicy{
+			MinLength:      cfg.PasswordMinLength,
+			MaxLength:      cfg.PasswordMaxLength,
+			RequireLower:   cfg.PasswordRequireLower,
+			RequireUpper:   cfg.PasswordRequireUpper,
+			RequireDigit:   cfg.PasswordRequireDigit,
+			RequireSymbol:  cfg.PasswordRequireSymbol,
+			RejectPersonal: cfg.PasswordRejectPersonal,
+			RejectCommon:   cfg.PasswordRejectCommon,
+		}),
+	}
+	if cfg.MagicLinkURL != "" {
+		if u, err := url.Parse(cfg.MagicLinkURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return appDeps{}, fmt.Errorf("MAGIC_LINK_URL must be an absolute URL, got %q", cfg.MagicLinkURL)
+		}
+	}
+	if notify != nil {
+		opts = append(opts,
+			ucase.WithNotifier(notify),
+			ucase.WithPasswordReset(oneTimeTokens, cfg.PasswordResetExpiration),
+			ucase.WithMagicLinks(oneTimeTokens, cfg.MagicLinkExpiration, cfg.MagicLinkURL),
+			ucase.WithEmailVerification(oneTimeTokens, cfg.EmailVerificationExpiration, cfg.RequireEmailVerification),
+			ucase.WithEmailChange(oneTimeTokens, cfg.EmailChangeExpiration),
+		)
+		if cfg.EnumerationSafeRegistration {
+			opts = append(opts, ucase.WithEnumerationSafeRegistration())
+		}
+	} else {
+		log.Warn("api: no NOTIFIER configured, password reset, magic links, email verification, email change and enumeration-safe registration are disabled")
+	}
+	if cfg.LockoutThreshold > 0 {
+		opts = append(opts, ucase.WithLockout(cfg.LockoutThreshold, cfg.LockoutDuration, cfg.LockoutMaxDuration))
+	}
+	if cfg.MFAEncryptionKey != "" {
+		cipher, err := auth.NewSecretCipher(cfg.MFAEncryptionKey)
+		if err != nil {
+			return appDeps{}, err
+		}
+		opts = append(opts, ucase.WithTOTP(cipher, oneTimeTokens, cfg.MFAIssuer, cfg.MFAChallengeExpiration))
+	}
+	if cfg.WebAuthnRPID != "" {
+		passkeys, err := datasource.NewDynamoPasskeyCredentialRepository(ctx, cfg)
+		if err != nil {
+			return appDeps{}, err
+		}
+		opts = append(opts, ucase.WithPasskeys(auth.NewWebAuthn(cfg.WebAuthnRPID, cfg.WebAuthnOrigins), passkeys, oneTimeTokens,
+			cfg.WebAuthnRPID, cfg.WebAuthnRPName, cfg.WebAuthnChallengeExpiration))
+	}
+	if cfg.IdentityProviders != "" {
+		providers, err := auth.NewIdentityProviders(cfg.IdentityProviders, nil)
+		if err != nil {
+			return appDeps{}, err
+		}
+		identities, err := datasource.NewDynamoFederatedIdentityRepository(ctx, cfg)
+		if err != nil {
+			return appDeps{}, err
+		}
+		opts = append(opts, ucase.WithFederatedLogin(providers, identities, oneTimeTokens, cfg.FederatedLoginExpiration))
+	}
+	if cfg.PersonalAccessTokensEnabled {
+		tokens, err := datasource.NewDynamoPersonalAccessTokenRepository(ctx, cfg)
+		if err != nil {
+			return appDeps{}, err
+		}
+		opts = append(opts, ucase.WithPersonalAccessTokens(tokens, cfg.PersonalAccessTokenMaxLifetime))
+	}
+	var auditLog port.AuditLog
+	if cfg.ImpersonationEnabled {
+		auditLog = audit.NewLogAuditLog(log)
+		opts = append(opts, ucase.WithImpersonation(auditLog, cfg.ImpersonationTokenExpiration))
+	}
+	hasher, err := newPasswordHasher(cfg)
+	if err != nil {
+		return appDeps{}, err
+	}
+	uc := ucase.NewUserUseCase(repo, hasher, jwtSigner, opts...)
 	ctrl := controller.NewUserController(uc)
+	clients, err := datasource.NewDynamoOAuthClientRepository(ctx, cfg)
+	if err != nil {
+		return appDeps{}, err
+	}
+	oauthOpts := []ucase.OAuthOption{ucase.WithClientCredentials(clients, cfg.ClientTokenExpiration)}
+	if cfg.OIDCEnabled {
+		if cfg.JWTIssuer == "" {
+			return appDeps{}, errors.New("OIDC_ENABLED requires JWT_ISSUER to be set to the API's base URL")
+		}
+		if len(jwtSigner.JWKS().Keys) == 0 {
+			return appDeps{}, errors.New("OIDC_ENABLED requires an RS256 or ES256 signing key, relying parties cannot verify HS256 ID tokens")
+		}
+		if u, err := url.Parse(cfg.OIDCLoginURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return appDeps{}, fmt.Errorf("OIDC_ENABLED requires OIDC_LOGIN_URL to be the absolute URL of the login page, got %q", cfg.OIDCLoginURL)
+		}
+		codes, err := datasource.NewDynamoAuthorizationCodeRepository(ctx, cfg)
+		if err != nil {
+			return appDeps{}, err
+		}
+		oauthOpts = append(oauthOpts, ucase.WithOpenIDConnect(codes, uc, cfg.JWTIssuer, cfg.OIDCLoginURL, cfg.AuthorizationCodeExpiration, cfg.JWTExpiration))
+	}
+	oauthUC := ucase.NewOAuthUseCase(repo, jwtSigner, cfg.IntrospectionClientID, cfg.IntrospectionClientSecret, oauthOpts...)
+	oauthCtrl := controller.NewOAuthController(oauthUC)
 	pres := presenter.NewJSONPresenter()
-	return appDeps{ctrl: ctrl, pres: pres, jwt: jwtSigner}, nil
+	deps := appDeps{
+		ctrl:       ctrl,
+		oauth:      oauthCtrl,
+		pres:       pres,
+		jwt:        jwtSigner,
+		audit:      auditLog,
+		ipLimit:    domain.RateLimit{Burst: cfg.IPRateLimitBurst, Interval: cfg.IPRateLimitInterval},
+		emailLimit: domain.RateLimit{Burst: cfg.EmailRateLimitBurst, Interval: cfg.EmailRateLimitInterval},
+	}
+	switch cfg.RateLimitStore {
+	case "dynamodb":
+		if deps.limiter, err = datasource.NewDynamoRateLimiter(ctx, cfg); err != nil {
+			return appDeps{}, err
+		}
+	case "memory":
+		deps.limiter = datasource.NewMemoryRateLimiter()
+	case "off":
+	default:
+		return appDeps{}, fmt.Errorf("unknown RATE_LIMIT_STORE %q", cfg.RateLimitStore)
+	}
+	return deps, nil
 }
 
 func respond(status int, payload any) (events.APIGatewayProxyResponse, error) {
+	return respondWithHeaders(status, payload, nil)
+}
+
+func respondWithHeaders(status int, payload any, extra map[string]string) (events.APIGatewayProxyResponse, error) {
 	b, _ := json.Marshal(payload)
+	headers := map[string]string{
+		"Content-Type":                 "application/json",
+		"Access-Control-Allow-Origin":  "*",
+		"Access-Control-Allow-Headers": "*",
+	}
+	for k, v := range extra {
+		headers[k] = v
+	}
 	return events.APIGatewayProxyResponse{
 		StatusCode: status,
+		Headers:    headers,
+		Body:       string(b),
+	}, nil
+}
+
+// redirect sends the user agent to location; used by the OpenID Connect authorization
+// endpoint, whose answers carry codes and must not be cached.
+func redirect(location string) (events.APIGatewayProxyResponse, error) {
+	return events.APIGatewayProxyResponse{
+		StatusCode: 302,
+		Headers: map[string]string{
+			"Location":                     location,
+			"Cache-Control":                "no-store",
+			"Access-Control-Allow-Origin":  "*",
+			"Access-Control-Allow-Headers": "*",
+		},
+	}, nil
+}
+
+func respondNoContent() (events.APIGatewayProxyResponse, error) {
+	return events.APIGatewayProxyResponse{
+		StatusCode: 204,
 		Headers: map[string]string{
-			"Content-Type":                 "application/json",
 			"Access-Control-Allow-Origin":  "*",
 			"Access-Control-Allow-Headers": "*",
 		},
-		Body: string(b),
 	}, nil
 }
 
@@ -69,6 +271,35 @@ func parseBody[T any](body string, v *T) error {
 	return dec.Decode(v)
 }
 
+// parseForm decodes an application/x-www-form-urlencoded body, as used by the OAuth endpoints.
+func parseForm(req events.APIGatewayProxyRequest) (url.Values, error) {
+	body := req.Body
+	if req.IsBase64Encoded {
+		b, err := base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return nil, err
+		}
+		body = string(b)
+	}
+	return url.ParseQuery(body)
+}
+
+// clientCredentials reads OAuth client credentials from HTTP Basic auth, falling back to
+// client_id/client_secret form parameters (RFC 6749 section 2.3.1).
+func clientCredentials(req events.APIGatewayProxyRequest, form url.Values) (string, string) {
+	parts := strings.SplitN(req.Headers["Authorization"], " ", 2)
+	if len(parts) == 2 && strings.EqualFold(parts[0], "Basic") {
+		if raw, err := base64.StdEncoding.DecodeString(parts[1]); err == nil {
+			if id, secret, ok := strings.Cut(string(raw), ":"); ok {
+				id, _ = url.QueryUnescape(id)
+				secret, _ = url.QueryUnescape(secret)
+				return id, secret
+			}
+		}
+	}
+	return form.Get("client_id"), form.Get("client_secret")
+}
+
 func extractBearerToken(hdr string) string {
 	parts := strings.SplitN(hdr, " ", 2)
 	if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
@@ -77,6 +308,202 @@ func extractBearerToken(hdr string) string {
 	return ""
 }
 
+// authenticate verifies the bearer token of the request, rejecting tokens issued before
+// the user's last password change and tokens issued to OpenID Connect clients, which are
+// only good for userinfo. Personal access tokens need the users:read scope for GET requests
+// and users:write for the others. On failure it returns the response to send back instead
+// of a principal.
+func authenticate(ctx context.Context, req events.APIGatewayProxyRequest) (*domain.Principal, *events.APIGatewayProxyResponse) {
+	principal, errResp := bearerPrincipal(ctx, req)
+	if errResp != nil {
+		return nil, errResp
+	}
+	if principal.IsDelegated() {
+		resp, _ := respond(403, map[string]string{"error": "forbidden", "details": "token was issued to a client application", "path": req.Path})
+		return nil, &resp
+	}
+	if principal.IsPersonalAccessToken() {
+		scope := domain.ScopeUsersWrite
+		if req.HTTPMethod == "GET" {
+			scope = domain.ScopeUsersRead
+		}
+		if errResp := requireScope(req, principal, scope); errResp != nil {
+			return nil, errResp
+		}
+	}
+	return principal, nil
+}
+
+// requireScope rejects personal access tokens and client tokens that were not granted scope.
+func requireScope(req events.APIGatewayProxyRequest, principal *domain.Principal, scope string) *events.APIGatewayProxyResponse {
+	if !principal.IsPersonalAccessToken() && !principal.IsClient() || principal.HasScope(scope) {
+		return nil
+	}
+	resp, _ := respondWithHeaders(403, map[string]string{"error": "insufficient_scope", "details": "the token lacks the " + scope + " scope", "path": req.Path},
+		map[string]string{"WWW-Authenticate": `Bearer error="insufficient_scope", scope="` + scope + `"`})
+	return &resp
+}
+
+// requireSignIn guards the routes that manage the account's credentials. They need a token
+// from the user's own sign-in: a leaked personal access token must neither take the account
+// over nor mint more tokens, and an admin impersonating the user must not lock them out.
+func requireSignIn(req events.APIGatewayProxyRequest, principal *domain.Principal) *events.APIGatewayProxyResponse {
+	var details string
+	switch {
+	case principal.IsPersonalAccessToken():
+		details = "personal access tokens cannot manage the account's credentials"
+	case principal.IsImpersonated():
+		details = "not allowed while impersonating a user"
+	default:
+		return nil
+	}
+	resp, _ := respond(403, map[string]string{"error": "forbidden", "details": details, "path": req.Path})
+	return &resp
+}
+
+// authorizeRequest runs an OpenID Connect authentication request. On failure it returns the
+// response to send back instead, for the problems that cannot be reported to the client.
+func authorizeRequest(ctx context.Context, req events.APIGatewayProxyRequest, in dto.AuthorizeInput) (*dto.AuthorizeOutput, *events.APIGatewayProxyResponse) {
+	out, err := app.oauth.Authorize(ctx, in)
+	if err == nil {
+		return out, nil
+	}
+	var resp events.APIGatewayProxyResponse
+	switch {
+	case errors.Is(err, ucase.ErrInvalidClient):
+		resp, _ = respond(400, map[string]string{"error": "invalid_request", "details": "unknown client_id", "path": req.Path})
+	case errors.Is(err, ucase.ErrInvalidRedirectURI):
+		resp, _ = respond(400, map[string]string{"error": "invalid_request", "details": "redirect_uri is not registered for the client", "path": req.Path})
+	case errors.Is(err, ucase.ErrOIDCDisabled):
+		resp, _ = respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+	default:
+		resp, _ = respond(500, map[string]string{"error": "internal error", "path": req.Path})
+	}
+	return nil, &resp
+}
+
+// bearerPrincipal is authenticate without the restriction on OpenID Connect client tokens.
+func bearerPrincipal(ctx context.Context, req events.APIGatewayProxyRequest) (*domain.Principal, *events.APIGatewayProxyResponse) {
+	tok := extractBearerToken(req.Headers["Authorization"])
+	if tok == "" {
+		resp, _ := respond(401, map[string]string{"error": "missing bearer token", "details": "Authorization header must be in format 'Bearer <token>'", "path": req.Path})
+		return nil, &resp
+	}
+	principal, err := app.ctrl.Authenticate(ctx, tok)
+	if errors.Is(err, ucase.ErrInvalidToken) {
+		resp, _ := respond(401, map[string]string{"error": "invalid token", "details": err.Error(), "path": req.Path})
+		return nil, &resp
+	}
+	if err != nil {
+		resp, _ := respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		return nil, &resp
+	}
+	if principal.IsImpersonated() {
+		if errResp := auditImpersonatedRequest(ctx, req, principal); errResp != nil {
+			return nil, errResp
+		}
+	}
+	return principal, nil
+}
+
+// auditImpersonatedRequest records a request an admin makes as a user. Requests that cannot
+// be recorded are refused, as are impersonation tokens outliving the feature's enablement.
+func auditImpersonatedRequest(ctx context.Context, req events.APIGatewayProxyRequest, principal *domain.Principal) *events.APIGatewayProxyResponse {
+	if app.audit == nil {
+		resp, _ := respond(401, map[string]string{"error": "invalid token", "details": ucase.ErrImpersonationDisabled.Error(), "path": req.Path})
+		return &resp
+	}
+	err := app.audit.Record(ctx, domain.AuditEvent{
+		Type:        domain.AuditImpersonatedRequest,
+		ActorUserID: principal.ActorUserID,
+		UserID:      principal.UserID,
+		Method:      req.HTTPMethod,
+		Path:        normalizePath(req.Path),
+		At:          time.Now().Unix(),
+	})
+	if err != nil {
+		resp, _ := respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		return &resp
+	}
+	return nil
+}
+
+// clientInfo describes the caller's device for the session a login starts.
+func clientInfo(req events.APIGatewayProxyRequest) dto.ClientInfo {
+	info := dto.ClientInfo{IPAddress: req.RequestContext.Identity.SourceIP}
+	for k, v := range req.Headers {
+		if strings.EqualFold(k, "User-Agent") {
+			info.UserAgent = v
+			break
+		}
+	}
+	return info
+}
+
+func newPasswordHasher(cfg *config.Config) (port.PasswordHasher, error) {
+	switch cfg.PasswordHashAlgorithm {
+	case "argon2id":
+		if cfg.Argon2Memory <= 0 || cfg.Argon2Iterations <= 0 || cfg.Argon2Parallelism <= 0 || cfg.Argon2Parallelism > 255 {
+			return nil, fmt.Errorf("invalid argon2id parameters m=%d t=%d p=%d", cfg.Argon2Memory, cfg.Argon2Iterations, cfg.Argon2Parallelism)
+		}
+		return auth.NewArgon2idHasher(uint32(cfg.Argon2Memory), uint32(cfg.Argon2Iterations), uint8(cfg.Argon2Parallelism))
+	case "bcrypt":
+		return auth.NewBcryptHasher(cfg.BcryptCost)
+	default:
+		return nil, fmt.Errorf("unknown PASSWORD_HASH_ALGORITHM %q", cfg.PasswordHashAlgorithm)
+	}
+}
+
+// weakPassword builds the 400 response listing the password policy rules err reports as
+// broken, if it is a policy error.
+func weakPassword(req events.APIGatewayProxyRequest, err error) (events.APIGatewayProxyResponse, bool) {
+	var policyErr *ucase.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return events.APIGatewayProxyResponse{}, false
+	}
+	resp, _ := respond(400, map[string]any{
+		"error":      ucase.ErrWeakPassword.Error(),
+		"violations": policyErr.Violations,
+		"path":       req.Path,
+	})
+	return resp, true
+}
+
+// rateLimit takes a token from the client IP's bucket of the endpoint and, when email is
+// set, from that email's bucket. It returns the 429 response to send back when either is
+// empty. Limiter failures let the request through: an outage of the bucket store must not
+// take logins down with it.
+func rateLimit(ctx context.Context, req events.APIGatewayProxyRequest, endpoint, email string) *events.APIGatewayProxyResponse {
+	if app.limiter == nil {
+		return nil
+	}
+	ip := req.RequestContext.Identity.SourceIP
+	if ip == "" {
+		ip = "unknown"
+	}
+	buckets := []struct {
+		key   string
+		limit domain.RateLimit
+	}{{"ip:" + endpoint + ":" + ip, app.ipLimit}}
+	if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+		buckets = append(buckets, struct {
+			key   string
+			limit domain.RateLimit
+		}{"email:" + endpoint + ":" + email, app.emailLimit})
+	}
+	for _, b := range buckets {
+		allowed, retryAfter, err := app.limiter.Allow(ctx, b.key, b.limit)
+		if err != nil || allowed {
+			continue
+		}
+		seconds := int64(math.Ceil(retryAfter.Seconds()))
+		resp, _ := respondWithHeaders(429, map[string]string{"error": "too many requests", "path": req.Path},
+			map[string]string{"Retry-After": strconv.FormatInt(max(seconds, 1), 10)})
+		return &resp
+	}
+	return nil
+}
+
 func normalizePath(p string) string {
 	if p == "" {
 		return p
@@ -98,13 +525,183 @@ func handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.API
 	}
 
 	switch {
+	case req.HTTPMethod == "GET" && normalizePath(req.Path) == "/.well-known/jwks.json":
+		b, err := app.pres.Present(app.jwt.JWKS())
+		if err != nil {
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respond(200, out)
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/oauth/token":
+		form, err := parseForm(req)
+		if err != nil {
+			return respond(400, map[string]string{"error": "invalid_request", "details": err.Error(), "path": req.Path})
+		}
+		id, secret := clientCredentials(req, form)
+		in := dto.TokenInput{
+			ClientID:     id,
+			ClientSecret: secret,
+			GrantType:    form.Get("grant_type"),
+			Scope:        form.Get("scope"),
+			Code:         form.Get("code"),
+			RedirectURI:  form.Get("redirect_uri"),
+			CodeVerifier: form.Get("code_verifier"),
+		}
+		b, err := app.oauth.Token(ctx, app.pres, in)
+		if err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInvalidClient):
+				return respondWithHeaders(401, map[string]string{"error": "invalid_client", "path": req.Path}, map[string]string{"WWW-Authenticate": `Basic realm="oauth"`})
+			case errors.Is(err, ucase.ErrInvalidInput):
+				details := "grant_type is required"
+				if in.GrantType != "" {
+					details = "code, redirect_uri and code_verifier are required"
+				}
+				return respond(400, map[string]string{"error": "invalid_request", "details": details, "path": req.Path})
+			case errors.Is(err, ucase.ErrUnsupportedGrantType):
+				return respond(400, map[string]string{"error": "unsupported_grant_type", "path": req.Path})
+			case errors.Is(err, ucase.ErrInvalidScope):
+				return respond(400, map[string]string{"error": "invalid_scope", "path": req.Path})
+			case errors.Is(err, ucase.ErrInvalidGrant):
+				return respond(400, map[string]string{"error": "invalid_grant", "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		// RFC 6749 section 5.1: token responses must not be cached
+		return respondWithHeaders(200, out, map[string]string{"Cache-Control": "no-store", "Pragma": "no-cache"})
+
+	case req.HTTPMethod == "GET" && normalizePath(req.Path) == "/.well-known/openid-configuration":
+		b, err := app.oauth.Discovery(ctx, app.pres)
+		if err != nil {
+			if errors.Is(err, ucase.ErrOIDCDisabled) {
+				return respond(404, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respond(200, out)
+
+	case req.HTTPMethod == "GET" && normalizePath(req.Path) == "/oauth/authorize":
+		// browsers arrive here without a token; the login page signs the user in
+		q := req.QueryStringParameters
+		out, errResp := authorizeRequest(ctx, req, dto.AuthorizeInput{
+			ResponseType:        q["response_type"],
+			ClientID:            q["client_id"],
+			RedirectURI:         q["redirect_uri"],
+			Scope:               q["scope"],
+			State:               q["state"],
+			Nonce:               q["nonce"],
+			CodeChallenge:       q["code_challenge"],
+			CodeChallengeMethod: q["code_challenge_method"],
+			Prompt:              q["prompt"],
+		})
+		if errResp != nil {
+			return *errResp, nil
+		}
+		return redirect(out.RedirectURI)
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/oauth/authorize":
+		// the login page posts the request back with the user's token and consent answer
+		form, err := parseForm(req)
+		if err != nil {
+			return respond(400, map[string]string{"error": "invalid_request", "details": err.Error(), "path": req.Path})
+		}
+		principal, errResp := authenticate(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if principal.IsClient() {
+			return respond(403, map[string]string{"error": "forbidden", "details": "client tokens do not identify a user", "path": req.Path})
+		}
+		if errResp := requireSignIn(req, principal); errResp != nil {
+			return *errResp, nil
+		}
+		out, errResp := authorizeRequest(ctx, req, dto.AuthorizeInput{
+			UserID:              principal.UserID,
+			AuthTime:            principal.IssuedAt,
+			Consent:             form.Get("consent") == "approve",
+			ResponseType:        form.Get("response_type"),
+			ClientID:            form.Get("client_id"),
+			RedirectURI:         form.Get("redirect_uri"),
+			Scope:               form.Get("scope"),
+			State:               form.Get("state"),
+			Nonce:               form.Get("nonce"),
+			CodeChallenge:       form.Get("code_challenge"),
+			CodeChallengeMethod: form.Get("code_challenge_method"),
+		})
+		if errResp != nil {
+			return *errResp, nil
+		}
+		return respondWithHeaders(200, map[string]string{"redirect_uri": out.RedirectURI}, map[string]string{"Cache-Control": "no-store"})
+
+	case (req.HTTPMethod == "GET" || req.HTTPMethod == "POST") && normalizePath(req.Path) == "/oauth/userinfo":
+		principal, errResp := bearerPrincipal(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if errResp := requireScope(req, principal, domain.ScopeUsersRead); errResp != nil {
+			return *errResp, nil
+		}
+		b, err := app.oauth.UserInfo(ctx, app.pres, *principal)
+		if err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInsufficientScope):
+				return respondWithHeaders(403, map[string]string{"error": "insufficient_scope", "path": req.Path},
+					map[string]string{"WWW-Authenticate": `Bearer error="insufficient_scope", scope="openid"`})
+			case errors.Is(err, ucase.ErrUserNotFound):
+				return respond(401, map[string]string{"error": "invalid_token", "path": req.Path})
+			case errors.Is(err, ucase.ErrOIDCDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respondWithHeaders(200, out, map[string]string{"Cache-Control": "no-store"})
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/oauth/introspect":
+		form, err := parseForm(req)
+		if err != nil {
+			return respond(400, map[string]string{"error": "invalid_request", "details": err.Error(), "path": req.Path})
+		}
+		id, secret := clientCredentials(req, form)
+		b, err := app.oauth.Introspect(ctx, app.pres, dto.IntrospectInput{
+			ClientID:      id,
+			ClientSecret:  secret,
+			Token:         form.Get("token"),
+			TokenTypeHint: form.Get("token_type_hint"),
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInvalidClient):
+				return respondWithHeaders(401, map[string]string{"error": "invalid_client", "path": req.Path}, map[string]string{"WWW-Authenticate": `Basic realm="oauth"`})
+			case errors.Is(err, ucase.ErrInvalidInput):
+				return respond(400, map[string]string{"error": "invalid_request", "details": "token is required", "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respond(200, out)
+
 	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/register":
 		var in dto.RegisterInput
 		if err := parseBody(req.Body, &in); err != nil {
 			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
 		}
+		if resp := rateLimit(ctx, req, "register", in.Email); resp != nil {
+			return *resp, nil
+		}
 		b, err := app.ctrl.Register(ctx, app.pres, in)
 		if err != nil {
+			if resp, ok := weakPassword(req, err); ok {
+				return resp, nil
+			}
 			status := 400
 			if errors.Is(err, ucase.ErrEmailAlreadyExists) {
 				status = 409
@@ -120,11 +717,21 @@ func handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.API
 		if err := parseBody(req.Body, &in); err != nil {
 			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
 		}
+		in.Client = clientInfo(req)
+		if resp := rateLimit(ctx, req, "login", in.Email); resp != nil {
+			return *resp, nil
+		}
 		b, err := app.ctrl.Login(ctx, app.pres, in)
 		if err != nil {
 			status := 400
 			if errors.Is(err, ucase.ErrInvalidCredentials) || errors.Is(err, ucase.ErrInvalidInput) {
 				status = 401
+			} else if errors.Is(err, ucase.ErrEmailNotVerified) {
+				status = 403
+			} else if errors.Is(err, ucase.ErrAccountLocked) {
+				status = 423
+			} else if errors.Is(err, ucase.ErrMFADisabled) {
+				return respond(500, map[string]string{"error": "internal error", "path": req.Path})
 			}
 			return respond(status, map[string]string{"error": err.Error(), "path": req.Path})
 		}
@@ -132,17 +739,638 @@ func handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.API
 		_ = json.Unmarshal(b, &out)
 		return respond(200, out)
 
-	case req.HTTPMethod == "GET" && normalizePath(req.Path) == "/users/me":
-		auth := req.Headers["Authorization"]
-		tok := extractBearerToken(auth)
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/login/mfa":
+		var in dto.LoginMFAInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		in.Client = clientInfo(req)
+		b, err := app.ctrl.LoginMFA(ctx, app.pres, in)
+		if err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput):
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrInvalidMFAToken) || errors.Is(err, ucase.ErrInvalidMFACode):
+				return respond(401, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrAccountLocked):
+				return respond(423, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrMFADisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respond(200, out)
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/login/magic-link":
+		var in dto.MagicLinkInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		if resp := rateLimit(ctx, req, "magic_link", in.Email); resp != nil {
+			return *resp, nil
+		}
+		if err := app.ctrl.RequestMagicLink(ctx, in); err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput):
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrMagicLinkDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		return respond(202, map[string]string{"message": "if the email is registered, a login link has been sent"})
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/login/magic-link/consume":
+		var in dto.ConsumeMagicLinkInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		in.Client = clientInfo(req)
+		b, err := app.ctrl.ConsumeMagicLink(ctx, app.pres, in)
+		if err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput):
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrInvalidMagicLink):
+				return respond(401, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrAccountLocked):
+				return respond(423, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrMagicLinkDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respond(200, out)
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/passkeys/login/options":
+		b, err := app.ctrl.BeginPasskeyLogin(ctx, app.pres)
+		if err != nil {
+			if errors.Is(err, ucase.ErrPasskeysDisabled) {
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respondWithHeaders(200, out, map[string]string{"Cache-Control": "no-store"})
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/passkeys/login":
+		var in dto.FinishPasskeyLoginInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		in.Client = clientInfo(req)
+		b, err := app.ctrl.FinishPasskeyLogin(ctx, app.pres, in)
+		if err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput):
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrInvalidPasskey) || errors.Is(err, ucase.ErrPasskeyCloned):
+				return respond(401, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrEmailNotVerified):
+				return respond(403, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrAccountLocked):
+				return respond(423, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrPasskeysDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respond(200, out)
+
+	case req.HTTPMethod == "POST" && strings.HasPrefix(normalizePath(req.Path), "/users/login/federated/") &&
+		strings.HasSuffix(normalizePath(req.Path), "/callback"):
+		var in dto.FinishFederatedLoginInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		in.Provider = strings.TrimSuffix(strings.TrimPrefix(normalizePath(req.Path), "/users/login/federated/"), "/callback")
+		in.Client = clientInfo(req)
+		b, err := app.ctrl.FinishFederatedLogin(ctx, app.pres, in)
+		if err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput):
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrInvalidFederatedLogin):
+				// the wrapped cause comes from the provider exchange and is not for the caller
+				return respond(401, map[string]string{"error": ucase.ErrInvalidFederatedLogin.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrFederatedEmailNotVerified):
+				return respond(403, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrUnknownIdentityProvider):
+				return respond(404, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrFederatedAccountConflict):
+				return respond(409, map[string]string{"error": err.Error(), "details": "verify the email address of the existing account first", "path": req.Path})
+			case errors.Is(err, ucase.ErrAccountLocked):
+				return respond(423, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrFederatedLoginDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respond(200, out)
+
+	case req.HTTPMethod == "POST" && strings.HasPrefix(normalizePath(req.Path), "/users/login/federated/"):
+		if resp := rateLimit(ctx, req, "federated_login", ""); resp != nil {
+			return *resp, nil
+		}
+		in := dto.BeginFederatedLoginInput{Provider: strings.TrimPrefix(normalizePath(req.Path), "/users/login/federated/")}
+		b, err := app.ctrl.BeginFederatedLogin(ctx, app.pres, in)
+		if err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrUnknownIdentityProvider):
+				return respond(404, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrFederatedLoginDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respondWithHeaders(200, out, map[string]string{"Cache-Control": "no-store"})
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/token/refresh":
+		var in dto.RefreshInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		b, err := app.ctrl.Refresh(ctx, app.pres, in)
+		if err != nil {
+			status := 400
+			if errors.Is(err, ucase.ErrInvalidRefreshToken) || errors.Is(err, ucase.ErrRefreshTokenReused) {
+				status = 401
+			}
+			return respond(status, map[string]string{"error": err.Error(), "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respond(200, out)
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/logout":
+		tok := extractBearerToken(req.Headers["Authorization"])
 		if tok == "" {
 			return respond(401, map[string]string{"error": "missing bearer token", "details": "Authorization header must be in format 'Bearer <token>'", "path": req.Path})
 		}
-		userID, err := app.jwt.Verify(tok)
+		in := dto.LogoutInput{}
+		if strings.TrimSpace(req.Body) != "" {
+			if err := parseBody(req.Body, &in); err != nil {
+				return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+			}
+		}
+		in.AccessToken = tok
+		if err := app.ctrl.Logout(ctx, in); err != nil {
+			status := 500
+			switch {
+			case errors.Is(err, ucase.ErrPersonalAccessTokenLogout):
+				status = 400
+			case errors.Is(err, ucase.ErrInvalidToken) || errors.Is(err, ucase.ErrInvalidInput):
+				status = 401
+			}
+			return respond(status, map[string]string{"error": err.Error(), "path": req.Path})
+		}
+		return respondNoContent()
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/password/forgot":
+		var in dto.ForgotPasswordInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		if resp := rateLimit(ctx, req, "forgot_password", in.Email); resp != nil {
+			return *resp, nil
+		}
+		if err := app.ctrl.ForgotPassword(ctx, in); err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput):
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrPasswordResetDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		return respond(202, map[string]string{"message": "if the email is registered, a reset link has been sent"})
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/password/reset":
+		var in dto.ResetPasswordInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		if err := app.ctrl.ResetPassword(ctx, in); err != nil {
+			if resp, ok := weakPassword(req, err); ok {
+				return resp, nil
+			}
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput) || errors.Is(err, ucase.ErrInvalidResetToken):
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrPasswordResetDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		return respondNoContent()
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/me/password":
+		principal, errResp := authenticate(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if principal.IsClient() {
+			return respond(403, map[string]string{"error": "forbidden", "details": "client tokens do not identify a user", "path": req.Path})
+		}
+		if errResp := requireSignIn(req, principal); errResp != nil {
+			return *errResp, nil
+		}
+		if resp := rateLimit(ctx, req, "change_password", principal.Email); resp != nil {
+			return *resp, nil
+		}
+		var in dto.ChangePasswordInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		in.UserID = principal.UserID
+		if err := app.ctrl.ChangePassword(ctx, in); err != nil {
+			if resp, ok := weakPassword(req, err); ok {
+				return resp, nil
+			}
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput):
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrInvalidCredentials):
+				return respond(403, map[string]string{"error": "current password is incorrect", "path": req.Path})
+			case errors.Is(err, ucase.ErrUserNotFound):
+				return respond(404, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrAccountLocked):
+				return respond(423, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		return respondNoContent()
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/me/email":
+		principal, errResp := authenticate(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if principal.IsClient() {
+			return respond(403, map[string]string{"error": "forbidden", "details": "client tokens do not identify a user", "path": req.Path})
+		}
+		if errResp := requireSignIn(req, principal); errResp != nil {
+			return *errResp, nil
+		}
+		if resp := rateLimit(ctx, req, "change_email", principal.Email); resp != nil {
+			return *resp, nil
+		}
+		var in dto.ChangeEmailInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		in.UserID = principal.UserID
+		if err := app.ctrl.RequestEmailChange(ctx, in); err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput):
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrInvalidCredentials):
+				return respond(403, map[string]string{"error": "current password is incorrect", "path": req.Path})
+			case errors.Is(err, ucase.ErrUserNotFound):
+				return respond(404, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrEmailAlreadyExists) || errors.Is(err, ucase.ErrEmailChangeConflict):
+				return respond(409, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrAccountLocked):
+				return respond(423, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrEmailChangeDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		return respond(202, map[string]string{"message": "a confirmation link has been sent to the new address"})
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/email/change/confirm":
+		var in dto.ConfirmEmailChangeInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		if err := app.ctrl.ConfirmEmailChange(ctx, in); err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput) || errors.Is(err, ucase.ErrInvalidEmailChangeToken):
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrEmailAlreadyExists):
+				return respond(409, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrEmailChangeDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		return respondNoContent()
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/email/verify":
+		var in dto.VerifyEmailInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		if err := app.ctrl.VerifyEmail(ctx, in); err != nil {
+			if errors.Is(err, ucase.ErrInvalidInput) || errors.Is(err, ucase.ErrInvalidVerificationToken) {
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		return respondNoContent()
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/email/verify/resend":
+		var in dto.ResendVerificationInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		if err := app.ctrl.ResendVerification(ctx, in); err != nil {
+			if errors.Is(err, ucase.ErrInvalidInput) {
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		return respond(202, map[string]string{"message": "if the email awaits verification, a new link has been sent"})
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/me/mfa/totp":
+		principal, errResp := authenticate(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if principal.IsClient() {
+			return respond(403, map[string]string{"error": "forbidden", "details": "client tokens do not identify a user", "path": req.Path})
+		}
+		if errResp := requireSignIn(req, principal); errResp != nil {
+			return *errResp, nil
+		}
+		b, err := app.ctrl.EnrollTOTP(ctx, app.pres, principal.UserID)
+		if err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrUserNotFound):
+				return respond(404, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrMFAAlreadyEnabled):
+				return respond(409, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrMFADisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respondWithHeaders(200, out, map[string]string{"Cache-Control": "no-store"})
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/me/mfa/totp/confirm":
+		principal, errResp := authenticate(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if principal.IsClient() {
+			return respond(403, map[string]string{"error": "forbidden", "details": "client tokens do not identify a user", "path": req.Path})
+		}
+		if errResp := requireSignIn(req, principal); errResp != nil {
+			return *errResp, nil
+		}
+		var in dto.ConfirmTOTPInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		in.UserID = principal.UserID
+		if err := app.ctrl.ConfirmTOTP(ctx, in); err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput) || errors.Is(err, ucase.ErrInvalidMFACode) || errors.Is(err, ucase.ErrMFANotEnrolled):
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrUserNotFound):
+				return respond(404, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrMFAAlreadyEnabled):
+				return respond(409, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrMFADisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		return respondNoContent()
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/me/passkeys/register/options":
+		principal, errResp := authenticate(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if principal.IsClient() {
+			return respond(403, map[string]string{"error": "forbidden", "details": "client tokens do not identify a user", "path": req.Path})
+		}
+		if errResp := requireSignIn(req, principal); errResp != nil {
+			return *errResp, nil
+		}
+		b, err := app.ctrl.BeginPasskeyRegistration(ctx, app.pres, principal.UserID)
+		if err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrUserNotFound):
+				return respond(404, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrPasskeysDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respondWithHeaders(200, out, map[string]string{"Cache-Control": "no-store"})
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/me/passkeys/register":
+		principal, errResp := authenticate(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if principal.IsClient() {
+			return respond(403, map[string]string{"error": "forbidden", "details": "client tokens do not identify a user", "path": req.Path})
+		}
+		if errResp := requireSignIn(req, principal); errResp != nil {
+			return *errResp, nil
+		}
+		var in dto.FinishPasskeyRegistrationInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		in.UserID = principal.UserID
+		b, err := app.ctrl.FinishPasskeyRegistration(ctx, app.pres, in)
 		if err != nil {
-			return respond(401, map[string]string{"error": "invalid token", "details": err.Error(), "path": req.Path})
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput) || errors.Is(err, ucase.ErrInvalidPasskey):
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrPasskeyExists):
+				return respond(409, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrPasskeysDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respond(201, out)
+
+	case req.HTTPMethod == "GET" && normalizePath(req.Path) == "/users/me/sessions":
+		principal, errResp := authenticate(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if principal.IsClient() {
+			return respond(403, map[string]string{"error": "forbidden", "details": "client tokens do not identify a user", "path": req.Path})
 		}
-		b, err := app.ctrl.GetMe(ctx, app.pres, userID)
+		b, err := app.ctrl.ListSessions(ctx, app.pres, dto.ListSessionsInput{UserID: principal.UserID, CurrentSessionID: principal.SessionID})
+		if err != nil {
+			if errors.Is(err, ucase.ErrSessionsDisabled) {
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respondWithHeaders(200, out, map[string]string{"Cache-Control": "no-store"})
+
+	case req.HTTPMethod == "DELETE" && strings.HasPrefix(normalizePath(req.Path), "/users/me/sessions/"):
+		principal, errResp := authenticate(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if principal.IsClient() {
+			return respond(403, map[string]string{"error": "forbidden", "details": "client tokens do not identify a user", "path": req.Path})
+		}
+		in := dto.RevokeSessionInput{UserID: principal.UserID, SessionID: strings.TrimPrefix(normalizePath(req.Path), "/users/me/sessions/")}
+		if err := app.ctrl.RevokeSession(ctx, in); err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput):
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrSessionNotFound):
+				return respond(404, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrSessionsDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		return respondNoContent()
+
+	case req.HTTPMethod == "POST" && normalizePath(req.Path) == "/users/me/tokens":
+		principal, errResp := authenticate(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if principal.IsClient() {
+			return respond(403, map[string]string{"error": "forbidden", "details": "client tokens do not identify a user", "path": req.Path})
+		}
+		if errResp := requireSignIn(req, principal); errResp != nil {
+			return *errResp, nil
+		}
+		var in dto.CreatePersonalAccessTokenInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		in.UserID = principal.UserID
+		b, err := app.ctrl.CreatePersonalAccessToken(ctx, app.pres, in)
+		if err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput), errors.Is(err, ucase.ErrUnknownScope), errors.Is(err, ucase.ErrTokenLifetimeTooLong):
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrPersonalAccessTokensDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respondWithHeaders(201, out, map[string]string{"Cache-Control": "no-store"})
+
+	case req.HTTPMethod == "GET" && normalizePath(req.Path) == "/users/me/tokens":
+		principal, errResp := authenticate(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if principal.IsClient() {
+			return respond(403, map[string]string{"error": "forbidden", "details": "client tokens do not identify a user", "path": req.Path})
+		}
+		b, err := app.ctrl.ListPersonalAccessTokens(ctx, app.pres, principal.UserID)
+		if err != nil {
+			if errors.Is(err, ucase.ErrPersonalAccessTokensDisabled) {
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respondWithHeaders(200, out, map[string]string{"Cache-Control": "no-store"})
+
+	case req.HTTPMethod == "DELETE" && strings.HasPrefix(normalizePath(req.Path), "/users/me/tokens/"):
+		principal, errResp := authenticate(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if principal.IsClient() {
+			return respond(403, map[string]string{"error": "forbidden", "details": "client tokens do not identify a user", "path": req.Path})
+		}
+		in := dto.RevokePersonalAccessTokenInput{UserID: principal.UserID, TokenID: strings.TrimPrefix(normalizePath(req.Path), "/users/me/tokens/")}
+		if err := app.ctrl.RevokePersonalAccessToken(ctx, in); err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput):
+				return respond(400, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrPersonalAccessTokenNotFound):
+				return respond(404, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrPersonalAccessTokensDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		return respondNoContent()
+
+	case req.HTTPMethod == "POST" && strings.HasPrefix(normalizePath(req.Path), "/admin/users/") &&
+		strings.HasSuffix(normalizePath(req.Path), "/impersonate"):
+		principal, errResp := authenticate(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if principal.IsClient() {
+			return respond(403, map[string]string{"error": "forbidden", "details": "client tokens do not identify a user", "path": req.Path})
+		}
+		if errResp := requireSignIn(req, principal); errResp != nil {
+			return *errResp, nil
+		}
+		rawID := strings.TrimSuffix(strings.TrimPrefix(normalizePath(req.Path), "/admin/users/"), "/impersonate")
+		userID, err := strconv.ParseInt(rawID, 10, 64)
+		if err != nil {
+			return respond(400, map[string]string{"error": "invalid user id", "details": err.Error(), "path": req.Path})
+		}
+		var in dto.ImpersonateInput
+		if err := parseBody(req.Body, &in); err != nil {
+			return respond(400, map[string]string{"error": "invalid body", "details": err.Error(), "path": req.Path})
+		}
+		in.AdminID = principal.UserID
+		in.UserID = userID
+		b, err := app.ctrl.Impersonate(ctx, app.pres, in)
+		if err != nil {
+			switch {
+			case errors.Is(err, ucase.ErrInvalidInput):
+				return respond(400, map[string]string{"error": err.Error(), "details": "reason is required", "path": req.Path})
+			case errors.Is(err, ucase.ErrAdminRequired), errors.Is(err, ucase.ErrCannotImpersonate):
+				return respond(403, map[string]string{"error": "forbidden", "details": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrUserNotFound):
+				return respond(404, map[string]string{"error": err.Error(), "path": req.Path})
+			case errors.Is(err, ucase.ErrImpersonationDisabled):
+				return respond(501, map[string]string{"error": err.Error(), "path": req.Path})
+			}
+			return respond(500, map[string]string{"error": "internal error", "path": req.Path})
+		}
+		var out any
+		_ = json.Unmarshal(b, &out)
+		return respondWithHeaders(200, out, map[string]string{"Cache-Control": "no-store"})
+
+	case req.HTTPMethod == "GET" && normalizePath(req.Path) == "/users/me":
+		principal, errResp := authenticate(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if principal.IsClient() {
+			return respond(403, map[string]string{"error": "forbidden", "details": "client tokens do not identify a user", "path": req.Path})
+		}
+		b, err := app.ctrl.GetMe(ctx, app.pres, principal.UserID)
 		if err != nil {
 			status := 400
 			if errors.Is(err, ucase.ErrUserNotFound) {
@@ -167,6 +1395,17 @@ func handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.API
 		if err != nil {
 			return respond(400, map[string]string{"error": "invalid user id", "details": err.Error(), "path": req.Path})
 		}
+		// services such as the video-processing workers look users up with a client token
+		principal, errResp := authenticate(ctx, req)
+		if errResp != nil {
+			return *errResp, nil
+		}
+		if !principal.IsClient() {
+			return respond(403, map[string]string{"error": "forbidden", "details": "only client tokens may look up users by ID", "path": req.Path})
+		}
+		if errResp := requireScope(req, principal, domain.ScopeUsersRead); errResp != nil {
+			return *errResp, nil
+		}
 		b, err := app.ctrl.GetUserByID(ctx, app.pres, userID)
 		if err != nil {
 			status := 400
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+	mockport "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port/mocks"
+)
+
+func TestRateLimit(t *testing.T) {
+	ctx := context.Background()
+	ipLimit := domain.RateLimit{Burst: 20, Interval: 6 * time.Second}
+	emailLimit := domain.RateLimit{Burst: 5, Interval: time.Minute}
+	req := events.APIGatewayProxyRequest{
+		Path:           "/users/login",
+		RequestContext: events.APIGatewayProxyRequestContext{Identity: events.APIGatewayRequestIdentity{SourceIP: "1.2.3.4"}},
+	}
+
+	tests := []struct {
+		name        string
+		email       string
+		setupMocks  func(*mockport.MockRateLimiter)
+		checkResult func(*testing.T, *events.APIGatewayProxyResponse)
+	}{
+		{
+			name:  "should let requests within both limits through",
+			email: " John@Example.com ",
+			setupMocks: func(l *mockport.MockRateLimiter) {
+				l.EXPECT().Allow(ctx, "ip:login:1.2.3.4", ipLimit).Return(true, time.Duration(0), nil)
+				l.EXPECT().Allow(ctx, "email:login:john@example.com", emailLimit).Return(true, time.Duration(0), nil)
+			},
+			checkResult: func(t *testing.T, resp *events.APIGatewayProxyResponse) {
+				assert.Nil(t, resp)
+			},
+		},
+		{
+			name: "should answer 429 with Retry-After rounded up to whole seconds",
+			setupMocks: func(l *mockport.MockRateLimiter) {
+				l.EXPECT().Allow(ctx, "ip:login:1.2.3.4", ipLimit).Return(false, 2500*time.Millisecond, nil)
+			},
+			checkResult: func(t *testing.T, resp *events.APIGatewayProxyResponse) {
+				assert.NotNil(t, resp)
+				assert.Equal(t, 429, resp.StatusCode)
+				assert.Equal(t, "3", resp.Headers["Retry-After"])
+				assert.JSONEq(t, `{"error":"too many requests","path":"/users/login"}`, resp.Body)
+			},
+		},
+		{
+			name:  "should limit by email once the IP is within its limit",
+			email: "john@example.com",
+			setupMocks: func(l *mockport.MockRateLimiter) {
+				l.EXPECT().Allow(ctx, "ip:login:1.2.3.4", ipLimit).Return(true, time.Duration(0), nil)
+				l.EXPECT().Allow(ctx, "email:login:john@example.com", emailLimit).Return(false, 40*time.Second, nil)
+			},
+			checkResult: func(t *testing.T, resp *events.APIGatewayProxyResponse) {
+				assert.Equal(t, 429, resp.StatusCode)
+				assert.Equal(t, "40", resp.Headers["Retry-After"])
+			},
+		},
+		{
+			name: "should ask to retry after at least one second",
+			setupMocks: func(l *mockport.MockRateLimiter) {
+				l.EXPECT().Allow(ctx, "ip:login:1.2.3.4", ipLimit).Return(false, time.Duration(0), nil)
+			},
+			checkResult: func(t *testing.T, resp *events.APIGatewayProxyResponse) {
+				assert.Equal(t, 429, resp.StatusCode)
+				assert.Equal(t, "1", resp.Headers["Retry-After"])
+			},
+		},
+		{
+			name:  "should fail open when the limiter errors",
+			email: "john@example.com",
+			setupMocks: func(l *mockport.MockRateLimiter) {
+				l.EXPECT().Allow(ctx, "ip:login:1.2.3.4", ipLimit).Return(false, time.Duration(0), assert.AnError)
+				l.EXPECT().Allow(ctx, "email:login:john@example.com", emailLimit).Return(true, time.Duration(0), nil)
+			},
+			checkResult: func(t *testing.T, resp *events.APIGatewayProxyResponse) {
+				assert.Nil(t, resp)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			limiter := mockport.NewMockRateLimiter(ctrl)
+			tt.setupMocks(limiter)
+			app = appDeps{limiter: limiter, ipLimit: ipLimit, emailLimit: emailLimit}
+			defer func() { app = appDeps{} }()
+
+			// Act
+			resp := rateLimit(ctx, req, "login", tt.email)
+
+			// Assert
+			tt.checkResult(t, resp)
+		})
+	}
+}
+
+func TestRateLimit_Disabled(t *testing.T) {
+	app = appDeps{}
+	assert.Nil(t, rateLimit(context.Background(), events.APIGatewayProxyRequest{}, "login", "john@example.com"))
+}
+
+func TestGetUserByID_RequiresClientToken(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		auth        string
+		setupMocks  func(*mockport.MockUserController)
+		checkResult func(*testing.T, events.APIGatewayProxyResponse)
+	}{
+		{
+			name: "should reject a request without a token",
+			setupMocks: func(c *mockport.MockUserController) {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, resp events.APIGatewayProxyResponse) {
+				assert.Equal(t, 401, resp.StatusCode)
+			},
+		},
+		{
+			name: "should reject a user token",
+			auth: "Bearer user-token",
+			setupMocks: func(c *mockport.MockUserController) {
+				c.EXPECT().Authenticate(ctx, "user-token").Return(&domain.Principal{UserID: 1}, nil)
+			},
+			checkResult: func(t *testing.T, resp events.APIGatewayProxyResponse) {
+				assert.Equal(t, 403, resp.StatusCode)
+			},
+		},
+		{
+			name: "should reject a client token without the users:read scope",
+			auth: "Bearer client-token",
+			setupMocks: func(c *mockport.MockUserController) {
+				c.EXPECT().Authenticate(ctx, "client-token").
+					Return(&domain.Principal{SubjectType: domain.SubjectTypeClient, ClientID: "video-worker", Scopes: []string{"videos:write"}}, nil)
+			},
+			checkResult: func(t *testing.T, resp events.APIGatewayProxyResponse) {
+				assert.Equal(t, 403, resp.StatusCode)
+				assert.Contains(t, resp.Body, "insufficient_scope")
+			},
+		},
+		{
+			name: "should return the user to a client token with the users:read scope",
+			auth: "Bearer client-token",
+			setupMocks: func(c *mockport.MockUserController) {
+				c.EXPECT().Authenticate(ctx, "client-token").
+					Return(&domain.Principal{SubjectType: domain.SubjectTypeClient, ClientID: "video-worker", Scopes: []string{domain.ScopeUsersRead}}, nil)
+				c.EXPECT().GetUserByID(ctx, gomock.Any(), int64(7)).
+					Return([]byte(`{"id":7,"email":"john@example.com","name":"John Doe"}`), nil)
+			},
+			checkResult: func(t *testing.T, resp events.APIGatewayProxyResponse) {
+				assert.Equal(t, 200, resp.StatusCode)
+				assert.JSONEq(t, `{"id":7,"email":"john@example.com","name":"John Doe"}`, resp.Body)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			userCtrl := mockport.NewMockUserController(ctrl)
+			tt.setupMocks(userCtrl)
+			app = appDeps{ctrl: userCtrl}
+			defer func() { app = appDeps{} }()
+			req := events.APIGatewayProxyRequest{HTTPMethod: "POST", Path: "/users/7", Headers: map[string]string{}}
+			if tt.auth != "" {
+				req.Headers["Authorization"] = tt.auth
+			}
+
+			// Act
+			resp, err := handler(ctx, req)
+
+			// Assert
+			assert.NoError(t, err)
+			tt.checkResult(t, resp)
+		})
+	}
+}
+
+func TestAuthorize_SendsBrowsersToTheLoginPage(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	oauthCtrl := mockport.NewMockOAuthController(ctrl)
+	oauthCtrl.EXPECT().
+		Authorize(ctx, dto.AuthorizeInput{ResponseType: "code", ClientID: "partner-app", Prompt: "login"}).
+		Return(&dto.AuthorizeOutput{RedirectURI: "https://login.example.com/authorize?client_id=partner-app"}, nil)
+	app = appDeps{ctrl: mockport.NewMockUserController(ctrl), oauth: oauthCtrl}
+	defer func() { app = appDeps{} }()
+	req := events.APIGatewayProxyRequest{
+		HTTPMethod:            "GET",
+		Path:                  "/oauth/authorize",
+		QueryStringParameters: map[string]string{"response_type": "code", "client_id": "partner-app", "prompt": "login"},
+	}
+
+	// Act
+	resp, err := handler(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 302, resp.StatusCode)
+	assert.Equal(t, "https://login.example.com/authorize?client_id=partner-app", resp.Headers["Location"])
+	assert.Equal(t, "no-store", resp.Headers["Cache-Control"])
+}
+
+func TestAuthorize_Consent(t *testing.T) {
+	ctx := context.Background()
+	body := "response_type=code&client_id=partner-app&redirect_uri=https%3A%2F%2Fpartner.example.com%2Fcallback&state=xyz"
+
+	tests := []struct {
+		name        string
+		auth        string
+		body        string
+		setupMocks  func(*mockport.MockUserController, *mockport.MockOAuthController)
+		checkResult func(*testing.T, events.APIGatewayProxyResponse)
+	}{
+		{
+			name: "should require the user's token",
+			body: body + "&consent=approve",
+			setupMocks: func(c *mockport.MockUserController, o *mockport.MockOAuthController) {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, resp events.APIGatewayProxyResponse) {
+				assert.Equal(t, 401, resp.StatusCode)
+			},
+		},
+		{
+			name: "should reject a personal access token",
+			auth: "Bearer pat-token",
+			body: body + "&consent=approve",
+			setupMocks: func(c *mockport.MockUserController, o *mockport.MockOAuthController) {
+				c.EXPECT().Authenticate(ctx, "pat-token").
+					Return(&domain.Principal{UserID: 7, PersonalAccessToken: true, Scopes: []string{domain.ScopeUsersWrite}}, nil)
+			},
+			checkResult: func(t *testing.T, resp events.APIGatewayProxyResponse) {
+				assert.Equal(t, 403, resp.StatusCode)
+			},
+		},
+		{
+			name: "should return the redirect of an approved request",
+			auth: "Bearer user-token",
+			body: body + "&consent=approve",
+			setupMocks: func(c *mockport.MockUserController, o *mockport.MockOAuthController) {
+				c.EXPECT().Authenticate(ctx, "user-token").Return(&domain.Principal{UserID: 7, IssuedAt: 1700000000}, nil)
+				o.EXPECT().
+					Authorize(ctx, dto.AuthorizeInput{
+						UserID:       7,
+						AuthTime:     1700000000,
+						Consent:      true,
+						ResponseType: "code",
+						ClientID:     "partner-app",
+						RedirectURI:  "https://partner.example.com/callback",
+						State:        "xyz",
+					}).
+					Return(&dto.AuthorizeOutput{RedirectURI: "https://partner.example.com/callback?code=abc&state=xyz"}, nil)
+			},
+			checkResult: func(t *testing.T, resp events.APIGatewayProxyResponse) {
+				assert.Equal(t, 200, resp.StatusCode)
+				assert.JSONEq(t, `{"redirect_uri":"https://partner.example.com/callback?code=abc&state=xyz"}`, resp.Body)
+				assert.Equal(t, "no-store", resp.Headers["Cache-Control"])
+				assert.Equal(t, "*", resp.Headers["Access-Control-Allow-Origin"])
+			},
+		},
+		{
+			name: "should pass on a declined request",
+			auth: "Bearer user-token",
+			body: body + "&consent=deny",
+			setupMocks: func(c *mockport.MockUserController, o *mockport.MockOAuthController) {
+				c.EXPECT().Authenticate(ctx, "user-token").Return(&domain.Principal{UserID: 7, IssuedAt: 1700000000}, nil)
+				o.EXPECT().
+					Authorize(ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, in dto.AuthorizeInput) (*dto.AuthorizeOutput, error) {
+						assert.False(t, in.Consent)
+						return &dto.AuthorizeOutput{RedirectURI: "https://partner.example.com/callback?error=access_denied&state=xyz"}, nil
+					})
+			},
+			checkResult: func(t *testing.T, resp events.APIGatewayProxyResponse) {
+				assert.Equal(t, 200, resp.StatusCode)
+				assert.Contains(t, resp.Body, "access_denied")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			userCtrl := mockport.NewMockUserController(ctrl)
+			oauthCtrl := mockport.NewMockOAuthController(ctrl)
+			tt.setupMocks(userCtrl, oauthCtrl)
+			app = appDeps{ctrl: userCtrl, oauth: oauthCtrl}
+			defer func() { app = appDeps{} }()
+			req := events.APIGatewayProxyRequest{HTTPMethod: "POST", Path: "/oauth/authorize", Body: tt.body, Headers: map[string]string{}}
+			if tt.auth != "" {
+				req.Headers["Authorization"] = tt.auth
+			}
+
+			// Act
+			resp, err := handler(ctx, req)
+
+			// Assert
+			assert.NoError(t, err)
+			tt.checkResult(t, resp)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/adapter/authorizer"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/auth"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/datasource"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/logger"
+)
+
+var app *authorizer.Authorizer
+
+func build(ctx context.Context) (*authorizer.Authorizer, error) {
+	cfg := config.Load(ctx)
+	log := logger.NewLogger(cfg.Environment)
+	log.Info("authorizer: building dependencies")
+	revocations, err := datasource.NewDynamoTokenRevocationStore(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	jwtSigner, err := auth.NewJWTSigner(cfg, revocations)
+	if err != nil {
+		return nil, err
+	}
+	users, err := datasource.NewDynamoUserRepository(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return authorizer.NewAuthorizer(jwtSigner, users), nil
+}
+
+func handler(ctx context.Context, event json.RawMessage) (events.APIGatewayCustomAuthorizerResponse, error) {
+	if app == nil {
+		a, err := build(ctx)
+		if err != nil {
+			return events.APIGatewayCustomAuthorizerResponse{}, err
+		}
+		app = a
+	}
+	return app.Handle(ctx, event)
+}
+
+func main() {
+	lambda.Start(handler)
+}
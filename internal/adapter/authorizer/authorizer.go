@@ -0,0 +1,166 @@
+package authorizer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+)
+
+// ErrUnauthorized makes API Gateway answer 401; it only does so for this exact message.
+var ErrUnauthorized = errors.New("Unauthorized") //nolint:staticcheck // message is matched by API Gateway
+
+// Authorizer implements API Gateway TOKEN and REQUEST Lambda authorizers on top of the
+// access tokens issued by this service.
+type Authorizer struct {
+	jwt   port.JWTSigner
+	users port.UserRepository // nil skips the password-change check
+}
+
+// NewAuthorizer builds an authorizer. With a user repository, user tokens issued before
+// the user's last password change are rejected as well.
+func NewAuthorizer(jwt port.JWTSigner, users port.UserRepository) *Authorizer {
+	return &Authorizer{jwt: jwt, users: users}
+}
+
+// Handle accepts either authorizer event and dispatches on its type field.
+func (a *Authorizer) Handle(ctx context.Context, event json.RawMessage) (events.APIGatewayCustomAuthorizerResponse, error) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(event, &head); err != nil {
+		return events.APIGatewayCustomAuthorizerResponse{}, err
+	}
+	switch strings.ToUpper(head.Type) {
+	case "TOKEN":
+		var req events.APIGatewayCustomAuthorizerRequest
+		if err := json.Unmarshal(event, &req); err != nil {
+			return events.APIGatewayCustomAuthorizerResponse{}, err
+		}
+		return a.AuthorizeToken(ctx, req)
+	case "REQUEST":
+		var req events.APIGatewayCustomAuthorizerRequestTypeRequest
+		if err := json.Unmarshal(event, &req); err != nil {
+			return events.APIGatewayCustomAuthorizerResponse{}, err
+		}
+		return a.AuthorizeRequest(ctx, req)
+	default:
+		return events.APIGatewayCustomAuthorizerResponse{}, errors.New("unsupported authorizer type " + head.Type)
+	}
+}
+
+func (a *Authorizer) AuthorizeToken(ctx context.Context, req events.APIGatewayCustomAuthorizerRequest) (events.APIGatewayCustomAuthorizerResponse, error) {
+	return a.authorize(ctx, req.AuthorizationToken, req.MethodArn)
+}
+
+func (a *Authorizer) AuthorizeRequest(ctx context.Context, req events.APIGatewayCustomAuthorizerRequestTypeRequest) (events.APIGatewayCustomAuthorizerResponse, error) {
+	var hdr string
+	for k, v := range req.Headers {
+		if strings.EqualFold(k, "Authorization") {
+			hdr = v
+			break
+		}
+	}
+	return a.authorize(ctx, hdr, req.MethodArn)
+}
+
+func (a *Authorizer) authorize(ctx context.Context, hdr, methodArn string) (events.APIGatewayCustomAuthorizerResponse, error) {
+	tok := bearerToken(hdr)
+	if tok == "" {
+		return events.APIGatewayCustomAuthorizerResponse{}, ErrUnauthorized
+	}
+	p, err := a.jwt.VerifyPrincipal(ctx, tok)
+	if err != nil {
+		return events.APIGatewayCustomAuthorizerResponse{}, ErrUnauthorized
+	}
+	if p.IsDelegated() {
+		// tokens issued to OpenID Connect relying parties only grant access to userinfo
+		return denyPolicy(p, methodArn), nil
+	}
+	if p.IsImpersonated() {
+		// every impersonated request must be audited, which only the user API does
+		return denyPolicy(p, methodArn), nil
+	}
+	if a.users != nil && !p.IsClient() {
+		user, err := a.users.GetByID(ctx, p.UserID)
+		if err != nil {
+			return events.APIGatewayCustomAuthorizerResponse{}, err
+		}
+		if user == nil || !user.AcceptsTokenIssuedAt(p.IssuedAt) {
+			return events.APIGatewayCustomAuthorizerResponse{}, ErrUnauthorized
+		}
+	}
+	return allowPolicy(p, methodArn), nil
+}
+
+// allowPolicy grants access to the whole stage rather than the single method being called:
+// API Gateway caches the policy per token and reuses it for every route.
+func allowPolicy(p *domain.Principal, methodArn string) events.APIGatewayCustomAuthorizerResponse {
+	principalID := "user:" + strconv.FormatInt(p.UserID, 10)
+	ctx := map[string]interface{}{
+		"subjectType": domain.SubjectTypeUser,
+		"userId":      strconv.FormatInt(p.UserID, 10),
+		"email":       p.Email,
+		"roles":       strings.Join(p.Roles, ","),
+		"scope":       strings.Join(p.Scopes, " "),
+	}
+	if p.SessionID != "" {
+		ctx["sessionId"] = p.SessionID
+	}
+	if p.IsClient() {
+		principalID = "client:" + p.ClientID
+		ctx = map[string]interface{}{
+			"subjectType": domain.SubjectTypeClient,
+			"clientId":    p.ClientID,
+			"scope":       strings.Join(p.Scopes, " "),
+		}
+	}
+	return policy(principalID, "Allow", methodArn, ctx)
+}
+
+// denyPolicy makes API Gateway answer 403 for every route of the stage, for valid tokens
+// that are not meant for the APIs behind it.
+func denyPolicy(p *domain.Principal, methodArn string) events.APIGatewayCustomAuthorizerResponse {
+	return policy("user:"+strconv.FormatInt(p.UserID, 10), "Deny", methodArn, nil)
+}
+
+func policy(principalID, effect, methodArn string, ctx map[string]interface{}) events.APIGatewayCustomAuthorizerResponse {
+	return events.APIGatewayCustomAuthorizerResponse{
+		PrincipalID: principalID,
+		PolicyDocument: events.APIGatewayCustomAuthorizerPolicy{
+			Version: "2012-10-17",
+			Statement: []events.IAMPolicyStatement{
+				{
+					Action:   []string{"execute-api:Invoke"},
+					Effect:   effect,
+					Resource: []string{stageWildcard(methodArn)},
+				},
+			},
+		},
+		Context: ctx,
+	}
+}
+
+// stageWildcard turns arn:aws:execute-api:region:account:api/stage/METHOD/path into
+// arn:aws:execute-api:region:account:api/stage/*.
+func stageWildcard(methodArn string) string {
+	parts := strings.SplitN(methodArn, "/", 3)
+	if len(parts) < 2 {
+		return methodArn
+	}
+	return parts[0] + "/" + parts[1] + "/*"
+}
+
+func bearerToken(hdr string) string {
+	parts := strings.SplitN(strings.TrimSpace(hdr), " ", 2)
+	if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
+		return strings.TrimSpace(parts[1])
+	}
+	return ""
+}
@@ -0,0 +1,222 @@
+package authorizer_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/adapter/authorizer"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	mockport "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port/mocks"
+)
+
+const methodArn = "arn:aws:execute-api:us-east-1:123456789012:abc123/prod/GET/users/me"
+
+func TestAuthorizer_AuthorizeToken(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		token       string
+		setupMocks  func(m *mockport.MockJWTSigner)
+		checkResult func(*testing.T, events.APIGatewayCustomAuthorizerResponse, error)
+	}{
+		{
+			name:  "should allow the whole stage for a valid token",
+			token: "Bearer good-token",
+			setupMocks: func(m *mockport.MockJWTSigner) {
+				m.EXPECT().VerifyPrincipal(ctx, "good-token").
+					Return(&domain.Principal{UserID: 7, Email: "a@a.com", Roles: []string{"admin", "support"}}, nil)
+			},
+			checkResult: func(t *testing.T, resp events.APIGatewayCustomAuthorizerResponse, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "user:7", resp.PrincipalID)
+				assert.Equal(t, "2012-10-17", resp.PolicyDocument.Version)
+				assert.Len(t, resp.PolicyDocument.Statement, 1)
+				stmt := resp.PolicyDocument.Statement[0]
+				assert.Equal(t, "Allow", stmt.Effect)
+				assert.Equal(t, []string{"execute-api:Invoke"}, stmt.Action)
+				assert.Equal(t, []string{"arn:aws:execute-api:us-east-1:123456789012:abc123/prod/*"}, stmt.Resource)
+				assert.Equal(t, "7", resp.Context["userId"])
+				assert.Equal(t, "a@a.com", resp.Context["email"])
+				assert.Equal(t, "admin,support", resp.Context["roles"])
+			},
+		},
+		{
+			name:  "should identify client tokens by client ID",
+			token: "Bearer client-token",
+			setupMocks: func(m *mockport.MockJWTSigner) {
+				m.EXPECT().VerifyPrincipal(ctx, "client-token").
+					Return(&domain.Principal{SubjectType: domain.SubjectTypeClient, ClientID: "video-worker", Scopes: []string{"users:read"}}, nil)
+			},
+			checkResult: func(t *testing.T, resp events.APIGatewayCustomAuthorizerResponse, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "client:video-worker", resp.PrincipalID)
+				assert.Equal(t, "client", resp.Context["subjectType"])
+				assert.Equal(t, "video-worker", resp.Context["clientId"])
+				assert.Equal(t, "users:read", resp.Context["scope"])
+				assert.NotContains(t, resp.Context, "userId")
+			},
+		},
+		{
+			name:  "should deny the whole stage to a user token issued to an OpenID Connect client",
+			token: "Bearer delegated-token",
+			setupMocks: func(m *mockport.MockJWTSigner) {
+				m.EXPECT().VerifyPrincipal(ctx, "delegated-token").
+					Return(&domain.Principal{UserID: 7, ClientID: "partner-app", Scopes: []string{"openid", "email"}}, nil)
+			},
+			checkResult: func(t *testing.T, resp events.APIGatewayCustomAuthorizerResponse, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "user:7", resp.PrincipalID)
+				assert.Len(t, resp.PolicyDocument.Statement, 1)
+				stmt := resp.PolicyDocument.Statement[0]
+				assert.Equal(t, "Deny", stmt.Effect)
+				assert.Equal(t, []string{"arn:aws:execute-api:us-east-1:123456789012:abc123/prod/*"}, stmt.Resource)
+				assert.Empty(t, resp.Context)
+			},
+		},
+		{
+			name:  "should deny the whole stage to an impersonation token",
+			token: "Bearer impersonation-token",
+			setupMocks: func(m *mockport.MockJWTSigner) {
+				m.EXPECT().VerifyPrincipal(ctx, "impersonation-token").
+					Return(&domain.Principal{UserID: 7, ActorUserID: 1, ActorEmail: "admin@a.com"}, nil)
+			},
+			checkResult: func(t *testing.T, resp events.APIGatewayCustomAuthorizerResponse, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "user:7", resp.PrincipalID)
+				assert.Len(t, resp.PolicyDocument.Statement, 1)
+				assert.Equal(t, "Deny", resp.PolicyDocument.Statement[0].Effect)
+				assert.NotContains(t, resp.Context, "actorUserId")
+			},
+		},
+		{
+			name:  "should reject a token without the Bearer scheme",
+			token: "good-token",
+			setupMocks: func(m *mockport.MockJWTSigner) {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, resp events.APIGatewayCustomAuthorizerResponse, err error) {
+				assert.Equal(t, authorizer.ErrUnauthorized, err)
+			},
+		},
+		{
+			name:  "should reject an invalid token",
+			token: "Bearer bad-token",
+			setupMocks: func(m *mockport.MockJWTSigner) {
+				m.EXPECT().VerifyPrincipal(ctx, "bad-token").Return(nil, assert.AnError)
+			},
+			checkResult: func(t *testing.T, resp events.APIGatewayCustomAuthorizerResponse, err error) {
+				assert.Equal(t, authorizer.ErrUnauthorized, err)
+				assert.Empty(t, resp.PrincipalID)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockJWT := mockport.NewMockJWTSigner(ctrl)
+			tt.setupMocks(mockJWT)
+
+			resp, err := authorizer.NewAuthorizer(mockJWT, nil).AuthorizeToken(ctx, events.APIGatewayCustomAuthorizerRequest{
+				Type:               "TOKEN",
+				AuthorizationToken: tt.token,
+				MethodArn:          methodArn,
+			})
+			tt.checkResult(t, resp, err)
+		})
+	}
+}
+
+func TestAuthorizer_AuthorizeRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	mockJWT := mockport.NewMockJWTSigner(ctrl)
+	a := authorizer.NewAuthorizer(mockJWT, nil)
+
+	mockJWT.EXPECT().VerifyPrincipal(ctx, "good-token").Return(&domain.Principal{UserID: 3}, nil)
+	resp, err := a.AuthorizeRequest(ctx, events.APIGatewayCustomAuthorizerRequestTypeRequest{
+		Type:      "REQUEST",
+		MethodArn: methodArn,
+		Headers:   map[string]string{"authorization": "bearer good-token"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "user:3", resp.PrincipalID)
+	assert.Equal(t, "", resp.Context["roles"])
+
+	_, err = a.AuthorizeRequest(ctx, events.APIGatewayCustomAuthorizerRequestTypeRequest{Type: "REQUEST", MethodArn: methodArn})
+	assert.Equal(t, authorizer.ErrUnauthorized, err)
+}
+
+func TestAuthorizer_Handle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	mockJWT := mockport.NewMockJWTSigner(ctrl)
+	a := authorizer.NewAuthorizer(mockJWT, nil)
+
+	mockJWT.EXPECT().VerifyPrincipal(ctx, "tok-1").Return(&domain.Principal{UserID: 1}, nil)
+	mockJWT.EXPECT().VerifyPrincipal(ctx, "tok-2").Return(&domain.Principal{UserID: 2}, nil)
+
+	tokenEvent, _ := json.Marshal(events.APIGatewayCustomAuthorizerRequest{Type: "TOKEN", AuthorizationToken: "Bearer tok-1", MethodArn: methodArn})
+	resp, err := a.Handle(ctx, tokenEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, "user:1", resp.PrincipalID)
+
+	requestEvent, _ := json.Marshal(events.APIGatewayCustomAuthorizerRequestTypeRequest{Type: "REQUEST", MethodArn: methodArn, Headers: map[string]string{"Authorization": "Bearer tok-2"}})
+	resp, err = a.Handle(ctx, requestEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, "user:2", resp.PrincipalID)
+
+	_, err = a.Handle(ctx, json.RawMessage(`{"type":"COGNITO"}`))
+	assert.Error(t, err)
+	_, err = a.Handle(ctx, json.RawMessage(`not json`))
+	assert.Error(t, err)
+}
+
+func TestAuthorizer_RejectsTokensIssuedBeforePasswordChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	mockJWT := mockport.NewMockJWTSigner(ctrl)
+	mockUsers := mockport.NewMockUserRepository(ctrl)
+	a := authorizer.NewAuthorizer(mockJWT, mockUsers)
+	authorize := func(token string) (events.APIGatewayCustomAuthorizerResponse, error) {
+		return a.AuthorizeToken(ctx, events.APIGatewayCustomAuthorizerRequest{Type: "TOKEN", AuthorizationToken: "Bearer " + token, MethodArn: methodArn})
+	}
+	user := &domain.User{UserID: 7, TokensValidAfter: 1000}
+
+	mockJWT.EXPECT().VerifyPrincipal(ctx, "old-token").Return(&domain.Principal{UserID: 7, IssuedAt: 999}, nil)
+	mockUsers.EXPECT().GetByID(ctx, int64(7)).Return(user, nil)
+	_, err := authorize("old-token")
+	assert.Equal(t, authorizer.ErrUnauthorized, err)
+
+	mockJWT.EXPECT().VerifyPrincipal(ctx, "same-second-token").Return(&domain.Principal{UserID: 7, IssuedAt: 1000}, nil)
+	mockUsers.EXPECT().GetByID(ctx, int64(7)).Return(user, nil)
+	_, err = authorize("same-second-token")
+	assert.Equal(t, authorizer.ErrUnauthorized, err)
+
+	mockJWT.EXPECT().VerifyPrincipal(ctx, "new-token").Return(&domain.Principal{UserID: 7, IssuedAt: 1001}, nil)
+	mockUsers.EXPECT().GetByID(ctx, int64(7)).Return(user, nil)
+	resp, err := authorize("new-token")
+	assert.NoError(t, err)
+	assert.Equal(t, "user:7", resp.PrincipalID)
+
+	mockJWT.EXPECT().VerifyPrincipal(ctx, "deleted-user").Return(&domain.Principal{UserID: 8, IssuedAt: 1001}, nil)
+	mockUsers.EXPECT().GetByID(ctx, int64(8)).Return(nil, nil)
+	_, err = authorize("deleted-user")
+	assert.Equal(t, authorizer.ErrUnauthorized, err)
+
+	// client tokens have no user to look up
+	mockJWT.EXPECT().VerifyPrincipal(ctx, "client-token").Return(&domain.Principal{SubjectType: domain.SubjectTypeClient, ClientID: "video-worker"}, nil)
+	resp, err = authorize("client-token")
+	assert.NoError(t, err)
+	assert.Equal(t, "client:video-worker", resp.PrincipalID)
+}
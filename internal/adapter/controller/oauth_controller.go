@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+)
+
+type OAuthController struct {
+	usecase port.OAuthUseCase
+}
+
+func NewOAuthController(uc port.OAuthUseCase) port.OAuthController {
+	return &OAuthController{usecase: uc}
+}
+
+func (c *OAuthController) Introspect(ctx context.Context, p port.Presenter, in dto.IntrospectInput) ([]byte, error) {
+	out, err := c.usecase.Introspect(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *OAuthController) Token(ctx context.Context, p port.Presenter, in dto.TokenInput) ([]byte, error) {
+	out, err := c.usecase.Token(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *OAuthController) Authorize(ctx context.Context, in dto.AuthorizeInput) (*dto.AuthorizeOutput, error) {
+	return c.usecase.Authorize(ctx, in)
+}
+
+func (c *OAuthController) UserInfo(ctx context.Context, p port.Presenter, principal domain.Principal) ([]byte, error) {
+	out, err := c.usecase.UserInfo(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *OAuthController) Discovery(ctx context.Context, p port.Presenter) ([]byte, error) {
+	out, err := c.usecase.Discovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
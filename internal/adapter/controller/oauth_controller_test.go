@@ -0,0 +1,173 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/adapter/controller"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+	mockport "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port/mocks"
+)
+
+func TestOAuthController_Introspect_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockOAuthUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewOAuthController(mockUC)
+
+	ctx := context.Background()
+	in := dto.IntrospectInput{ClientID: "svc", ClientSecret: "s", Token: "t"}
+	out := &dto.IntrospectOutput{Active: true, Sub: "1"}
+
+	mockUC.EXPECT().Introspect(ctx, in).Return(out, nil)
+	mockPresenter.EXPECT().Present(out).Return([]byte(`{"active":true}`), nil)
+
+	b, err := c.Introspect(ctx, mockPresenter, in)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"active":true}`, string(b))
+}
+
+func TestOAuthController_Introspect_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockOAuthUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewOAuthController(mockUC)
+
+	ctx := context.Background()
+	in := dto.IntrospectInput{Token: "t"}
+
+	mockUC.EXPECT().Introspect(ctx, in).Return(nil, assert.AnError)
+
+	b, err := c.Introspect(ctx, mockPresenter, in)
+	assert.Error(t, err)
+	assert.Nil(t, b)
+}
+
+func TestOAuthController_Token_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockOAuthUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewOAuthController(mockUC)
+
+	ctx := context.Background()
+	in := dto.TokenInput{ClientID: "svc", ClientSecret: "s", GrantType: "client_credentials"}
+	out := &dto.TokenOutput{AccessToken: "jwt", TokenType: "Bearer", ExpiresIn: 3600}
+
+	mockUC.EXPECT().Token(ctx, in).Return(out, nil)
+	mockPresenter.EXPECT().Present(out).Return([]byte(`{"access_token":"jwt"}`), nil)
+
+	b, err := c.Token(ctx, mockPresenter, in)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"access_token":"jwt"}`, string(b))
+}
+
+func TestOAuthController_Token_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockOAuthUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewOAuthController(mockUC)
+
+	ctx := context.Background()
+	in := dto.TokenInput{GrantType: "password"}
+
+	mockUC.EXPECT().Token(ctx, in).Return(nil, assert.AnError)
+
+	b, err := c.Token(ctx, mockPresenter, in)
+	assert.Error(t, err)
+	assert.Nil(t, b)
+}
+
+func TestOAuthController_Authorize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockOAuthUseCase(ctrl)
+	c := controller.NewOAuthController(mockUC)
+
+	ctx := context.Background()
+	in := dto.AuthorizeInput{UserID: 7, ClientID: "partner-app", ResponseType: "code"}
+	out := &dto.AuthorizeOutput{RedirectURI: "https://partner.example.com/callback?code=c"}
+
+	mockUC.EXPECT().Authorize(ctx, in).Return(out, nil)
+	got, err := c.Authorize(ctx, in)
+	assert.NoError(t, err)
+	assert.Equal(t, out, got)
+
+	mockUC.EXPECT().Authorize(ctx, in).Return(nil, assert.AnError)
+	_, err = c.Authorize(ctx, in)
+	assert.Error(t, err)
+}
+
+func TestOAuthController_UserInfo_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockOAuthUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewOAuthController(mockUC)
+
+	ctx := context.Background()
+	principal := domain.Principal{UserID: 7}
+	out := &dto.UserInfoOutput{Sub: "7"}
+
+	mockUC.EXPECT().UserInfo(ctx, principal).Return(out, nil)
+	mockPresenter.EXPECT().Present(out).Return([]byte(`{"sub":"7"}`), nil)
+
+	b, err := c.UserInfo(ctx, mockPresenter, principal)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"sub":"7"}`, string(b))
+}
+
+func TestOAuthController_UserInfo_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockOAuthUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewOAuthController(mockUC)
+
+	ctx := context.Background()
+	principal := domain.Principal{UserID: 7}
+
+	mockUC.EXPECT().UserInfo(ctx, principal).Return(nil, assert.AnError)
+
+	b, err := c.UserInfo(ctx, mockPresenter, principal)
+	assert.Error(t, err)
+	assert.Nil(t, b)
+}
+
+func TestOAuthController_Discovery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockOAuthUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewOAuthController(mockUC)
+
+	ctx := context.Background()
+	out := &dto.OpenIDConfigurationOutput{Issuer: "https://auth.example.com"}
+
+	mockUC.EXPECT().Discovery(ctx).Return(out, nil)
+	mockPresenter.EXPECT().Present(out).Return([]byte(`{"issuer":"https://auth.example.com"}`), nil)
+
+	b, err := c.Discovery(ctx, mockPresenter)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"issuer":"https://auth.example.com"}`, string(b))
+
+	mockUC.EXPECT().Discovery(ctx).Return(nil, assert.AnError)
+	b, err = c.Discovery(ctx, mockPresenter)
+	assert.Error(t, err)
+	assert.Nil(t, b)
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
 )
@@ -31,6 +32,166 @@ func (c *UserController) Login(ctx context.Context, p port.Presenter, in dto.Log
 	return p.Present(out)
 }
 
+func (c *UserController) LoginMFA(ctx context.Context, p port.Presenter, in dto.LoginMFAInput) ([]byte, error) {
+	out, err := c.usecase.LoginMFA(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *UserController) Refresh(ctx context.Context, p port.Presenter, in dto.RefreshInput) ([]byte, error) {
+	out, err := c.usecase.Refresh(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *UserController) Logout(ctx context.Context, in dto.LogoutInput) error {
+	return c.usecase.Logout(ctx, in)
+}
+
+func (c *UserController) RequestMagicLink(ctx context.Context, in dto.MagicLinkInput) error {
+	return c.usecase.RequestMagicLink(ctx, in)
+}
+
+func (c *UserController) ConsumeMagicLink(ctx context.Context, p port.Presenter, in dto.ConsumeMagicLinkInput) ([]byte, error) {
+	out, err := c.usecase.ConsumeMagicLink(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *UserController) ForgotPassword(ctx context.Context, in dto.ForgotPasswordInput) error {
+	return c.usecase.ForgotPassword(ctx, in)
+}
+
+func (c *UserController) ResetPassword(ctx context.Context, in dto.ResetPasswordInput) error {
+	return c.usecase.ResetPassword(ctx, in)
+}
+
+func (c *UserController) ChangePassword(ctx context.Context, in dto.ChangePasswordInput) error {
+	return c.usecase.ChangePassword(ctx, in)
+}
+
+func (c *UserController) VerifyEmail(ctx context.Context, in dto.VerifyEmailInput) error {
+	return c.usecase.VerifyEmail(ctx, in)
+}
+
+func (c *UserController) RequestEmailChange(ctx context.Context, in dto.ChangeEmailInput) error {
+	return c.usecase.RequestEmailChange(ctx, in)
+}
+
+func (c *UserController) ConfirmEmailChange(ctx context.Context, in dto.ConfirmEmailChangeInput) error {
+	return c.usecase.ConfirmEmailChange(ctx, in)
+}
+
+func (c *UserController) ResendVerification(ctx context.Context, in dto.ResendVerificationInput) error {
+	return c.usecase.ResendVerification(ctx, in)
+}
+
+func (c *UserController) EnrollTOTP(ctx context.Context, p port.Presenter, userID int64) ([]byte, error) {
+	out, err := c.usecase.EnrollTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *UserController) ConfirmTOTP(ctx context.Context, in dto.ConfirmTOTPInput) error {
+	return c.usecase.ConfirmTOTP(ctx, in)
+}
+
+func (c *UserController) BeginPasskeyRegistration(ctx context.Context, p port.Presenter, userID int64) ([]byte, error) {
+	out, err := c.usecase.BeginPasskeyRegistration(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *UserController) FinishPasskeyRegistration(ctx context.Context, p port.Presenter, in dto.FinishPasskeyRegistrationInput) ([]byte, error) {
+	out, err := c.usecase.FinishPasskeyRegistration(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *UserController) BeginPasskeyLogin(ctx context.Context, p port.Presenter) ([]byte, error) {
+	out, err := c.usecase.BeginPasskeyLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *UserController) FinishPasskeyLogin(ctx context.Context, p port.Presenter, in dto.FinishPasskeyLoginInput) ([]byte, error) {
+	out, err := c.usecase.FinishPasskeyLogin(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *UserController) BeginFederatedLogin(ctx context.Context, p port.Presenter, in dto.BeginFederatedLoginInput) ([]byte, error) {
+	out, err := c.usecase.BeginFederatedLogin(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *UserController) FinishFederatedLogin(ctx context.Context, p port.Presenter, in dto.FinishFederatedLoginInput) ([]byte, error) {
+	out, err := c.usecase.FinishFederatedLogin(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *UserController) ListSessions(ctx context.Context, p port.Presenter, in dto.ListSessionsInput) ([]byte, error) {
+	out, err := c.usecase.ListSessions(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *UserController) RevokeSession(ctx context.Context, in dto.RevokeSessionInput) error {
+	return c.usecase.RevokeSession(ctx, in)
+}
+
+func (c *UserController) CreatePersonalAccessToken(ctx context.Context, p port.Presenter, in dto.CreatePersonalAccessTokenInput) ([]byte, error) {
+	out, err := c.usecase.CreatePersonalAccessToken(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *UserController) ListPersonalAccessTokens(ctx context.Context, p port.Presenter, userID int64) ([]byte, error) {
+	out, err := c.usecase.ListPersonalAccessTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
+func (c *UserController) RevokePersonalAccessToken(ctx context.Context, in dto.RevokePersonalAccessTokenInput) error {
+	return c.usecase.RevokePersonalAccessToken(ctx, in)
+}
+
+func (c *UserController) Impersonate(ctx context.Context, p port.Presenter, in dto.ImpersonateInput) ([]byte, error) {
+	out, err := c.usecase.Impersonate(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return p.Present(out)
+}
+
 func (c *UserController) GetMe(ctx context.Context, p port.Presenter, userID int64) ([]byte, error) {
 	out, err := c.usecase.GetMe(ctx, userID)
 	if err != nil {
@@ -39,6 +200,10 @@ func (c *UserController) GetMe(ctx context.Context, p port.Presenter, userID int
 	return p.Present(out)
 }
 
+func (c *UserController) Authenticate(ctx context.Context, token string) (*domain.Principal, error) {
+	return c.usecase.Authenticate(ctx, token)
+}
+
 func (c *UserController) GetUserByID(ctx context.Context, p port.Presenter, userID int64) ([]byte, error) {
 	out, err := c.usecase.GetUserByID(ctx, userID)
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/adapter/controller"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
 	mockport "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port/mocks"
 )
@@ -88,6 +89,430 @@ func TestUserController_Login_Error(t *testing.T) {
 	assert.Nil(t, b)
 }
 
+func TestUserController_Refresh_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.RefreshInput{RefreshToken: "refresh"}
+	out := &dto.LoginOutput{Token: "abc", RefreshToken: "def"}
+
+	mockUC.EXPECT().Refresh(ctx, in).Return(out, nil)
+	mockPresenter.EXPECT().Present(gomock.AssignableToTypeOf(&dto.LoginOutput{})).Return([]byte("{}"), nil)
+
+	b, err := c.Refresh(ctx, mockPresenter, in)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+}
+
+func TestUserController_Refresh_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.RefreshInput{RefreshToken: "refresh"}
+
+	mockUC.EXPECT().Refresh(ctx, in).Return(nil, assert.AnError)
+
+	b, err := c.Refresh(ctx, mockPresenter, in)
+	assert.Error(t, err)
+	assert.Nil(t, b)
+}
+
+func TestUserController_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.LogoutInput{AccessToken: "abc"}
+
+	mockUC.EXPECT().Logout(ctx, in).Return(nil)
+	assert.NoError(t, c.Logout(ctx, in))
+
+	mockUC.EXPECT().Logout(ctx, in).Return(assert.AnError)
+	assert.Error(t, c.Logout(ctx, in))
+}
+
+func TestUserController_ForgotPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.ForgotPasswordInput{Email: "a@a.com"}
+
+	mockUC.EXPECT().ForgotPassword(ctx, in).Return(nil)
+	assert.NoError(t, c.ForgotPassword(ctx, in))
+
+	mockUC.EXPECT().ForgotPassword(ctx, in).Return(assert.AnError)
+	assert.Error(t, c.ForgotPassword(ctx, in))
+}
+
+func TestUserController_RequestMagicLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.MagicLinkInput{Email: "a@a.com"}
+
+	mockUC.EXPECT().RequestMagicLink(ctx, in).Return(nil)
+	assert.NoError(t, c.RequestMagicLink(ctx, in))
+
+	mockUC.EXPECT().RequestMagicLink(ctx, in).Return(assert.AnError)
+	assert.Error(t, c.RequestMagicLink(ctx, in))
+}
+
+func TestUserController_ConsumeMagicLink_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.ConsumeMagicLinkInput{Token: "link"}
+	out := &dto.LoginOutput{Token: "t"}
+
+	mockUC.EXPECT().ConsumeMagicLink(ctx, in).Return(out, nil)
+	mockPresenter.EXPECT().Present(gomock.AssignableToTypeOf(&dto.LoginOutput{})).Return([]byte("{}"), nil)
+
+	b, err := c.ConsumeMagicLink(ctx, mockPresenter, in)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+}
+
+func TestUserController_ConsumeMagicLink_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.ConsumeMagicLinkInput{Token: "link"}
+
+	mockUC.EXPECT().ConsumeMagicLink(ctx, in).Return(nil, assert.AnError)
+
+	b, err := c.ConsumeMagicLink(ctx, mockPresenter, in)
+	assert.Error(t, err)
+	assert.Nil(t, b)
+}
+
+func TestUserController_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.ResetPasswordInput{Token: "t", NewPassword: "new-password"}
+
+	mockUC.EXPECT().ResetPassword(ctx, in).Return(nil)
+	assert.NoError(t, c.ResetPassword(ctx, in))
+
+	mockUC.EXPECT().ResetPassword(ctx, in).Return(assert.AnError)
+	assert.Error(t, c.ResetPassword(ctx, in))
+}
+
+func TestUserController_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.ChangePasswordInput{UserID: 5, CurrentPassword: "old-password", NewPassword: "new-password"}
+
+	mockUC.EXPECT().ChangePassword(ctx, in).Return(nil)
+	assert.NoError(t, c.ChangePassword(ctx, in))
+
+	mockUC.EXPECT().ChangePassword(ctx, in).Return(assert.AnError)
+	assert.Error(t, c.ChangePassword(ctx, in))
+}
+
+func TestUserController_VerifyEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.VerifyEmailInput{Token: "t"}
+
+	mockUC.EXPECT().VerifyEmail(ctx, in).Return(nil)
+	assert.NoError(t, c.VerifyEmail(ctx, in))
+
+	mockUC.EXPECT().VerifyEmail(ctx, in).Return(assert.AnError)
+	assert.Error(t, c.VerifyEmail(ctx, in))
+}
+
+func TestUserController_EmailChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	request := dto.ChangeEmailInput{UserID: 5, NewEmail: "new@a.com", Password: "password123"}
+	confirm := dto.ConfirmEmailChangeInput{Token: "change-token"}
+
+	mockUC.EXPECT().RequestEmailChange(ctx, request).Return(nil)
+	assert.NoError(t, c.RequestEmailChange(ctx, request))
+	mockUC.EXPECT().RequestEmailChange(ctx, request).Return(assert.AnError)
+	assert.Error(t, c.RequestEmailChange(ctx, request))
+
+	mockUC.EXPECT().ConfirmEmailChange(ctx, confirm).Return(nil)
+	assert.NoError(t, c.ConfirmEmailChange(ctx, confirm))
+	mockUC.EXPECT().ConfirmEmailChange(ctx, confirm).Return(assert.AnError)
+	assert.Error(t, c.ConfirmEmailChange(ctx, confirm))
+}
+
+func TestUserController_ResendVerification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.ResendVerificationInput{Email: "a@a.com"}
+
+	mockUC.EXPECT().ResendVerification(ctx, in).Return(nil)
+	assert.NoError(t, c.ResendVerification(ctx, in))
+
+	mockUC.EXPECT().ResendVerification(ctx, in).Return(assert.AnError)
+	assert.Error(t, c.ResendVerification(ctx, in))
+}
+
+func TestUserController_LoginMFA_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.LoginMFAInput{MFAToken: "mfa", Code: "123456"}
+	out := &dto.LoginOutput{Token: "t"}
+
+	mockUC.EXPECT().LoginMFA(ctx, in).Return(out, nil)
+	mockPresenter.EXPECT().Present(gomock.AssignableToTypeOf(&dto.LoginOutput{})).Return([]byte("{}"), nil)
+
+	b, err := c.LoginMFA(ctx, mockPresenter, in)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+}
+
+func TestUserController_LoginMFA_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.LoginMFAInput{MFAToken: "mfa", Code: "123456"}
+
+	mockUC.EXPECT().LoginMFA(ctx, in).Return(nil, assert.AnError)
+
+	b, err := c.LoginMFA(ctx, mockPresenter, in)
+	assert.Error(t, err)
+	assert.Nil(t, b)
+}
+
+func TestUserController_EnrollTOTP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	out := &dto.EnrollTOTPOutput{Secret: "S", URI: "otpauth://totp/x"}
+
+	mockUC.EXPECT().EnrollTOTP(ctx, int64(5)).Return(out, nil)
+	mockPresenter.EXPECT().Present(gomock.AssignableToTypeOf(&dto.EnrollTOTPOutput{})).Return([]byte("{}"), nil)
+	b, err := c.EnrollTOTP(ctx, mockPresenter, 5)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+
+	mockUC.EXPECT().EnrollTOTP(ctx, int64(5)).Return(nil, assert.AnError)
+	b, err = c.EnrollTOTP(ctx, mockPresenter, 5)
+	assert.Error(t, err)
+	assert.Nil(t, b)
+}
+
+func TestUserController_ConfirmTOTP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.ConfirmTOTPInput{UserID: 5, Code: "123456"}
+
+	mockUC.EXPECT().ConfirmTOTP(ctx, in).Return(nil)
+	assert.NoError(t, c.ConfirmTOTP(ctx, in))
+
+	mockUC.EXPECT().ConfirmTOTP(ctx, in).Return(assert.AnError)
+	assert.Error(t, c.ConfirmTOTP(ctx, in))
+}
+
+func TestUserController_PasskeyRegistration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.FinishPasskeyRegistrationInput{UserID: 5, ClientDataJSON: "cd", AttestationObject: "att"}
+
+	mockUC.EXPECT().BeginPasskeyRegistration(ctx, int64(5)).Return(&dto.BeginPasskeyRegistrationOutput{Challenge: "c"}, nil)
+	mockPresenter.EXPECT().Present(gomock.AssignableToTypeOf(&dto.BeginPasskeyRegistrationOutput{})).Return([]byte("{}"), nil)
+	b, err := c.BeginPasskeyRegistration(ctx, mockPresenter, 5)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+
+	mockUC.EXPECT().FinishPasskeyRegistration(ctx, in).Return(&dto.PasskeyOutput{CredentialID: "cred"}, nil)
+	mockPresenter.EXPECT().Present(gomock.AssignableToTypeOf(&dto.PasskeyOutput{})).Return([]byte("{}"), nil)
+	b, err = c.FinishPasskeyRegistration(ctx, mockPresenter, in)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+
+	mockUC.EXPECT().FinishPasskeyRegistration(ctx, in).Return(nil, assert.AnError)
+	b, err = c.FinishPasskeyRegistration(ctx, mockPresenter, in)
+	assert.Error(t, err)
+	assert.Nil(t, b)
+}
+
+func TestUserController_PasskeyLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.FinishPasskeyLoginInput{CredentialID: "cred", ClientDataJSON: "cd", AuthenticatorData: "ad", Signature: "sig"}
+
+	mockUC.EXPECT().BeginPasskeyLogin(ctx).Return(&dto.BeginPasskeyLoginOutput{Challenge: "c"}, nil)
+	mockPresenter.EXPECT().Present(gomock.AssignableToTypeOf(&dto.BeginPasskeyLoginOutput{})).Return([]byte("{}"), nil)
+	b, err := c.BeginPasskeyLogin(ctx, mockPresenter)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+
+	mockUC.EXPECT().FinishPasskeyLogin(ctx, in).Return(&dto.LoginOutput{Token: "t"}, nil)
+	mockPresenter.EXPECT().Present(gomock.AssignableToTypeOf(&dto.LoginOutput{})).Return([]byte("{}"), nil)
+	b, err = c.FinishPasskeyLogin(ctx, mockPresenter, in)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+
+	mockUC.EXPECT().FinishPasskeyLogin(ctx, in).Return(nil, assert.AnError)
+	b, err = c.FinishPasskeyLogin(ctx, mockPresenter, in)
+	assert.Error(t, err)
+	assert.Nil(t, b)
+}
+
+func TestUserController_FederatedLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	begin := dto.BeginFederatedLoginInput{Provider: "google"}
+	finish := dto.FinishFederatedLoginInput{Provider: "google", Code: "code", State: "state"}
+
+	mockUC.EXPECT().BeginFederatedLogin(ctx, begin).Return(&dto.BeginFederatedLoginOutput{AuthorizationURL: "https://idp/authorize", State: "state"}, nil)
+	mockPresenter.EXPECT().Present(gomock.AssignableToTypeOf(&dto.BeginFederatedLoginOutput{})).Return([]byte("{}"), nil)
+	b, err := c.BeginFederatedLogin(ctx, mockPresenter, begin)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+
+	mockUC.EXPECT().FinishFederatedLogin(ctx, finish).Return(&dto.LoginOutput{Token: "t"}, nil)
+	mockPresenter.EXPECT().Present(gomock.AssignableToTypeOf(&dto.LoginOutput{})).Return([]byte("{}"), nil)
+	b, err = c.FinishFederatedLogin(ctx, mockPresenter, finish)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+
+	mockUC.EXPECT().FinishFederatedLogin(ctx, finish).Return(nil, assert.AnError)
+	b, err = c.FinishFederatedLogin(ctx, mockPresenter, finish)
+	assert.Error(t, err)
+	assert.Nil(t, b)
+}
+
+func TestUserController_ListSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.ListSessionsInput{UserID: 5, CurrentSessionID: "sess-1"}
+	out := &dto.ListSessionsOutput{Sessions: []dto.SessionOutput{{SessionID: "sess-1", Current: true}}}
+
+	mockUC.EXPECT().ListSessions(ctx, in).Return(out, nil)
+	mockPresenter.EXPECT().Present(out).Return([]byte("{}"), nil)
+	b, err := c.ListSessions(ctx, mockPresenter, in)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+
+	mockUC.EXPECT().ListSessions(ctx, in).Return(nil, assert.AnError)
+	b, err = c.ListSessions(ctx, mockPresenter, in)
+	assert.Error(t, err)
+	assert.Nil(t, b)
+}
+
+func TestUserController_RevokeSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.RevokeSessionInput{UserID: 5, SessionID: "sess-1"}
+
+	mockUC.EXPECT().RevokeSession(ctx, in).Return(nil)
+	assert.NoError(t, c.RevokeSession(ctx, in))
+
+	mockUC.EXPECT().RevokeSession(ctx, in).Return(assert.AnError)
+	assert.Error(t, c.RevokeSession(ctx, in))
+}
+
 func TestUserController_GetMe_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -126,6 +551,27 @@ func TestUserController_GetMe_Error(t *testing.T) {
 	assert.Nil(t, b)
 }
 
+func TestUserController_Authenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	principal := &domain.Principal{UserID: 5}
+
+	mockUC.EXPECT().Authenticate(ctx, "jwt-token").Return(principal, nil)
+	got, err := c.Authenticate(ctx, "jwt-token")
+	assert.NoError(t, err)
+	assert.Equal(t, principal, got)
+
+	mockUC.EXPECT().Authenticate(ctx, "jwt-token").Return(nil, assert.AnError)
+	got, err = c.Authenticate(ctx, "jwt-token")
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
+
 func TestUserController_GetUserByID_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -163,3 +609,64 @@ func TestUserController_GetUserByID_Error(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, b)
 }
+
+func TestUserController_PersonalAccessTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	create := dto.CreatePersonalAccessTokenInput{UserID: 5, Name: "ci"}
+	revoke := dto.RevokePersonalAccessTokenInput{UserID: 5, TokenID: "pat-1"}
+
+	mockUC.EXPECT().CreatePersonalAccessToken(ctx, create).Return(&dto.CreatePersonalAccessTokenOutput{Token: "pat_secret"}, nil)
+	mockPresenter.EXPECT().Present(gomock.AssignableToTypeOf(&dto.CreatePersonalAccessTokenOutput{})).Return([]byte("{}"), nil)
+	b, err := c.CreatePersonalAccessToken(ctx, mockPresenter, create)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+
+	mockUC.EXPECT().CreatePersonalAccessToken(ctx, create).Return(nil, assert.AnError)
+	b, err = c.CreatePersonalAccessToken(ctx, mockPresenter, create)
+	assert.Error(t, err)
+	assert.Nil(t, b)
+
+	out := &dto.ListPersonalAccessTokensOutput{Tokens: []dto.PersonalAccessTokenOutput{{TokenID: "pat-1"}}}
+	mockUC.EXPECT().ListPersonalAccessTokens(ctx, int64(5)).Return(out, nil)
+	mockPresenter.EXPECT().Present(out).Return([]byte("{}"), nil)
+	b, err = c.ListPersonalAccessTokens(ctx, mockPresenter, 5)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+
+	mockUC.EXPECT().RevokePersonalAccessToken(ctx, revoke).Return(nil)
+	assert.NoError(t, c.RevokePersonalAccessToken(ctx, revoke))
+
+	mockUC.EXPECT().RevokePersonalAccessToken(ctx, revoke).Return(assert.AnError)
+	assert.Error(t, c.RevokePersonalAccessToken(ctx, revoke))
+}
+
+func TestUserController_Impersonate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := mockport.NewMockUserUseCase(ctrl)
+	mockPresenter := mockport.NewMockPresenter(ctrl)
+	c := controller.NewUserController(mockUC)
+
+	ctx := context.Background()
+	in := dto.ImpersonateInput{AdminID: 9, UserID: 5, Reason: "ticket #42"}
+
+	out := &dto.ImpersonateOutput{Token: "jwt-token", ExpiresIn: 900, UserID: 5}
+	mockUC.EXPECT().Impersonate(ctx, in).Return(out, nil)
+	mockPresenter.EXPECT().Present(out).Return([]byte("{}"), nil)
+	b, err := c.Impersonate(ctx, mockPresenter, in)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+
+	mockUC.EXPECT().Impersonate(ctx, in).Return(nil, assert.AnError)
+	b, err = c.Impersonate(ctx, mockPresenter, in)
+	assert.Error(t, err)
+	assert.Nil(t, b)
+}
@@ -14,37 +14,340 @@ func (p *JSONPresenter) Present(v any) ([]byte, error) {
 	switch t := v.(type) {
 	case dto.RegisterOutput:
 		return json.Marshal(struct {
-			UserID int64  `json:"user_id"`
+			UserID int64  `json:"user_id,omitempty"`
 			Name   string `json:"name"`
 			Email  string `json:"email"`
 		}{UserID: t.UserID, Name: t.Name, Email: t.Email})
 	case *dto.RegisterOutput:
 		return json.Marshal(struct {
-			UserID int64  `json:"user_id"`
+			UserID int64  `json:"user_id,omitempty"`
 			Name   string `json:"name"`
 			Email  string `json:"email"`
 		}{UserID: t.UserID, Name: t.Name, Email: t.Email})
 	case dto.LoginOutput:
-		return json.Marshal(struct {
-			Token string `json:"token"`
-		}{Token: t.Token})
+		return json.Marshal(presentLogin(t))
 	case *dto.LoginOutput:
-		return json.Marshal(struct {
-			Token string `json:"token"`
-		}{Token: t.Token})
+		return json.Marshal(presentLogin(*t))
+	case dto.EnrollTOTPOutput:
+		return json.Marshal(presentTOTPEnrollment(t))
+	case *dto.EnrollTOTPOutput:
+		return json.Marshal(presentTOTPEnrollment(*t))
+	case dto.BeginPasskeyRegistrationOutput:
+		return json.Marshal(presentPasskeyCreationOptions(t))
+	case *dto.BeginPasskeyRegistrationOutput:
+		return json.Marshal(presentPasskeyCreationOptions(*t))
+	case dto.PasskeyOutput:
+		return json.Marshal(presentPasskey(t))
+	case *dto.PasskeyOutput:
+		return json.Marshal(presentPasskey(*t))
+	case dto.BeginPasskeyLoginOutput:
+		return json.Marshal(presentPasskeyRequestOptions(t))
+	case *dto.BeginPasskeyLoginOutput:
+		return json.Marshal(presentPasskeyRequestOptions(*t))
+	case dto.BeginFederatedLoginOutput:
+		return json.Marshal(presentFederatedLogin(t))
+	case *dto.BeginFederatedLoginOutput:
+		return json.Marshal(presentFederatedLogin(*t))
+	case dto.ListSessionsOutput:
+		return json.Marshal(presentSessions(t))
+	case *dto.ListSessionsOutput:
+		return json.Marshal(presentSessions(*t))
+	case dto.CreatePersonalAccessTokenOutput:
+		return json.Marshal(presentCreatedPersonalAccessToken(t))
+	case *dto.CreatePersonalAccessTokenOutput:
+		return json.Marshal(presentCreatedPersonalAccessToken(*t))
+	case dto.ListPersonalAccessTokensOutput:
+		return json.Marshal(presentPersonalAccessTokens(t))
+	case *dto.ListPersonalAccessTokensOutput:
+		return json.Marshal(presentPersonalAccessTokens(*t))
+	case dto.ImpersonateOutput:
+		return json.Marshal(presentImpersonation(t))
+	case *dto.ImpersonateOutput:
+		return json.Marshal(presentImpersonation(*t))
 	case dto.GetMeOutput:
 		return json.Marshal(struct {
-			UserID int64  `json:"user_id"`
-			Name   string `json:"name"`
-			Email  string `json:"email"`
-		}{UserID: t.UserID, Name: t.Name, Email: t.Email})
+			UserID        int64  `json:"user_id"`
+			Name          string `json:"name"`
+			Email         string `json:"email"`
+			EmailVerified bool   `json:"email_verified"`
+			PendingEmail  string `json:"pending_email,omitempty"`
+		}{UserID: t.UserID, Name: t.Name, Email: t.Email, EmailVerified: t.EmailVerified, PendingEmail: t.PendingEmail})
 	case *dto.GetMeOutput:
 		return json.Marshal(struct {
-			UserID int64  `json:"user_id"`
-			Name   string `json:"name"`
-			Email  string `json:"email"`
-		}{UserID: t.UserID, Name: t.Name, Email: t.Email})
+			UserID        int64  `json:"user_id"`
+			Name          string `json:"name"`
+			Email         string `json:"email"`
+			EmailVerified bool   `json:"email_verified"`
+			PendingEmail  string `json:"pending_email,omitempty"`
+		}{UserID: t.UserID, Name: t.Name, Email: t.Email, EmailVerified: t.EmailVerified, PendingEmail: t.PendingEmail})
+	case dto.TokenOutput:
+		return json.Marshal(presentToken(t))
+	case *dto.TokenOutput:
+		return json.Marshal(presentToken(*t))
+	case dto.IntrospectOutput:
+		return json.Marshal(presentIntrospection(t))
+	case *dto.IntrospectOutput:
+		return json.Marshal(presentIntrospection(*t))
+	case dto.UserInfoOutput:
+		return json.Marshal(presentUserInfo(t))
+	case *dto.UserInfoOutput:
+		return json.Marshal(presentUserInfo(*t))
+	case dto.OpenIDConfigurationOutput:
+		return json.Marshal(presentOpenIDConfiguration(t))
+	case *dto.OpenIDConfigurationOutput:
+		return json.Marshal(presentOpenIDConfiguration(*t))
+	case dto.JWKSOutput:
+		return json.Marshal(presentJWKS(t))
+	case *dto.JWKSOutput:
+		return json.Marshal(presentJWKS(*t))
 	default:
 		return json.Marshal(v)
 	}
 }
+
+func presentLogin(t dto.LoginOutput) any {
+	if t.MFARequired {
+		return struct {
+			MFARequired bool   `json:"mfa_required"`
+			MFAToken    string `json:"mfa_token"`
+		}{t.MFARequired, t.MFAToken}
+	}
+	return struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token,omitempty"`
+	}{Token: t.Token, RefreshToken: t.RefreshToken}
+}
+
+func presentTOTPEnrollment(t dto.EnrollTOTPOutput) any {
+	return struct {
+		Secret string `json:"secret"`
+		URI    string `json:"otpauth_uri"`
+	}{t.Secret, t.URI}
+}
+
+type passkeyDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// presentPasskeyCreationOptions renders PublicKeyCredentialCreationOptionsJSON, which
+// browsers accept through PublicKeyCredential.parseCreationOptionsFromJSON.
+func presentPasskeyCreationOptions(t dto.BeginPasskeyRegistrationOutput) any {
+	type rp struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	type user struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	}
+	type param struct {
+		Type string `json:"type"`
+		Alg  int64  `json:"alg"`
+	}
+	type selection struct {
+		ResidentKey      string `json:"residentKey"`
+		UserVerification string `json:"userVerification"`
+	}
+	params := make([]param, 0, len(t.Algorithms))
+	for _, alg := range t.Algorithms {
+		params = append(params, param{Type: "public-key", Alg: alg})
+	}
+	exclude := make([]passkeyDescriptor, 0, len(t.ExcludeCredentials))
+	for _, id := range t.ExcludeCredentials {
+		exclude = append(exclude, passkeyDescriptor{Type: "public-key", ID: id})
+	}
+	return struct {
+		RP                     rp                  `json:"rp"`
+		User                   user                `json:"user"`
+		Challenge              string              `json:"challenge"`
+		PubKeyCredParams       []param             `json:"pubKeyCredParams"`
+		Timeout                int64               `json:"timeout"`
+		ExcludeCredentials     []passkeyDescriptor `json:"excludeCredentials"`
+		AuthenticatorSelection selection           `json:"authenticatorSelection"`
+		Attestation            string              `json:"attestation"`
+	}{
+		RP:                     rp{ID: t.RPID, Name: t.RPName},
+		User:                   user{ID: t.UserHandle, Name: t.UserName, DisplayName: t.UserName},
+		Challenge:              t.Challenge,
+		PubKeyCredParams:       params,
+		Timeout:                t.Timeout,
+		ExcludeCredentials:     exclude,
+		AuthenticatorSelection: selection{ResidentKey: "required", UserVerification: "required"},
+		Attestation:            "none",
+	}
+}
+
+// presentPasskeyRequestOptions renders PublicKeyCredentialRequestOptionsJSON. No
+// allowCredentials are sent: passkeys are discoverable, so the browser lets the user pick one.
+func presentPasskeyRequestOptions(t dto.BeginPasskeyLoginOutput) any {
+	return struct {
+		Challenge        string `json:"challenge"`
+		RPID             string `json:"rpId"`
+		Timeout          int64  `json:"timeout"`
+		UserVerification string `json:"userVerification"`
+	}{t.Challenge, t.RPID, t.Timeout, "required"}
+}
+
+func presentFederatedLogin(t dto.BeginFederatedLoginOutput) any {
+	return struct {
+		AuthorizationURL string `json:"authorization_url"`
+		State            string `json:"state"`
+	}{t.AuthorizationURL, t.State}
+}
+
+func presentPasskey(t dto.PasskeyOutput) any {
+	return struct {
+		CredentialID string `json:"credential_id"`
+		Name         string `json:"name"`
+		CreatedAt    int64  `json:"created_at"`
+	}{t.CredentialID, t.Name, t.CreatedAt}
+}
+
+type sessionResponse struct {
+	SessionID  string `json:"session_id"`
+	Device     string `json:"device"`
+	UserAgent  string `json:"user_agent"`
+	IPAddress  string `json:"ip_address"`
+	CreatedAt  int64  `json:"created_at"`
+	LastSeenAt int64  `json:"last_seen_at"`
+	Current    bool   `json:"current"`
+}
+
+func presentSessions(t dto.ListSessionsOutput) any {
+	sessions := make([]sessionResponse, 0, len(t.Sessions))
+	for _, s := range t.Sessions {
+		sessions = append(sessions, sessionResponse(s))
+	}
+	return struct {
+		Sessions []sessionResponse `json:"sessions"`
+	}{sessions}
+}
+
+func presentImpersonation(t dto.ImpersonateOutput) any {
+	return struct {
+		Token     string `json:"token"`
+		TokenType string `json:"token_type"`
+		ExpiresIn int64  `json:"expires_in"`
+		UserID    int64  `json:"user_id"`
+	}{t.Token, "Bearer", t.ExpiresIn, t.UserID}
+}
+
+type personalAccessTokenResponse struct {
+	TokenID    string   `json:"token_id"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	CreatedAt  int64    `json:"created_at"`
+	ExpiresAt  int64    `json:"expires_at,omitempty"`
+	LastUsedAt int64    `json:"last_used_at,omitempty"`
+}
+
+func presentCreatedPersonalAccessToken(t dto.CreatePersonalAccessTokenOutput) any {
+	return struct {
+		personalAccessTokenResponse
+		Token string `json:"token"`
+	}{personalAccessTokenResponse(t.PersonalAccessTokenOutput), t.Token}
+}
+
+func presentPersonalAccessTokens(t dto.ListPersonalAccessTokensOutput) any {
+	tokens := make([]personalAccessTokenResponse, 0, len(t.Tokens))
+	for _, pat := range t.Tokens {
+		tokens = append(tokens, personalAccessTokenResponse(pat))
+	}
+	return struct {
+		Tokens []personalAccessTokenResponse `json:"tokens"`
+	}{tokens}
+}
+
+type jwkResponse struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+func presentJWKS(t dto.JWKSOutput) any {
+	keys := make([]jwkResponse, 0, len(t.Keys))
+	for _, k := range t.Keys {
+		keys = append(keys, jwkResponse(k))
+	}
+	return struct {
+		Keys []jwkResponse `json:"keys"`
+	}{Keys: keys}
+}
+
+func presentToken(t dto.TokenOutput) any {
+	return struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+		Scope       string `json:"scope,omitempty"`
+		IDToken     string `json:"id_token,omitempty"`
+	}{t.AccessToken, t.TokenType, t.ExpiresIn, t.Scope, t.IDToken}
+}
+
+// presentUserInfo leaves out the claims of scopes the token was not granted;
+// email_verified goes with email.
+func presentUserInfo(t dto.UserInfoOutput) any {
+	out := map[string]any{"sub": t.Sub}
+	if t.Name != "" {
+		out["name"] = t.Name
+	}
+	if t.Email != "" {
+		out["email"] = t.Email
+		out["email_verified"] = t.EmailVerified
+	}
+	return out
+}
+
+func presentOpenIDConfiguration(t dto.OpenIDConfigurationOutput) any {
+	return struct {
+		Issuer                            string   `json:"issuer"`
+		AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+		TokenEndpoint                     string   `json:"token_endpoint"`
+		UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+		JWKSURI                           string   `json:"jwks_uri"`
+		IntrospectionEndpoint             string   `json:"introspection_endpoint,omitempty"`
+		ResponseTypesSupported            []string `json:"response_types_supported"`
+		GrantTypesSupported               []string `json:"grant_types_supported"`
+		SubjectTypesSupported             []string `json:"subject_types_supported"`
+		IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+		ScopesSupported                   []string `json:"scopes_supported"`
+		ClaimsSupported                   []string `json:"claims_supported"`
+		TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+		CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	}(t)
+}
+
+func presentIntrospection(t dto.IntrospectOutput) any {
+	if !t.Active {
+		return struct {
+			Active bool `json:"active"`
+		}{}
+	}
+	type actor struct {
+		Sub string `json:"sub"`
+	}
+	var act *actor
+	if t.Act != "" {
+		act = &actor{Sub: t.Act}
+	}
+	return struct {
+		Active    bool   `json:"active"`
+		Scope     string `json:"scope,omitempty"`
+		ClientID  string `json:"client_id,omitempty"`
+		Username  string `json:"username,omitempty"`
+		TokenType string `json:"token_type,omitempty"`
+		Sub       string `json:"sub,omitempty"`
+		Jti       string `json:"jti,omitempty"`
+		Exp       int64  `json:"exp,omitempty"`
+		Iat       int64  `json:"iat,omitempty"`
+		Act       *actor `json:"act,omitempty"` // RFC 8693 section 4.1
+	}{t.Active, t.Scope, t.ClientID, t.Username, t.TokenType, t.Sub, t.Jti, t.Exp, t.Iat, act}
+}
@@ -0,0 +1,19 @@
+package domain
+
+// Audit event types.
+const (
+	AuditImpersonationStarted = "impersonation_started"
+	AuditImpersonatedRequest  = "impersonated_request"
+)
+
+// AuditEvent records an action taken on a user's account by someone else, so staff access
+// can be reviewed afterwards.
+type AuditEvent struct {
+	Type        string
+	ActorUserID int64 // the admin
+	UserID      int64 // the account acted upon
+	Reason      string
+	Method      string // request events only
+	Path        string // request events only
+	At          int64
+}
@@ -0,0 +1,18 @@
+package domain
+
+// AuthorizationCode is the single-use code of the OpenID Connect authorization code flow.
+// It is bound to the client, redirect URI and PKCE challenge of the authorization request,
+// and only the SHA-256 hash of the code is persisted.
+type AuthorizationCode struct {
+	CodeHash      string
+	ClientID      string
+	UserID        int64
+	RedirectURI   string
+	Scopes        []string
+	Nonce         string // echoed in the ID token; empty when the client sent none
+	CodeChallenge string // base64url SHA-256 of the code verifier (PKCE S256)
+	AuthTime      int64  // when the user signed in, i.e. the iat of their access token
+	CreatedAt     int64
+	ExpiresAt     int64
+	UsedAt        int64 // 0 while the code has not been redeemed
+}
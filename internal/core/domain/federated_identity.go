@@ -0,0 +1,21 @@
+package domain
+
+// ExternalIdentity is what an external identity provider asserts about a user, taken from
+// a verified ID token.
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string // the provider's stable user identifier, the sub claim
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// FederatedIdentity links an account at an external identity provider to a user, so later
+// sign-ins find the user even after either side changes its email.
+type FederatedIdentity struct {
+	Provider  string
+	Subject   string
+	UserID    int64
+	Email     string // email asserted by the provider when the link was made
+	CreatedAt int64
+}
@@ -0,0 +1,14 @@
+package domain
+
+// IDToken holds the claims of an OpenID Connect ID token about a signed-in user. Email
+// and Name are left empty when the client was not granted the email or profile scope.
+type IDToken struct {
+	UserID        int64
+	Audience      string // client ID
+	Nonce         string
+	AuthTime      int64
+	Email         string
+	EmailVerified bool
+	Name          string
+	ExpiresAt     int64
+}
@@ -0,0 +1,23 @@
+package domain
+
+// Notification types delivered through port.Notifier.
+const (
+	NotificationPasswordReset     = "password_reset"
+	NotificationEmailVerification = "email_verification"
+	NotificationAccountExists     = "account_exists"      // someone tried to register the email again
+	NotificationEmailChange       = "email_change"        // confirmation link, sent to the new address
+	NotificationEmailChangeNotice = "email_change_notice" // heads-up, sent to the old address
+	NotificationMagicLink         = "magic_link"
+)
+
+// Notification is a message for a user that usually carries a one-time token they must
+// present back to the service, such as a password reset or email verification link.
+type Notification struct {
+	Type      string
+	UserID    int64
+	To        string // email address
+	Name      string
+	Token     string // empty when the message has no link
+	Link      string // page to open with the token, for flows that have one
+	ExpiresAt int64
+}
@@ -0,0 +1,41 @@
+package domain
+
+// OAuthClient is an application registered with the service. Machine clients obtain
+// access tokens through the client credentials grant; OpenID Connect relying parties sign
+// users in through the authorization code flow and must register their redirect URIs.
+// Only the SHA-256 hash of the client secret is persisted; public clients such as
+// single-page apps have none and rely on PKCE alone.
+type OAuthClient struct {
+	ClientID     string
+	SecretHash   string // empty for public clients
+	Name         string
+	Scopes       []string // scopes the client is allowed to request in the client credentials grant
+	RedirectURIs []string // exact URIs the authorization endpoint may redirect to
+	CreatedAt    int64
+}
+
+// IsPublic reports whether the client has no secret to authenticate with.
+func (c OAuthClient) IsPublic() bool {
+	return c.SecretHash == ""
+}
+
+// AllowsRedirectURI reports whether uri is one of the client's registered redirect URIs.
+// URIs must match exactly, as OpenID Connect requires.
+func (c OAuthClient) AllowsRedirectURI(uri string) bool {
+	for _, u := range c.RedirectURIs {
+		if u == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsScope reports whether the client may request the given scope.
+func (c OAuthClient) AllowsScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package domain
+
+// Purposes of one-time tokens; a token is only accepted for the purpose it was issued for.
+const (
+	TokenPurposePasswordReset       = "password_reset"
+	TokenPurposeEmailVerification   = "email_verification"
+	TokenPurposeMFAChallenge        = "mfa_challenge"
+	TokenPurposePasskeyRegistration = "passkey_registration"
+	TokenPurposePasskeyLogin        = "passkey_login"
+	TokenPurposeEmailChange         = "email_change"
+	TokenPurposeMagicLink           = "magic_link"
+	TokenPurposeFederatedLogin      = "federated_login"
+)
+
+// OneTimeToken is a short-lived, single-use secret delivered to a user out of band, e.g. in
+// a password reset email. Only the SHA-256 hash of the token is persisted.
+type OneTimeToken struct {
+	TokenHash string
+	Purpose   string
+	UserID    int64
+	Email     string // address the token was sent to, for email change tokens
+	CreatedAt int64
+	ExpiresAt int64
+	UsedAt    int64 // 0 while the token has not been consumed
+	// Provider, Nonce and CodeVerifier belong to federated login tokens, which hold the
+	// state of a sign-in at an external identity provider.
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+}
@@ -0,0 +1,20 @@
+package domain
+
+// COSE algorithm identifiers of the passkey signatures we can verify.
+const (
+	COSEAlgES256 int64 = -7
+	COSEAlgEdDSA int64 = -8
+	COSEAlgRS256 int64 = -257
+)
+
+// PasskeyCredential is a WebAuthn public key credential registered by a user. The public
+// key is kept in its COSE_Key encoding, exactly as the authenticator reported it.
+type PasskeyCredential struct {
+	CredentialID string // base64url, as exposed to browsers
+	UserID       int64
+	PublicKey    []byte
+	SignCount    uint32 // last signature counter seen; 0 for authenticators without one
+	Name         string
+	CreatedAt    int64
+	LastUsedAt   int64
+}
@@ -0,0 +1,30 @@
+package domain
+
+// Scopes a personal access token can be limited to. Read covers GET requests and write
+// everything else; a token created without scopes gets both.
+const (
+	ScopeUsersRead  = "users:read"
+	ScopeUsersWrite = "users:write"
+)
+
+// PersonalAccessTokenScopes are the scopes users may grant their personal access tokens.
+var PersonalAccessTokenScopes = []string{ScopeUsersRead, ScopeUsersWrite}
+
+// PersonalAccessToken is a long-lived credential a user creates for scripts and CLIs.
+// Only the SHA-256 hash of the token is persisted; TokenID identifies it when listing and
+// revoking.
+type PersonalAccessToken struct {
+	TokenHash  string
+	TokenID    string
+	UserID     int64
+	Name       string
+	Scopes     []string
+	CreatedAt  int64
+	ExpiresAt  int64 // 0 for tokens that do not expire
+	LastUsedAt int64 // 0 while the token has not been used
+}
+
+// Expired reports whether the token has run out at now.
+func (t *PersonalAccessToken) Expired(now int64) bool {
+	return t.ExpiresAt != 0 && t.ExpiresAt <= now
+}
@@ -0,0 +1,73 @@
+package domain
+
+// Subject types distinguish end users from machine clients in access tokens.
+const (
+	SubjectTypeUser   = "user"
+	SubjectTypeClient = "client"
+)
+
+// Principal is the identity carried by a verified access token. It lets callers make
+// authorization decisions without reading the user back from the repository. Client
+// principals carry a ClientID and scopes instead of a user ID; user tokens issued to an
+// OpenID Connect client carry both.
+type Principal struct {
+	SubjectType string
+	UserID      int64
+	ClientID    string
+	Email       string
+	Roles       []string
+	Scopes      []string
+	TokenID     string // jti
+	SessionID   string // sid; empty for client tokens and tokens issued before sessions
+	IssuedAt    int64
+	ExpiresAt   int64
+	// PersonalAccessToken is set when the caller authenticated with a personal access token
+	// rather than a signed access token; TokenID is then the personal access token's ID.
+	PersonalAccessToken bool
+	// ActorUserID and ActorEmail identify the admin acting as UserID through an
+	// impersonation token (the act claim of RFC 8693); ActorUserID is 0 otherwise.
+	ActorUserID int64
+	ActorEmail  string
+}
+
+// IsClient reports whether the token was issued to an OAuth client rather than a user.
+func (p Principal) IsClient() bool {
+	return p.SubjectType == SubjectTypeClient
+}
+
+// IsDelegated reports whether a user token was issued to a third-party OpenID Connect
+// client rather than to our own apps.
+func (p Principal) IsDelegated() bool {
+	return !p.IsClient() && p.ClientID != ""
+}
+
+// IsPersonalAccessToken reports whether the caller authenticated with a personal access
+// token, which must not be able to manage the account's credentials.
+func (p Principal) IsPersonalAccessToken() bool {
+	return p.PersonalAccessToken
+}
+
+// IsImpersonated reports whether an admin is acting as the user with this token.
+func (p Principal) IsImpersonated() bool {
+	return p.ActorUserID != 0
+}
+
+// HasRole reports whether the principal was granted the given role.
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the token carries the given scope.
+func (p Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// RateLimit is a token bucket: it allows bursts of up to Burst requests and refills one
+// token every Interval.
+type RateLimit struct {
+	Burst    int
+	Interval time.Duration
+}
+
+// Take refills a bucket that held tokens at last and takes one token from it at now. It
+// returns the tokens left, or false and how long until a token is available.
+func (l RateLimit) Take(tokens float64, last, now time.Time) (float64, bool, time.Duration) {
+	if l.Interval > 0 && now.After(last) {
+		tokens += float64(now.Sub(last)) / float64(l.Interval)
+	}
+	tokens = min(tokens, float64(l.Burst))
+	if tokens < 1 {
+		return tokens, false, time.Duration((1 - tokens) * float64(l.Interval))
+	}
+	return tokens - 1, true, 0
+}
+
+// RefillTime is how long an empty bucket takes to fill up again; an idle bucket can be
+// forgotten after that.
+func (l RateLimit) RefillTime() time.Duration {
+	return time.Duration(l.Burst) * l.Interval
+}
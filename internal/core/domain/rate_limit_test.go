@@ -0,0 +1,106 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+func TestRateLimit_Take(t *testing.T) {
+	limit := domain.RateLimit{Burst: 5, Interval: 10 * time.Second}
+	last := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name       string
+		limit      domain.RateLimit
+		tokens     float64
+		now        time.Time
+		wantTokens float64
+		wantOK     bool
+		wantRetry  time.Duration
+	}{
+		{
+			name:       "should take a token from a full bucket",
+			limit:      limit,
+			tokens:     5,
+			now:        last,
+			wantTokens: 4,
+			wantOK:     true,
+		},
+		{
+			name:       "should take the last token",
+			limit:      limit,
+			tokens:     1,
+			now:        last,
+			wantTokens: 0,
+			wantOK:     true,
+		},
+		{
+			name:       "should refuse an empty bucket and wait a full interval",
+			limit:      limit,
+			tokens:     0,
+			now:        last,
+			wantTokens: 0,
+			wantRetry:  10 * time.Second,
+		},
+		{
+			name:       "should wait only for the missing part of a token",
+			limit:      limit,
+			tokens:     0,
+			now:        last.Add(4 * time.Second),
+			wantTokens: 0.4,
+			wantRetry:  6 * time.Second,
+		},
+		{
+			name:       "should refill one token per interval",
+			limit:      limit,
+			tokens:     0,
+			now:        last.Add(25 * time.Second),
+			wantTokens: 1.5,
+			wantOK:     true,
+		},
+		{
+			name:       "should not refill beyond the burst",
+			limit:      limit,
+			tokens:     3,
+			now:        last.Add(time.Hour),
+			wantTokens: 4,
+			wantOK:     true,
+		},
+		{
+			name:       "should cap a bucket holding more than a smaller burst",
+			limit:      domain.RateLimit{Burst: 2, Interval: 10 * time.Second},
+			tokens:     5,
+			now:        last,
+			wantTokens: 1,
+			wantOK:     true,
+		},
+		{
+			name:       "should not refill when the clock went backwards",
+			limit:      limit,
+			tokens:     0.5,
+			now:        last.Add(-time.Minute),
+			wantTokens: 0.5,
+			wantRetry:  5 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			tokens, ok, retry := tt.limit.Take(tt.tokens, last, tt.now)
+
+			// Assert
+			assert.InDelta(t, tt.wantTokens, tokens, 1e-9)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.InDelta(t, float64(tt.wantRetry), float64(retry), float64(time.Millisecond))
+		})
+	}
+}
+
+func TestRateLimit_RefillTime(t *testing.T) {
+	assert.Equal(t, 50*time.Second, domain.RateLimit{Burst: 5, Interval: 10 * time.Second}.RefillTime())
+}
@@ -0,0 +1,14 @@
+package domain
+
+// RefreshToken is a long-lived, single-use credential that can be exchanged for a new
+// access/refresh pair. Only the SHA-256 hash of the token is persisted. Tokens issued from
+// the same login share a FamilyID so that replaying a rotated token can revoke them all.
+type RefreshToken struct {
+	TokenHash string
+	FamilyID  string
+	UserID    int64
+	CreatedAt int64
+	ExpiresAt int64
+	UsedAt    int64 // 0 while the token has not been rotated yet
+	Revoked   bool
+}
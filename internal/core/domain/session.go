@@ -0,0 +1,15 @@
+package domain
+
+// Session is one login of a user on one device. Its ID is also the family ID of the
+// refresh tokens issued for the login and the sid claim of its access tokens, so ending a
+// session can revoke both.
+type Session struct {
+	SessionID  string
+	UserID     int64
+	Device     string // short description derived from the user agent, e.g. "Chrome on macOS"
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  int64
+	LastSeenAt int64 // last login or token refresh
+	ExpiresAt  int64 // when the session's refresh token runs out unless it is refreshed
+}
@@ -1,10 +1,40 @@
 package domain
 
+// RoleAdmin is granted to staff accounts, which may impersonate users.
+const RoleAdmin = "admin"
+
 type User struct {
-	UserID    int64
-	Name      string
-	Email     string
-	Password  string // hashed
-	CreatedAt int64
-	UpdatedAt int64
+	UserID        int64
+	Name          string
+	Email         string
+	PendingEmail  string // new address awaiting confirmation; empty when no change is pending
+	Password      string // hashed
+	EmailVerified bool
+	Roles         []string
+	MFAEnabled    bool
+	TOTPSecret    string // encrypted; set on enrollment, before MFA is enabled
+	TOTPLastStep  int64  // time step of the last accepted code, to refuse replays
+	FailedLogins  int    // consecutive failed password or MFA attempts
+	LockedUntil   int64  // logins are refused until then; 0 when not locked
+	// TokensValidAfter invalidates every token issued before it; set when the password changes.
+	TokensValidAfter int64
+	CreatedAt        int64
+	UpdatedAt        int64
+}
+
+// AcceptsTokenIssuedAt reports whether a token issued at the given Unix time is still
+// valid for the user, i.e. was issued after the last password change. Issue times only
+// have second resolution, so tokens from the same second as the change are rejected too.
+func (u *User) AcceptsTokenIssuedAt(issuedAt int64) bool {
+	return issuedAt > u.TokensValidAfter
+}
+
+// HasRole reports whether the user was granted the given role.
+func (u *User) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,29 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+func TestUser_AcceptsTokenIssuedAt(t *testing.T) {
+	user := &domain.User{TokensValidAfter: 1000}
+
+	tests := []struct {
+		name     string
+		issuedAt int64
+		want     bool
+	}{
+		{name: "should reject a token issued before the password change", issuedAt: 999, want: false},
+		{name: "should reject a token issued in the same second as the password change", issuedAt: 1000, want: false},
+		{name: "should accept a token issued after the password change", issuedAt: 1001, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, user.AcceptsTokenIssuedAt(tt.issuedAt))
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package dto
+
+type BeginFederatedLoginInput struct {
+	Provider string `json:"-"`
+}
+
+// BeginFederatedLoginOutput tells the app where to send the user. State comes back on the
+// provider's redirect and must be passed to FinishFederatedLogin with the code.
+type BeginFederatedLoginOutput struct {
+	AuthorizationURL string
+	State            string
+}
+
+type FinishFederatedLoginInput struct {
+	Provider string `json:"-"`
+	Code     string
+	State    string
+	Client   ClientInfo `json:"-"`
+}
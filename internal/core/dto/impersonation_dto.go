@@ -0,0 +1,13 @@
+package dto
+
+type ImpersonateInput struct {
+	AdminID int64 `json:"-"`
+	UserID  int64 `json:"-"`
+	Reason  string
+}
+
+type ImpersonateOutput struct {
+	Token     string
+	ExpiresIn int64 // seconds
+	UserID    int64
+}
@@ -0,0 +1,18 @@
+package dto
+
+// JWK is a public JSON Web Key (RFC 7517). RSA keys fill N/E, EC keys fill Crv/X/Y.
+type JWK struct {
+	Kty string
+	Use string
+	Alg string
+	Kid string
+	N   string
+	E   string
+	Crv string
+	X   string
+	Y   string
+}
+
+type JWKSOutput struct {
+	Keys []JWK
+}
@@ -0,0 +1,94 @@
+package dto
+
+type TokenInput struct {
+	ClientID     string
+	ClientSecret string
+	GrantType    string
+	Scope        string // space-delimited; empty requests every scope the client is allowed
+	// authorization_code grant only
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+}
+
+// TokenOutput is the RFC 6749 section 5.1 access token response. IDToken is only set by
+// the authorization code grant.
+type TokenOutput struct {
+	AccessToken string
+	TokenType   string
+	ExpiresIn   int64
+	Scope       string
+	IDToken     string
+}
+
+// AuthorizeInput is an OpenID Connect authentication request. UserID is the signed-in
+// user, 0 when the request carried no valid access token; Consent is their answer to the
+// consent prompt of the login page.
+type AuthorizeInput struct {
+	UserID              int64
+	AuthTime            int64
+	Consent             bool
+	Prompt              string
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// AuthorizeOutput is where the user agent is sent next: the login page, or back to the
+// client with either the authorization code or an OAuth error in the query string.
+type AuthorizeOutput struct {
+	RedirectURI string
+}
+
+// UserInfoOutput holds the OpenID Connect standard claims about the user. Email and Name
+// are empty when the token was not granted the email or profile scope.
+type UserInfoOutput struct {
+	Sub           string
+	Name          string
+	Email         string
+	EmailVerified bool
+}
+
+// OpenIDConfigurationOutput is the OpenID Connect discovery document.
+type OpenIDConfigurationOutput struct {
+	Issuer                            string
+	AuthorizationEndpoint             string
+	TokenEndpoint                     string
+	UserInfoEndpoint                  string
+	JWKSURI                           string
+	IntrospectionEndpoint             string
+	ResponseTypesSupported            []string
+	GrantTypesSupported               []string
+	SubjectTypesSupported             []string
+	IDTokenSigningAlgValuesSupported  []string
+	ScopesSupported                   []string
+	ClaimsSupported                   []string
+	TokenEndpointAuthMethodsSupported []string
+	CodeChallengeMethodsSupported     []string
+}
+
+type IntrospectInput struct {
+	ClientID      string
+	ClientSecret  string
+	Token         string
+	TokenTypeHint string
+}
+
+// IntrospectOutput follows RFC 7662; every field but Active is omitted for inactive tokens.
+type IntrospectOutput struct {
+	Active    bool
+	Scope     string
+	ClientID  string
+	Username  string
+	TokenType string
+	Sub       string
+	Jti       string
+	Exp       int64
+	Iat       int64
+	Act       string // user ID of the admin acting as Sub, for impersonation tokens
+}
@@ -0,0 +1,43 @@
+package dto
+
+// Binary WebAuthn values (challenges, credential IDs, authenticator responses) travel as
+// unpadded base64url strings, as in the WebAuthn JSON serialization.
+
+type BeginPasskeyRegistrationOutput struct {
+	Challenge          string
+	RPID               string
+	RPName             string
+	UserHandle         string
+	UserName           string
+	Algorithms         []int64 // COSE algorithms, in order of preference
+	ExcludeCredentials []string
+	Timeout            int64 // milliseconds
+}
+
+type FinishPasskeyRegistrationInput struct {
+	UserID            int64 `json:"-"`
+	Name              string
+	ClientDataJSON    string `json:"client_data_json"`
+	AttestationObject string `json:"attestation_object"`
+}
+
+type PasskeyOutput struct {
+	CredentialID string
+	Name         string
+	CreatedAt    int64
+}
+
+type BeginPasskeyLoginOutput struct {
+	Challenge string
+	RPID      string
+	Timeout   int64 // milliseconds
+}
+
+type FinishPasskeyLoginInput struct {
+	CredentialID      string `json:"credential_id"`
+	ClientDataJSON    string `json:"client_data_json"`
+	AuthenticatorData string `json:"authenticator_data"`
+	Signature         string
+	UserHandle        string     `json:"user_handle"` // optional; checked against the credential's owner when sent
+	Client            ClientInfo `json:"-"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+type CreatePersonalAccessTokenInput struct {
+	UserID        int64 `json:"-"`
+	Name          string
+	Scopes        []string
+	ExpiresInDays int `json:"expires_in_days"` // 0 for a token that does not expire
+}
+
+type PersonalAccessTokenOutput struct {
+	TokenID    string
+	Name       string
+	Scopes     []string
+	CreatedAt  int64
+	ExpiresAt  int64
+	LastUsedAt int64
+}
+
+// CreatePersonalAccessTokenOutput carries the token itself, which is only ever shown once.
+type CreatePersonalAccessTokenOutput struct {
+	PersonalAccessTokenOutput
+	Token string
+}
+
+type ListPersonalAccessTokensOutput struct {
+	Tokens []PersonalAccessTokenOutput
+}
+
+type RevokePersonalAccessTokenInput struct {
+	UserID  int64  `json:"-"`
+	TokenID string `json:"-"`
+}
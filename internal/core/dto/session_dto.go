@@ -0,0 +1,32 @@
+package dto
+
+// ClientInfo describes the device a login comes from. It is filled in from the request
+// by the API layer, never from the request body.
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+type ListSessionsInput struct {
+	UserID           int64  `json:"-"`
+	CurrentSessionID string `json:"-"`
+}
+
+type SessionOutput struct {
+	SessionID  string
+	Device     string
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  int64
+	LastSeenAt int64
+	Current    bool // the session of the token the list was requested with
+}
+
+type ListSessionsOutput struct {
+	Sessions []SessionOutput
+}
+
+type RevokeSessionInput struct {
+	UserID    int64  `json:"-"`
+	SessionID string `json:"-"`
+}
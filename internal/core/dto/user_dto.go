@@ -15,16 +15,91 @@ type RegisterOutput struct {
 type LoginInput struct {
 	Email    string
 	Password string
+	Client   ClientInfo `json:"-"`
 }
 
+// LoginOutput carries either the issued tokens or, for accounts with MFA enabled, an MFA
+// challenge token to be redeemed together with a TOTP code.
 type LoginOutput struct {
+	Token        string
+	RefreshToken string
+	MFARequired  bool
+	MFAToken     string
+}
+
+type LoginMFAInput struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string
+	Client   ClientInfo `json:"-"`
+}
+
+type EnrollTOTPOutput struct {
+	Secret string // base32, for manual entry
+	URI    string // otpauth:// URI, usually rendered as a QR code
+}
+
+type ConfirmTOTPInput struct {
+	UserID int64 `json:"-"`
+	Code   string
+}
+
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type LogoutInput struct {
+	AccessToken  string `json:"-"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type ForgotPasswordInput struct {
+	Email string
+}
+
+type MagicLinkInput struct {
+	Email string
+}
+
+type ConsumeMagicLinkInput struct {
+	Token  string
+	Client ClientInfo `json:"-"`
+}
+
+type ResetPasswordInput struct {
+	Token       string
+	NewPassword string `json:"new_password"`
+}
+
+type ChangePasswordInput struct {
+	UserID          int64  `json:"-"`
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type ChangeEmailInput struct {
+	UserID   int64  `json:"-"`
+	NewEmail string `json:"new_email"`
+	Password string
+}
+
+type ConfirmEmailChangeInput struct {
 	Token string
 }
 
+type VerifyEmailInput struct {
+	Token string
+}
+
+type ResendVerificationInput struct {
+	Email string
+}
+
 type GetMeOutput struct {
-	UserID int64
-	Name   string
-	Email  string
+	UserID        int64
+	Name          string
+	Email         string
+	EmailVerified bool
+	PendingEmail  string
 }
 
 type GetUserByIDOutput struct {
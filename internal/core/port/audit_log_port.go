@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+// AuditLog keeps the audit trail of staff actions on user accounts.
+type AuditLog interface {
+	Record(ctx context.Context, e domain.AuditEvent) error
+}
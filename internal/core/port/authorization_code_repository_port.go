@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+type AuthorizationCodeRepository interface {
+	Create(ctx context.Context, c *domain.AuthorizationCode) error
+	// Consume atomically marks an unused, unexpired code as used and returns it. It returns
+	// nil, nil when there is no such code.
+	Consume(ctx context.Context, codeHash string, now int64) (*domain.AuthorizationCode, error)
+}
@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+type FederatedIdentityRepository interface {
+	// Create links an external identity to a user. It returns false when the identity is
+	// already linked.
+	Create(ctx context.Context, f *domain.FederatedIdentity) (bool, error)
+	// Get returns the link of an external identity, or nil, nil when it has none.
+	Get(ctx context.Context, provider, subject string) (*domain.FederatedIdentity, error)
+}
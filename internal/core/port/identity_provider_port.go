@@ -0,0 +1,18 @@
+package port
+
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+// IdentityProvider is an external OpenID Connect provider users can sign in with, e.g.
+// Google or a corporate IdP. We are its relying party and use the authorization code flow.
+type IdentityProvider interface {
+	// AuthorizationURL returns where to send the user to sign in at the provider. state,
+	// nonce and the S256 PKCE codeChallenge are echoed back by the provider.
+	AuthorizationURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange redeems an authorization code and returns the identity asserted by the ID
+	// token, after checking its signature, issuer, audience, expiry and nonce.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.ExternalIdentity, error)
+}
@@ -1,6 +1,20 @@
 package port
 
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+)
+
 type JWTSigner interface {
-	Sign(userID int64) (string, error)
-	Verify(tokenStr string) (int64, error) // returns userID
+	Sign(p domain.Principal) (string, error)      // p.ExpiresAt, when set, overrides the default lifetime; p.ActorUserID adds an act claim
+	SignIDToken(t domain.IDToken) (string, error) // OpenID Connect ID token; never accepted as an access token
+	// Verify returns the token's user and, for impersonation tokens, the admin acting as them
+	// (0 otherwise); it fails for client tokens.
+	Verify(ctx context.Context, tokenStr string) (userID, actorUserID int64, err error)
+	VerifyPrincipal(ctx context.Context, tokenStr string) (*domain.Principal, error)
+	Revoke(ctx context.Context, tokenStr string) error
+	RevokeSession(ctx context.Context, sessionID string) error // rejects every token carrying the sid
+	JWKS() dto.JWKSOutput                                      // public verification keys; empty for shared-secret algorithms
 }
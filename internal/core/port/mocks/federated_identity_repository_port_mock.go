@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/port/federated_identity_repository_port.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/core/port/federated_identity_repository_port.go -destination=internal/core/port/mocks/federated_identity_repository_port_mock.go
+//
+
+// Package mock_port is a generated GoMock package.
+package mock_port
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFederatedIdentityRepository is a mock of FederatedIdentityRepository interface.
+type MockFederatedIdentityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFederatedIdentityRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFederatedIdentityRepositoryMockRecorder is the mock recorder for MockFederatedIdentityRepository.
+type MockFederatedIdentityRepositoryMockRecorder struct {
+	mock *MockFederatedIdentityRepository
+}
+
+// NewMockFederatedIdentityRepository creates a new mock instance.
+func NewMockFederatedIdentityRepository(ctrl *gomock.Controller) *MockFederatedIdentityRepository {
+	mock := &MockFederatedIdentityRepository{ctrl: ctrl}
+	mock.recorder = &MockFederatedIdentityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFederatedIdentityRepository) EXPECT() *MockFederatedIdentityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockFederatedIdentityRepository) Create(ctx context.Context, f *domain.FederatedIdentity) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, f)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFederatedIdentityRepositoryMockRecorder) Create(ctx, f any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFederatedIdentityRepository)(nil).Create), ctx, f)
+}
+
+// Get mocks base method.
+func (m *MockFederatedIdentityRepository) Get(ctx context.Context, provider, subject string) (*domain.FederatedIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, provider, subject)
+	ret0, _ := ret[0].(*domain.FederatedIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockFederatedIdentityRepositoryMockRecorder) Get(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockFederatedIdentityRepository)(nil).Get), ctx, provider, subject)
+}
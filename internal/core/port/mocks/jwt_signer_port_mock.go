@@ -10,8 +10,11 @@
 package mock_port
 
 import (
+	context "context"
 	reflect "reflect"
 
+	domain "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	dto "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -39,32 +42,105 @@ func (m *MockJWTSigner) EXPECT() *MockJWTSignerMockRecorder {
 	return m.recorder
 }
 
+// JWKS mocks base method.
+func (m *MockJWTSigner) JWKS() dto.JWKSOutput {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(dto.JWKSOutput)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockJWTSignerMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockJWTSigner)(nil).JWKS))
+}
+
+// Revoke mocks base method.
+func (m *MockJWTSigner) Revoke(ctx context.Context, tokenStr string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, tokenStr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockJWTSignerMockRecorder) Revoke(ctx, tokenStr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockJWTSigner)(nil).Revoke), ctx, tokenStr)
+}
+
+// RevokeSession mocks base method.
+func (m *MockJWTSigner) RevokeSession(ctx context.Context, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockJWTSignerMockRecorder) RevokeSession(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockJWTSigner)(nil).RevokeSession), ctx, sessionID)
+}
+
 // Sign mocks base method.
-func (m *MockJWTSigner) Sign(userID int64) (string, error) {
+func (m *MockJWTSigner) Sign(p domain.Principal) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Sign", userID)
+	ret := m.ctrl.Call(m, "Sign", p)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Sign indicates an expected call of Sign.
-func (mr *MockJWTSignerMockRecorder) Sign(userID any) *gomock.Call {
+func (mr *MockJWTSignerMockRecorder) Sign(p any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockJWTSigner)(nil).Sign), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockJWTSigner)(nil).Sign), p)
+}
+
+// SignIDToken mocks base method.
+func (m *MockJWTSigner) SignIDToken(t domain.IDToken) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignIDToken", t)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignIDToken indicates an expected call of SignIDToken.
+func (mr *MockJWTSignerMockRecorder) SignIDToken(t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIDToken", reflect.TypeOf((*MockJWTSigner)(nil).SignIDToken), t)
 }
 
 // Verify mocks base method.
-func (m *MockJWTSigner) Verify(tokenStr string) (int64, error) {
+func (m *MockJWTSigner) Verify(ctx context.Context, tokenStr string) (int64, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Verify", tokenStr)
+	ret := m.ctrl.Call(m, "Verify", ctx, tokenStr)
 	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockJWTSignerMockRecorder) Verify(ctx, tokenStr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockJWTSigner)(nil).Verify), ctx, tokenStr)
+}
+
+// VerifyPrincipal mocks base method.
+func (m *MockJWTSigner) VerifyPrincipal(ctx context.Context, tokenStr string) (*domain.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyPrincipal", ctx, tokenStr)
+	ret0, _ := ret[0].(*domain.Principal)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Verify indicates an expected call of Verify.
-func (mr *MockJWTSignerMockRecorder) Verify(tokenStr any) *gomock.Call {
+// VerifyPrincipal indicates an expected call of VerifyPrincipal.
+func (mr *MockJWTSignerMockRecorder) VerifyPrincipal(ctx, tokenStr any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockJWTSigner)(nil).Verify), tokenStr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPrincipal", reflect.TypeOf((*MockJWTSigner)(nil).VerifyPrincipal), ctx, tokenStr)
 }
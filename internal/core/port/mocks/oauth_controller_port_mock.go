@@ -0,0 +1,119 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/port/oauth_controller_port.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/core/port/oauth_controller_port.go -destination=internal/core/port/mocks/oauth_controller_port_mock.go
+//
+
+// Package mock_port is a generated GoMock package.
+package mock_port
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	dto "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+	port "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOAuthController is a mock of OAuthController interface.
+type MockOAuthController struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockOAuthControllerMockRecorder is the mock recorder for MockOAuthController.
+type MockOAuthControllerMockRecorder struct {
+	mock *MockOAuthController
+}
+
+// NewMockOAuthController creates a new mock instance.
+func NewMockOAuthController(ctrl *gomock.Controller) *MockOAuthController {
+	mock := &MockOAuthController{ctrl: ctrl}
+	mock.recorder = &MockOAuthControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthController) EXPECT() *MockOAuthControllerMockRecorder {
+	return m.recorder
+}
+
+// Authorize mocks base method.
+func (m *MockOAuthController) Authorize(ctx context.Context, in dto.AuthorizeInput) (*dto.AuthorizeOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, in)
+	ret0, _ := ret[0].(*dto.AuthorizeOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockOAuthControllerMockRecorder) Authorize(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockOAuthController)(nil).Authorize), ctx, in)
+}
+
+// Discovery mocks base method.
+func (m *MockOAuthController) Discovery(ctx context.Context, p port.Presenter) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Discovery", ctx, p)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Discovery indicates an expected call of Discovery.
+func (mr *MockOAuthControllerMockRecorder) Discovery(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Discovery", reflect.TypeOf((*MockOAuthController)(nil).Discovery), ctx, p)
+}
+
+// Introspect mocks base method.
+func (m *MockOAuthController) Introspect(ctx context.Context, p port.Presenter, in dto.IntrospectInput) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Introspect", ctx, p, in)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Introspect indicates an expected call of Introspect.
+func (mr *MockOAuthControllerMockRecorder) Introspect(ctx, p, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Introspect", reflect.TypeOf((*MockOAuthController)(nil).Introspect), ctx, p, in)
+}
+
+// Token mocks base method.
+func (m *MockOAuthController) Token(ctx context.Context, p port.Presenter, in dto.TokenInput) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token", ctx, p, in)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockOAuthControllerMockRecorder) Token(ctx, p, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockOAuthController)(nil).Token), ctx, p, in)
+}
+
+// UserInfo mocks base method.
+func (m *MockOAuthController) UserInfo(ctx context.Context, p port.Presenter, principal domain.Principal) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserInfo", ctx, p, principal)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserInfo indicates an expected call of UserInfo.
+func (mr *MockOAuthControllerMockRecorder) UserInfo(ctx, p, principal any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserInfo", reflect.TypeOf((*MockOAuthController)(nil).UserInfo), ctx, p, principal)
+}
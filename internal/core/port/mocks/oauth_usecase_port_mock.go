@@ -0,0 +1,118 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/port/oauth_usecase_port.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/core/port/oauth_usecase_port.go -destination=internal/core/port/mocks/oauth_usecase_port_mock.go
+//
+
+// Package mock_port is a generated GoMock package.
+package mock_port
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	dto "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOAuthUseCase is a mock of OAuthUseCase interface.
+type MockOAuthUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthUseCaseMockRecorder
+	isgomock struct{}
+}
+
+// MockOAuthUseCaseMockRecorder is the mock recorder for MockOAuthUseCase.
+type MockOAuthUseCaseMockRecorder struct {
+	mock *MockOAuthUseCase
+}
+
+// NewMockOAuthUseCase creates a new mock instance.
+func NewMockOAuthUseCase(ctrl *gomock.Controller) *MockOAuthUseCase {
+	mock := &MockOAuthUseCase{ctrl: ctrl}
+	mock.recorder = &MockOAuthUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthUseCase) EXPECT() *MockOAuthUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Authorize mocks base method.
+func (m *MockOAuthUseCase) Authorize(ctx context.Context, in dto.AuthorizeInput) (*dto.AuthorizeOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, in)
+	ret0, _ := ret[0].(*dto.AuthorizeOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockOAuthUseCaseMockRecorder) Authorize(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockOAuthUseCase)(nil).Authorize), ctx, in)
+}
+
+// Discovery mocks base method.
+func (m *MockOAuthUseCase) Discovery(ctx context.Context) (*dto.OpenIDConfigurationOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Discovery", ctx)
+	ret0, _ := ret[0].(*dto.OpenIDConfigurationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Discovery indicates an expected call of Discovery.
+func (mr *MockOAuthUseCaseMockRecorder) Discovery(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Discovery", reflect.TypeOf((*MockOAuthUseCase)(nil).Discovery), ctx)
+}
+
+// Introspect mocks base method.
+func (m *MockOAuthUseCase) Introspect(ctx context.Context, in dto.IntrospectInput) (*dto.IntrospectOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Introspect", ctx, in)
+	ret0, _ := ret[0].(*dto.IntrospectOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Introspect indicates an expected call of Introspect.
+func (mr *MockOAuthUseCaseMockRecorder) Introspect(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Introspect", reflect.TypeOf((*MockOAuthUseCase)(nil).Introspect), ctx, in)
+}
+
+// Token mocks base method.
+func (m *MockOAuthUseCase) Token(ctx context.Context, in dto.TokenInput) (*dto.TokenOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token", ctx, in)
+	ret0, _ := ret[0].(*dto.TokenOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockOAuthUseCaseMockRecorder) Token(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockOAuthUseCase)(nil).Token), ctx, in)
+}
+
+// UserInfo mocks base method.
+func (m *MockOAuthUseCase) UserInfo(ctx context.Context, p domain.Principal) (*dto.UserInfoOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserInfo", ctx, p)
+	ret0, _ := ret[0].(*dto.UserInfoOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserInfo indicates an expected call of UserInfo.
+func (mr *MockOAuthUseCaseMockRecorder) UserInfo(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserInfo", reflect.TypeOf((*MockOAuthUseCase)(nil).UserInfo), ctx, p)
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/port/one_time_token_repository_port.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/core/port/one_time_token_repository_port.go -destination=internal/core/port/mocks/one_time_token_repository_port_mock.go
+//
+
+// Package mock_port is a generated GoMock package.
+package mock_port
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOneTimeTokenRepository is a mock of OneTimeTokenRepository interface.
+type MockOneTimeTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOneTimeTokenRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOneTimeTokenRepositoryMockRecorder is the mock recorder for MockOneTimeTokenRepository.
+type MockOneTimeTokenRepositoryMockRecorder struct {
+	mock *MockOneTimeTokenRepository
+}
+
+// NewMockOneTimeTokenRepository creates a new mock instance.
+func NewMockOneTimeTokenRepository(ctrl *gomock.Controller) *MockOneTimeTokenRepository {
+	mock := &MockOneTimeTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockOneTimeTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOneTimeTokenRepository) EXPECT() *MockOneTimeTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockOneTimeTokenRepository) Consume(ctx context.Context, tokenHash, purpose string, now int64) (*domain.OneTimeToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, tokenHash, purpose, now)
+	ret0, _ := ret[0].(*domain.OneTimeToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockOneTimeTokenRepositoryMockRecorder) Consume(ctx, tokenHash, purpose, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockOneTimeTokenRepository)(nil).Consume), ctx, tokenHash, purpose, now)
+}
+
+// Create mocks base method.
+func (m *MockOneTimeTokenRepository) Create(ctx context.Context, t *domain.OneTimeToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOneTimeTokenRepositoryMockRecorder) Create(ctx, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOneTimeTokenRepository)(nil).Create), ctx, t)
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/port/passkey_credential_repository_port.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/core/port/passkey_credential_repository_port.go -destination=internal/core/port/mocks/passkey_credential_repository_port_mock.go
+//
+
+// Package mock_port is a generated GoMock package.
+package mock_port
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPasskeyCredentialRepository is a mock of PasskeyCredentialRepository interface.
+type MockPasskeyCredentialRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasskeyCredentialRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPasskeyCredentialRepositoryMockRecorder is the mock recorder for MockPasskeyCredentialRepository.
+type MockPasskeyCredentialRepositoryMockRecorder struct {
+	mock *MockPasskeyCredentialRepository
+}
+
+// NewMockPasskeyCredentialRepository creates a new mock instance.
+func NewMockPasskeyCredentialRepository(ctrl *gomock.Controller) *MockPasskeyCredentialRepository {
+	mock := &MockPasskeyCredentialRepository{ctrl: ctrl}
+	mock.recorder = &MockPasskeyCredentialRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasskeyCredentialRepository) EXPECT() *MockPasskeyCredentialRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPasskeyCredentialRepository) Create(ctx context.Context, c *domain.PasskeyCredential) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, c)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPasskeyCredentialRepositoryMockRecorder) Create(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPasskeyCredentialRepository)(nil).Create), ctx, c)
+}
+
+// GetByID mocks base method.
+func (m *MockPasskeyCredentialRepository) GetByID(ctx context.Context, credentialID string) (*domain.PasskeyCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, credentialID)
+	ret0, _ := ret[0].(*domain.PasskeyCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPasskeyCredentialRepositoryMockRecorder) GetByID(ctx, credentialID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPasskeyCredentialRepository)(nil).GetByID), ctx, credentialID)
+}
+
+// ListByUser mocks base method.
+func (m *MockPasskeyCredentialRepository) ListByUser(ctx context.Context, userID int64) ([]*domain.PasskeyCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]*domain.PasskeyCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockPasskeyCredentialRepositoryMockRecorder) ListByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockPasskeyCredentialRepository)(nil).ListByUser), ctx, userID)
+}
+
+// UpdateSignCount mocks base method.
+func (m *MockPasskeyCredentialRepository) UpdateSignCount(ctx context.Context, credentialID string, signCount uint32, usedAt int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSignCount", ctx, credentialID, signCount, usedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSignCount indicates an expected call of UpdateSignCount.
+func (mr *MockPasskeyCredentialRepositoryMockRecorder) UpdateSignCount(ctx, credentialID, signCount, usedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSignCount", reflect.TypeOf((*MockPasskeyCredentialRepository)(nil).UpdateSignCount), ctx, credentialID, signCount, usedAt)
+}
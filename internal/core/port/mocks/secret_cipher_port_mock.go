@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/port/secret_cipher_port.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/core/port/secret_cipher_port.go -destination=internal/core/port/mocks/secret_cipher_port_mock.go
+//
+
+// Package mock_port is a generated GoMock package.
+package mock_port
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSecretCipher is a mock of SecretCipher interface.
+type MockSecretCipher struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecretCipherMockRecorder
+	isgomock struct{}
+}
+
+// MockSecretCipherMockRecorder is the mock recorder for MockSecretCipher.
+type MockSecretCipherMockRecorder struct {
+	mock *MockSecretCipher
+}
+
+// NewMockSecretCipher creates a new mock instance.
+func NewMockSecretCipher(ctrl *gomock.Controller) *MockSecretCipher {
+	mock := &MockSecretCipher{ctrl: ctrl}
+	mock.recorder = &MockSecretCipherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSecretCipher) EXPECT() *MockSecretCipherMockRecorder {
+	return m.recorder
+}
+
+// Decrypt mocks base method.
+func (m *MockSecretCipher) Decrypt(ciphertext string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decrypt", ciphertext)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decrypt indicates an expected call of Decrypt.
+func (mr *MockSecretCipherMockRecorder) Decrypt(ciphertext any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrypt", reflect.TypeOf((*MockSecretCipher)(nil).Decrypt), ciphertext)
+}
+
+// Encrypt mocks base method.
+func (m *MockSecretCipher) Encrypt(plaintext []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Encrypt", plaintext)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Encrypt indicates an expected call of Encrypt.
+func (mr *MockSecretCipherMockRecorder) Encrypt(plaintext any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encrypt", reflect.TypeOf((*MockSecretCipher)(nil).Encrypt), plaintext)
+}
@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	domain "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
 	dto "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
 	port "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
 	gomock "go.uber.org/mock/gomock"
@@ -42,6 +43,212 @@ func (m *MockUserController) EXPECT() *MockUserControllerMockRecorder {
 	return m.recorder
 }
 
+// Authenticate mocks base method.
+func (m *MockUserController) Authenticate(ctx context.Context, token string) (*domain.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, token)
+	ret0, _ := ret[0].(*domain.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockUserControllerMockRecorder) Authenticate(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockUserController)(nil).Authenticate), ctx, token)
+}
+
+// BeginFederatedLogin mocks base method.
+func (m *MockUserController) BeginFederatedLogin(ctx context.Context, p port.Presenter, in dto.BeginFederatedLoginInput) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginFederatedLogin", ctx, p, in)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginFederatedLogin indicates an expected call of BeginFederatedLogin.
+func (mr *MockUserControllerMockRecorder) BeginFederatedLogin(ctx, p, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginFederatedLogin", reflect.TypeOf((*MockUserController)(nil).BeginFederatedLogin), ctx, p, in)
+}
+
+// BeginPasskeyLogin mocks base method.
+func (m *MockUserController) BeginPasskeyLogin(ctx context.Context, p port.Presenter) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginPasskeyLogin", ctx, p)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginPasskeyLogin indicates an expected call of BeginPasskeyLogin.
+func (mr *MockUserControllerMockRecorder) BeginPasskeyLogin(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginPasskeyLogin", reflect.TypeOf((*MockUserController)(nil).BeginPasskeyLogin), ctx, p)
+}
+
+// BeginPasskeyRegistration mocks base method.
+func (m *MockUserController) BeginPasskeyRegistration(ctx context.Context, p port.Presenter, userID int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginPasskeyRegistration", ctx, p, userID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginPasskeyRegistration indicates an expected call of BeginPasskeyRegistration.
+func (mr *MockUserControllerMockRecorder) BeginPasskeyRegistration(ctx, p, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginPasskeyRegistration", reflect.TypeOf((*MockUserController)(nil).BeginPasskeyRegistration), ctx, p, userID)
+}
+
+// ChangePassword mocks base method.
+func (m *MockUserController) ChangePassword(ctx context.Context, in dto.ChangePasswordInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserControllerMockRecorder) ChangePassword(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserController)(nil).ChangePassword), ctx, in)
+}
+
+// ConfirmEmailChange mocks base method.
+func (m *MockUserController) ConfirmEmailChange(ctx context.Context, in dto.ConfirmEmailChangeInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmEmailChange", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmEmailChange indicates an expected call of ConfirmEmailChange.
+func (mr *MockUserControllerMockRecorder) ConfirmEmailChange(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmEmailChange", reflect.TypeOf((*MockUserController)(nil).ConfirmEmailChange), ctx, in)
+}
+
+// ConfirmTOTP mocks base method.
+func (m *MockUserController) ConfirmTOTP(ctx context.Context, in dto.ConfirmTOTPInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTP", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmTOTP indicates an expected call of ConfirmTOTP.
+func (mr *MockUserControllerMockRecorder) ConfirmTOTP(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTP", reflect.TypeOf((*MockUserController)(nil).ConfirmTOTP), ctx, in)
+}
+
+// ConsumeMagicLink mocks base method.
+func (m *MockUserController) ConsumeMagicLink(ctx context.Context, p port.Presenter, in dto.ConsumeMagicLinkInput) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeMagicLink", ctx, p, in)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeMagicLink indicates an expected call of ConsumeMagicLink.
+func (mr *MockUserControllerMockRecorder) ConsumeMagicLink(ctx, p, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeMagicLink", reflect.TypeOf((*MockUserController)(nil).ConsumeMagicLink), ctx, p, in)
+}
+
+// CreatePersonalAccessToken mocks base method.
+func (m *MockUserController) CreatePersonalAccessToken(ctx context.Context, p port.Presenter, in dto.CreatePersonalAccessTokenInput) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePersonalAccessToken", ctx, p, in)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePersonalAccessToken indicates an expected call of CreatePersonalAccessToken.
+func (mr *MockUserControllerMockRecorder) CreatePersonalAccessToken(ctx, p, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePersonalAccessToken", reflect.TypeOf((*MockUserController)(nil).CreatePersonalAccessToken), ctx, p, in)
+}
+
+// EnrollTOTP mocks base method.
+func (m *MockUserController) EnrollTOTP(ctx context.Context, p port.Presenter, userID int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTOTP", ctx, p, userID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTOTP indicates an expected call of EnrollTOTP.
+func (mr *MockUserControllerMockRecorder) EnrollTOTP(ctx, p, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTOTP", reflect.TypeOf((*MockUserController)(nil).EnrollTOTP), ctx, p, userID)
+}
+
+// FinishFederatedLogin mocks base method.
+func (m *MockUserController) FinishFederatedLogin(ctx context.Context, p port.Presenter, in dto.FinishFederatedLoginInput) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishFederatedLogin", ctx, p, in)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishFederatedLogin indicates an expected call of FinishFederatedLogin.
+func (mr *MockUserControllerMockRecorder) FinishFederatedLogin(ctx, p, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishFederatedLogin", reflect.TypeOf((*MockUserController)(nil).FinishFederatedLogin), ctx, p, in)
+}
+
+// FinishPasskeyLogin mocks base method.
+func (m *MockUserController) FinishPasskeyLogin(ctx context.Context, p port.Presenter, in dto.FinishPasskeyLoginInput) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishPasskeyLogin", ctx, p, in)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishPasskeyLogin indicates an expected call of FinishPasskeyLogin.
+func (mr *MockUserControllerMockRecorder) FinishPasskeyLogin(ctx, p, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishPasskeyLogin", reflect.TypeOf((*MockUserController)(nil).FinishPasskeyLogin), ctx, p, in)
+}
+
+// FinishPasskeyRegistration mocks base method.
+func (m *MockUserController) FinishPasskeyRegistration(ctx context.Context, p port.Presenter, in dto.FinishPasskeyRegistrationInput) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishPasskeyRegistration", ctx, p, in)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishPasskeyRegistration indicates an expected call of FinishPasskeyRegistration.
+func (mr *MockUserControllerMockRecorder) FinishPasskeyRegistration(ctx, p, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishPasskeyRegistration", reflect.TypeOf((*MockUserController)(nil).FinishPasskeyRegistration), ctx, p, in)
+}
+
+// ForgotPassword mocks base method.
+func (m *MockUserController) ForgotPassword(ctx context.Context, in dto.ForgotPasswordInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockUserControllerMockRecorder) ForgotPassword(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockUserController)(nil).ForgotPassword), ctx, in)
+}
+
 // GetMe mocks base method.
 func (m *MockUserController) GetMe(ctx context.Context, p port.Presenter, userID int64) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -72,6 +279,51 @@ func (mr *MockUserControllerMockRecorder) GetUserByID(ctx, p, userID any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserController)(nil).GetUserByID), ctx, p, userID)
 }
 
+// Impersonate mocks base method.
+func (m *MockUserController) Impersonate(ctx context.Context, p port.Presenter, in dto.ImpersonateInput) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Impersonate", ctx, p, in)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Impersonate indicates an expected call of Impersonate.
+func (mr *MockUserControllerMockRecorder) Impersonate(ctx, p, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Impersonate", reflect.TypeOf((*MockUserController)(nil).Impersonate), ctx, p, in)
+}
+
+// ListPersonalAccessTokens mocks base method.
+func (m *MockUserController) ListPersonalAccessTokens(ctx context.Context, p port.Presenter, userID int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPersonalAccessTokens", ctx, p, userID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPersonalAccessTokens indicates an expected call of ListPersonalAccessTokens.
+func (mr *MockUserControllerMockRecorder) ListPersonalAccessTokens(ctx, p, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPersonalAccessTokens", reflect.TypeOf((*MockUserController)(nil).ListPersonalAccessTokens), ctx, p, userID)
+}
+
+// ListSessions mocks base method.
+func (m *MockUserController) ListSessions(ctx context.Context, p port.Presenter, in dto.ListSessionsInput) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", ctx, p, in)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockUserControllerMockRecorder) ListSessions(ctx, p, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockUserController)(nil).ListSessions), ctx, p, in)
+}
+
 // Login mocks base method.
 func (m *MockUserController) Login(ctx context.Context, p port.Presenter, in dto.LoginInput) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -87,6 +339,50 @@ func (mr *MockUserControllerMockRecorder) Login(ctx, p, in any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserController)(nil).Login), ctx, p, in)
 }
 
+// LoginMFA mocks base method.
+func (m *MockUserController) LoginMFA(ctx context.Context, p port.Presenter, in dto.LoginMFAInput) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginMFA", ctx, p, in)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginMFA indicates an expected call of LoginMFA.
+func (mr *MockUserControllerMockRecorder) LoginMFA(ctx, p, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginMFA", reflect.TypeOf((*MockUserController)(nil).LoginMFA), ctx, p, in)
+}
+
+// Logout mocks base method.
+func (m *MockUserController) Logout(ctx context.Context, in dto.LogoutInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUserControllerMockRecorder) Logout(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUserController)(nil).Logout), ctx, in)
+}
+
+// Refresh mocks base method.
+func (m *MockUserController) Refresh(ctx context.Context, p port.Presenter, in dto.RefreshInput) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, p, in)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockUserControllerMockRecorder) Refresh(ctx, p, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockUserController)(nil).Refresh), ctx, p, in)
+}
+
 // Register mocks base method.
 func (m *MockUserController) Register(ctx context.Context, p port.Presenter, in dto.RegisterInput) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -101,3 +397,101 @@ func (mr *MockUserControllerMockRecorder) Register(ctx, p, in any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserController)(nil).Register), ctx, p, in)
 }
+
+// RequestEmailChange mocks base method.
+func (m *MockUserController) RequestEmailChange(ctx context.Context, in dto.ChangeEmailInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestEmailChange", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestEmailChange indicates an expected call of RequestEmailChange.
+func (mr *MockUserControllerMockRecorder) RequestEmailChange(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestEmailChange", reflect.TypeOf((*MockUserController)(nil).RequestEmailChange), ctx, in)
+}
+
+// RequestMagicLink mocks base method.
+func (m *MockUserController) RequestMagicLink(ctx context.Context, in dto.MagicLinkInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestMagicLink", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestMagicLink indicates an expected call of RequestMagicLink.
+func (mr *MockUserControllerMockRecorder) RequestMagicLink(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestMagicLink", reflect.TypeOf((*MockUserController)(nil).RequestMagicLink), ctx, in)
+}
+
+// ResendVerification mocks base method.
+func (m *MockUserController) ResendVerification(ctx context.Context, in dto.ResendVerificationInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerification", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendVerification indicates an expected call of ResendVerification.
+func (mr *MockUserControllerMockRecorder) ResendVerification(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerification", reflect.TypeOf((*MockUserController)(nil).ResendVerification), ctx, in)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUserController) ResetPassword(ctx context.Context, in dto.ResetPasswordInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserControllerMockRecorder) ResetPassword(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserController)(nil).ResetPassword), ctx, in)
+}
+
+// RevokePersonalAccessToken mocks base method.
+func (m *MockUserController) RevokePersonalAccessToken(ctx context.Context, in dto.RevokePersonalAccessTokenInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokePersonalAccessToken", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokePersonalAccessToken indicates an expected call of RevokePersonalAccessToken.
+func (mr *MockUserControllerMockRecorder) RevokePersonalAccessToken(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokePersonalAccessToken", reflect.TypeOf((*MockUserController)(nil).RevokePersonalAccessToken), ctx, in)
+}
+
+// RevokeSession mocks base method.
+func (m *MockUserController) RevokeSession(ctx context.Context, in dto.RevokeSessionInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockUserControllerMockRecorder) RevokeSession(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockUserController)(nil).RevokeSession), ctx, in)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockUserController) VerifyEmail(ctx context.Context, in dto.VerifyEmailInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockUserControllerMockRecorder) VerifyEmail(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockUserController)(nil).VerifyEmail), ctx, in)
+}
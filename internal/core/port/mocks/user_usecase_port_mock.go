@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	domain "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
 	dto "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,6 +42,212 @@ func (m *MockUserUseCase) EXPECT() *MockUserUseCaseMockRecorder {
 	return m.recorder
 }
 
+// Authenticate mocks base method.
+func (m *MockUserUseCase) Authenticate(ctx context.Context, token string) (*domain.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, token)
+	ret0, _ := ret[0].(*domain.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockUserUseCaseMockRecorder) Authenticate(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockUserUseCase)(nil).Authenticate), ctx, token)
+}
+
+// BeginFederatedLogin mocks base method.
+func (m *MockUserUseCase) BeginFederatedLogin(ctx context.Context, in dto.BeginFederatedLoginInput) (*dto.BeginFederatedLoginOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginFederatedLogin", ctx, in)
+	ret0, _ := ret[0].(*dto.BeginFederatedLoginOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginFederatedLogin indicates an expected call of BeginFederatedLogin.
+func (mr *MockUserUseCaseMockRecorder) BeginFederatedLogin(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginFederatedLogin", reflect.TypeOf((*MockUserUseCase)(nil).BeginFederatedLogin), ctx, in)
+}
+
+// BeginPasskeyLogin mocks base method.
+func (m *MockUserUseCase) BeginPasskeyLogin(ctx context.Context) (*dto.BeginPasskeyLoginOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginPasskeyLogin", ctx)
+	ret0, _ := ret[0].(*dto.BeginPasskeyLoginOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginPasskeyLogin indicates an expected call of BeginPasskeyLogin.
+func (mr *MockUserUseCaseMockRecorder) BeginPasskeyLogin(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginPasskeyLogin", reflect.TypeOf((*MockUserUseCase)(nil).BeginPasskeyLogin), ctx)
+}
+
+// BeginPasskeyRegistration mocks base method.
+func (m *MockUserUseCase) BeginPasskeyRegistration(ctx context.Context, userID int64) (*dto.BeginPasskeyRegistrationOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginPasskeyRegistration", ctx, userID)
+	ret0, _ := ret[0].(*dto.BeginPasskeyRegistrationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginPasskeyRegistration indicates an expected call of BeginPasskeyRegistration.
+func (mr *MockUserUseCaseMockRecorder) BeginPasskeyRegistration(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginPasskeyRegistration", reflect.TypeOf((*MockUserUseCase)(nil).BeginPasskeyRegistration), ctx, userID)
+}
+
+// ChangePassword mocks base method.
+func (m *MockUserUseCase) ChangePassword(ctx context.Context, in dto.ChangePasswordInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserUseCaseMockRecorder) ChangePassword(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserUseCase)(nil).ChangePassword), ctx, in)
+}
+
+// ConfirmEmailChange mocks base method.
+func (m *MockUserUseCase) ConfirmEmailChange(ctx context.Context, in dto.ConfirmEmailChangeInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmEmailChange", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmEmailChange indicates an expected call of ConfirmEmailChange.
+func (mr *MockUserUseCaseMockRecorder) ConfirmEmailChange(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmEmailChange", reflect.TypeOf((*MockUserUseCase)(nil).ConfirmEmailChange), ctx, in)
+}
+
+// ConfirmTOTP mocks base method.
+func (m *MockUserUseCase) ConfirmTOTP(ctx context.Context, in dto.ConfirmTOTPInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTP", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmTOTP indicates an expected call of ConfirmTOTP.
+func (mr *MockUserUseCaseMockRecorder) ConfirmTOTP(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTP", reflect.TypeOf((*MockUserUseCase)(nil).ConfirmTOTP), ctx, in)
+}
+
+// ConsumeMagicLink mocks base method.
+func (m *MockUserUseCase) ConsumeMagicLink(ctx context.Context, in dto.ConsumeMagicLinkInput) (*dto.LoginOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeMagicLink", ctx, in)
+	ret0, _ := ret[0].(*dto.LoginOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeMagicLink indicates an expected call of ConsumeMagicLink.
+func (mr *MockUserUseCaseMockRecorder) ConsumeMagicLink(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeMagicLink", reflect.TypeOf((*MockUserUseCase)(nil).ConsumeMagicLink), ctx, in)
+}
+
+// CreatePersonalAccessToken mocks base method.
+func (m *MockUserUseCase) CreatePersonalAccessToken(ctx context.Context, in dto.CreatePersonalAccessTokenInput) (*dto.CreatePersonalAccessTokenOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePersonalAccessToken", ctx, in)
+	ret0, _ := ret[0].(*dto.CreatePersonalAccessTokenOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePersonalAccessToken indicates an expected call of CreatePersonalAccessToken.
+func (mr *MockUserUseCaseMockRecorder) CreatePersonalAccessToken(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePersonalAccessToken", reflect.TypeOf((*MockUserUseCase)(nil).CreatePersonalAccessToken), ctx, in)
+}
+
+// EnrollTOTP mocks base method.
+func (m *MockUserUseCase) EnrollTOTP(ctx context.Context, userID int64) (*dto.EnrollTOTPOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTOTP", ctx, userID)
+	ret0, _ := ret[0].(*dto.EnrollTOTPOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTOTP indicates an expected call of EnrollTOTP.
+func (mr *MockUserUseCaseMockRecorder) EnrollTOTP(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTOTP", reflect.TypeOf((*MockUserUseCase)(nil).EnrollTOTP), ctx, userID)
+}
+
+// FinishFederatedLogin mocks base method.
+func (m *MockUserUseCase) FinishFederatedLogin(ctx context.Context, in dto.FinishFederatedLoginInput) (*dto.LoginOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishFederatedLogin", ctx, in)
+	ret0, _ := ret[0].(*dto.LoginOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishFederatedLogin indicates an expected call of FinishFederatedLogin.
+func (mr *MockUserUseCaseMockRecorder) FinishFederatedLogin(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishFederatedLogin", reflect.TypeOf((*MockUserUseCase)(nil).FinishFederatedLogin), ctx, in)
+}
+
+// FinishPasskeyLogin mocks base method.
+func (m *MockUserUseCase) FinishPasskeyLogin(ctx context.Context, in dto.FinishPasskeyLoginInput) (*dto.LoginOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishPasskeyLogin", ctx, in)
+	ret0, _ := ret[0].(*dto.LoginOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishPasskeyLogin indicates an expected call of FinishPasskeyLogin.
+func (mr *MockUserUseCaseMockRecorder) FinishPasskeyLogin(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishPasskeyLogin", reflect.TypeOf((*MockUserUseCase)(nil).FinishPasskeyLogin), ctx, in)
+}
+
+// FinishPasskeyRegistration mocks base method.
+func (m *MockUserUseCase) FinishPasskeyRegistration(ctx context.Context, in dto.FinishPasskeyRegistrationInput) (*dto.PasskeyOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishPasskeyRegistration", ctx, in)
+	ret0, _ := ret[0].(*dto.PasskeyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishPasskeyRegistration indicates an expected call of FinishPasskeyRegistration.
+func (mr *MockUserUseCaseMockRecorder) FinishPasskeyRegistration(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishPasskeyRegistration", reflect.TypeOf((*MockUserUseCase)(nil).FinishPasskeyRegistration), ctx, in)
+}
+
+// ForgotPassword mocks base method.
+func (m *MockUserUseCase) ForgotPassword(ctx context.Context, in dto.ForgotPasswordInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockUserUseCaseMockRecorder) ForgotPassword(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockUserUseCase)(nil).ForgotPassword), ctx, in)
+}
+
 // GetMe mocks base method.
 func (m *MockUserUseCase) GetMe(ctx context.Context, userID int64) (*dto.GetMeOutput, error) {
 	m.ctrl.T.Helper()
@@ -71,6 +278,51 @@ func (mr *MockUserUseCaseMockRecorder) GetUserByID(ctx, userID any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserUseCase)(nil).GetUserByID), ctx, userID)
 }
 
+// Impersonate mocks base method.
+func (m *MockUserUseCase) Impersonate(ctx context.Context, in dto.ImpersonateInput) (*dto.ImpersonateOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Impersonate", ctx, in)
+	ret0, _ := ret[0].(*dto.ImpersonateOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Impersonate indicates an expected call of Impersonate.
+func (mr *MockUserUseCaseMockRecorder) Impersonate(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Impersonate", reflect.TypeOf((*MockUserUseCase)(nil).Impersonate), ctx, in)
+}
+
+// ListPersonalAccessTokens mocks base method.
+func (m *MockUserUseCase) ListPersonalAccessTokens(ctx context.Context, userID int64) (*dto.ListPersonalAccessTokensOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPersonalAccessTokens", ctx, userID)
+	ret0, _ := ret[0].(*dto.ListPersonalAccessTokensOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPersonalAccessTokens indicates an expected call of ListPersonalAccessTokens.
+func (mr *MockUserUseCaseMockRecorder) ListPersonalAccessTokens(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPersonalAccessTokens", reflect.TypeOf((*MockUserUseCase)(nil).ListPersonalAccessTokens), ctx, userID)
+}
+
+// ListSessions mocks base method.
+func (m *MockUserUseCase) ListSessions(ctx context.Context, in dto.ListSessionsInput) (*dto.ListSessionsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", ctx, in)
+	ret0, _ := ret[0].(*dto.ListSessionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockUserUseCaseMockRecorder) ListSessions(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockUserUseCase)(nil).ListSessions), ctx, in)
+}
+
 // Login mocks base method.
 func (m *MockUserUseCase) Login(ctx context.Context, in dto.LoginInput) (*dto.LoginOutput, error) {
 	m.ctrl.T.Helper()
@@ -86,6 +338,50 @@ func (mr *MockUserUseCaseMockRecorder) Login(ctx, in any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserUseCase)(nil).Login), ctx, in)
 }
 
+// LoginMFA mocks base method.
+func (m *MockUserUseCase) LoginMFA(ctx context.Context, in dto.LoginMFAInput) (*dto.LoginOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginMFA", ctx, in)
+	ret0, _ := ret[0].(*dto.LoginOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginMFA indicates an expected call of LoginMFA.
+func (mr *MockUserUseCaseMockRecorder) LoginMFA(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginMFA", reflect.TypeOf((*MockUserUseCase)(nil).LoginMFA), ctx, in)
+}
+
+// Logout mocks base method.
+func (m *MockUserUseCase) Logout(ctx context.Context, in dto.LogoutInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUserUseCaseMockRecorder) Logout(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUserUseCase)(nil).Logout), ctx, in)
+}
+
+// Refresh mocks base method.
+func (m *MockUserUseCase) Refresh(ctx context.Context, in dto.RefreshInput) (*dto.LoginOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, in)
+	ret0, _ := ret[0].(*dto.LoginOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockUserUseCaseMockRecorder) Refresh(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockUserUseCase)(nil).Refresh), ctx, in)
+}
+
 // Register mocks base method.
 func (m *MockUserUseCase) Register(ctx context.Context, in dto.RegisterInput) (*dto.RegisterOutput, error) {
 	m.ctrl.T.Helper()
@@ -100,3 +396,101 @@ func (mr *MockUserUseCaseMockRecorder) Register(ctx, in any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserUseCase)(nil).Register), ctx, in)
 }
+
+// RequestEmailChange mocks base method.
+func (m *MockUserUseCase) RequestEmailChange(ctx context.Context, in dto.ChangeEmailInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestEmailChange", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestEmailChange indicates an expected call of RequestEmailChange.
+func (mr *MockUserUseCaseMockRecorder) RequestEmailChange(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestEmailChange", reflect.TypeOf((*MockUserUseCase)(nil).RequestEmailChange), ctx, in)
+}
+
+// RequestMagicLink mocks base method.
+func (m *MockUserUseCase) RequestMagicLink(ctx context.Context, in dto.MagicLinkInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestMagicLink", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestMagicLink indicates an expected call of RequestMagicLink.
+func (mr *MockUserUseCaseMockRecorder) RequestMagicLink(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestMagicLink", reflect.TypeOf((*MockUserUseCase)(nil).RequestMagicLink), ctx, in)
+}
+
+// ResendVerification mocks base method.
+func (m *MockUserUseCase) ResendVerification(ctx context.Context, in dto.ResendVerificationInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerification", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendVerification indicates an expected call of ResendVerification.
+func (mr *MockUserUseCaseMockRecorder) ResendVerification(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerification", reflect.TypeOf((*MockUserUseCase)(nil).ResendVerification), ctx, in)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUserUseCase) ResetPassword(ctx context.Context, in dto.ResetPasswordInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserUseCaseMockRecorder) ResetPassword(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserUseCase)(nil).ResetPassword), ctx, in)
+}
+
+// RevokePersonalAccessToken mocks base method.
+func (m *MockUserUseCase) RevokePersonalAccessToken(ctx context.Context, in dto.RevokePersonalAccessTokenInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokePersonalAccessToken", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokePersonalAccessToken indicates an expected call of RevokePersonalAccessToken.
+func (mr *MockUserUseCaseMockRecorder) RevokePersonalAccessToken(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokePersonalAccessToken", reflect.TypeOf((*MockUserUseCase)(nil).RevokePersonalAccessToken), ctx, in)
+}
+
+// RevokeSession mocks base method.
+func (m *MockUserUseCase) RevokeSession(ctx context.Context, in dto.RevokeSessionInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockUserUseCaseMockRecorder) RevokeSession(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockUserUseCase)(nil).RevokeSession), ctx, in)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockUserUseCase) VerifyEmail(ctx context.Context, in dto.VerifyEmailInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockUserUseCaseMockRecorder) VerifyEmail(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockUserUseCase)(nil).VerifyEmail), ctx, in)
+}
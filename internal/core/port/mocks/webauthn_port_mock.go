@@ -0,0 +1,74 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/port/webauthn_port.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/core/port/webauthn_port.go -destination=internal/core/port/mocks/webauthn_port_mock.go
+//
+
+// Package mock_port is a generated GoMock package.
+package mock_port
+
+import (
+	reflect "reflect"
+
+	domain "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	dto "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWebAuthn is a mock of WebAuthn interface.
+type MockWebAuthn struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebAuthnMockRecorder
+	isgomock struct{}
+}
+
+// MockWebAuthnMockRecorder is the mock recorder for MockWebAuthn.
+type MockWebAuthnMockRecorder struct {
+	mock *MockWebAuthn
+}
+
+// NewMockWebAuthn creates a new mock instance.
+func NewMockWebAuthn(ctrl *gomock.Controller) *MockWebAuthn {
+	mock := &MockWebAuthn{ctrl: ctrl}
+	mock.recorder = &MockWebAuthnMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebAuthn) EXPECT() *MockWebAuthnMockRecorder {
+	return m.recorder
+}
+
+// VerifyAssertion mocks base method.
+func (m *MockWebAuthn) VerifyAssertion(cred *domain.PasskeyCredential, in dto.FinishPasskeyLoginInput) (string, uint32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAssertion", cred, in)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(uint32)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// VerifyAssertion indicates an expected call of VerifyAssertion.
+func (mr *MockWebAuthnMockRecorder) VerifyAssertion(cred, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAssertion", reflect.TypeOf((*MockWebAuthn)(nil).VerifyAssertion), cred, in)
+}
+
+// VerifyRegistration mocks base method.
+func (m *MockWebAuthn) VerifyRegistration(in dto.FinishPasskeyRegistrationInput) (string, *domain.PasskeyCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyRegistration", in)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*domain.PasskeyCredential)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// VerifyRegistration indicates an expected call of VerifyRegistration.
+func (mr *MockWebAuthnMockRecorder) VerifyRegistration(in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyRegistration", reflect.TypeOf((*MockWebAuthn)(nil).VerifyRegistration), in)
+}
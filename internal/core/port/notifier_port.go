@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+// Notifier delivers messages to users, e.g. by email.
+type Notifier interface {
+	Notify(ctx context.Context, n domain.Notification) error
+}
@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+type OAuthClientRepository interface {
+	// GetByID returns nil, nil when the client does not exist.
+	GetByID(ctx context.Context, clientID string) (*domain.OAuthClient, error)
+}
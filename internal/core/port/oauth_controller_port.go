@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+)
+
+type OAuthController interface {
+	Token(ctx context.Context, p Presenter, in dto.TokenInput) ([]byte, error)
+	Introspect(ctx context.Context, p Presenter, in dto.IntrospectInput) ([]byte, error)
+	Authorize(ctx context.Context, in dto.AuthorizeInput) (*dto.AuthorizeOutput, error)
+	UserInfo(ctx context.Context, p Presenter, principal domain.Principal) ([]byte, error)
+	Discovery(ctx context.Context, p Presenter) ([]byte, error)
+}
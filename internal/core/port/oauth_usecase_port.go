@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+)
+
+type OAuthUseCase interface {
+	Token(ctx context.Context, in dto.TokenInput) (*dto.TokenOutput, error)
+	Introspect(ctx context.Context, in dto.IntrospectInput) (*dto.IntrospectOutput, error)
+	Authorize(ctx context.Context, in dto.AuthorizeInput) (*dto.AuthorizeOutput, error)
+	UserInfo(ctx context.Context, p domain.Principal) (*dto.UserInfoOutput, error)
+	Discovery(ctx context.Context) (*dto.OpenIDConfigurationOutput, error)
+}
@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+type OneTimeTokenRepository interface {
+	Create(ctx context.Context, t *domain.OneTimeToken) error
+	// Consume atomically marks an unused, unexpired token of the given purpose as used and
+	// returns it. It returns nil, nil when there is no such token.
+	Consume(ctx context.Context, tokenHash, purpose string, now int64) (*domain.OneTimeToken, error)
+}
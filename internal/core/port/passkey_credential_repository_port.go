@@ -0,0 +1,18 @@
+package port
+
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+type PasskeyCredentialRepository interface {
+	// Create returns false when a credential with the same ID is already registered.
+	Create(ctx context.Context, c *domain.PasskeyCredential) (bool, error)
+	GetByID(ctx context.Context, credentialID string) (*domain.PasskeyCredential, error)
+	ListByUser(ctx context.Context, userID int64) ([]*domain.PasskeyCredential, error)
+	// UpdateSignCount stores a new signature counter, which must be higher than the stored
+	// one, or 0 for authenticators that keep no counter and still have none stored. It
+	// returns false when that no longer holds, e.g. a concurrent login used the counter.
+	UpdateSignCount(ctx context.Context, credentialID string, signCount uint32, usedAt int64) (bool, error)
+}
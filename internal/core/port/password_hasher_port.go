@@ -0,0 +1,12 @@
+package port
+
+// PasswordHasher hashes passwords into self-describing strings that carry the algorithm and
+// its parameters, so hashes made with older settings keep verifying after a change.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify reports whether password matches hash. It fails on hashes it cannot parse.
+	Verify(hash, password string) (bool, error)
+	// NeedsRehash reports whether hash was made with another algorithm or other parameters
+	// than Hash uses now.
+	NeedsRehash(hash string) bool
+}
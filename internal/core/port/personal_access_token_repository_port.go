@@ -0,0 +1,17 @@
+package port
+
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+type PersonalAccessTokenRepository interface {
+	Create(ctx context.Context, t *domain.PersonalAccessToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error)
+	// ListByUser returns the user's tokens, including expired ones not purged yet.
+	ListByUser(ctx context.Context, userID int64) ([]*domain.PersonalAccessToken, error)
+	// Touch records a use of the token; it does nothing for tokens that were deleted.
+	Touch(ctx context.Context, tokenHash string, lastUsedAt int64) error
+	Delete(ctx context.Context, tokenHash string) error
+}
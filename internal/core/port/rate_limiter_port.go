@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+// RateLimiter keeps one token bucket per key.
+type RateLimiter interface {
+	// Allow takes a token from the key's bucket. When the bucket is empty it returns false
+	// and how long the caller should wait before retrying.
+	Allow(ctx context.Context, key string, limit domain.RateLimit) (bool, time.Duration, error)
+}
@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, t *domain.RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	// MarkUsed atomically flags the token as rotated; it returns false when the token had already been used.
+	MarkUsed(ctx context.Context, tokenHash string, usedAt int64) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+}
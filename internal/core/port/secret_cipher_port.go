@@ -0,0 +1,7 @@
+package port
+
+// SecretCipher encrypts secrets, such as TOTP seeds, before they are persisted.
+type SecretCipher interface {
+	Encrypt(plaintext []byte) (string, error)
+	Decrypt(ciphertext string) ([]byte, error)
+}
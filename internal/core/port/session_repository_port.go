@@ -0,0 +1,18 @@
+package port
+
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, s *domain.Session) error
+	GetByID(ctx context.Context, sessionID string) (*domain.Session, error)
+	// ListByUser returns the user's sessions, including expired ones not purged yet.
+	ListByUser(ctx context.Context, userID int64) ([]*domain.Session, error)
+	// Touch records activity on a session and pushes its expiry back; it does nothing for
+	// sessions that were deleted.
+	Touch(ctx context.Context, sessionID string, lastSeenAt, expiresAt int64) error
+	Delete(ctx context.Context, sessionID string) error
+}
@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+	"time"
+)
+
+// TokenRevocationStore is a denylist of token IDs (jti) that must be rejected until they expire.
+type TokenRevocationStore interface {
+	Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+}
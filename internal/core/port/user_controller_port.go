@@ -3,12 +3,40 @@ package port
 import (
 	"context"
 
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
 )
 
 type UserController interface {
 	Register(ctx context.Context, p Presenter, in dto.RegisterInput) ([]byte, error)
 	Login(ctx context.Context, p Presenter, in dto.LoginInput) ([]byte, error)
+	LoginMFA(ctx context.Context, p Presenter, in dto.LoginMFAInput) ([]byte, error)
+	Refresh(ctx context.Context, p Presenter, in dto.RefreshInput) ([]byte, error)
+	Logout(ctx context.Context, in dto.LogoutInput) error
+	RequestMagicLink(ctx context.Context, in dto.MagicLinkInput) error
+	ConsumeMagicLink(ctx context.Context, p Presenter, in dto.ConsumeMagicLinkInput) ([]byte, error)
+	ForgotPassword(ctx context.Context, in dto.ForgotPasswordInput) error
+	ResetPassword(ctx context.Context, in dto.ResetPasswordInput) error
+	ChangePassword(ctx context.Context, in dto.ChangePasswordInput) error
+	VerifyEmail(ctx context.Context, in dto.VerifyEmailInput) error
+	RequestEmailChange(ctx context.Context, in dto.ChangeEmailInput) error
+	ConfirmEmailChange(ctx context.Context, in dto.ConfirmEmailChangeInput) error
+	ResendVerification(ctx context.Context, in dto.ResendVerificationInput) error
+	EnrollTOTP(ctx context.Context, p Presenter, userID int64) ([]byte, error)
+	ConfirmTOTP(ctx context.Context, in dto.ConfirmTOTPInput) error
+	BeginPasskeyRegistration(ctx context.Context, p Presenter, userID int64) ([]byte, error)
+	FinishPasskeyRegistration(ctx context.Context, p Presenter, in dto.FinishPasskeyRegistrationInput) ([]byte, error)
+	BeginPasskeyLogin(ctx context.Context, p Presenter) ([]byte, error)
+	FinishPasskeyLogin(ctx context.Context, p Presenter, in dto.FinishPasskeyLoginInput) ([]byte, error)
+	BeginFederatedLogin(ctx context.Context, p Presenter, in dto.BeginFederatedLoginInput) ([]byte, error)
+	FinishFederatedLogin(ctx context.Context, p Presenter, in dto.FinishFederatedLoginInput) ([]byte, error)
+	ListSessions(ctx context.Context, p Presenter, in dto.ListSessionsInput) ([]byte, error)
+	RevokeSession(ctx context.Context, in dto.RevokeSessionInput) error
+	CreatePersonalAccessToken(ctx context.Context, p Presenter, in dto.CreatePersonalAccessTokenInput) ([]byte, error)
+	ListPersonalAccessTokens(ctx context.Context, p Presenter, userID int64) ([]byte, error)
+	RevokePersonalAccessToken(ctx context.Context, in dto.RevokePersonalAccessTokenInput) error
+	Impersonate(ctx context.Context, p Presenter, in dto.ImpersonateInput) ([]byte, error)
 	GetMe(ctx context.Context, p Presenter, userID int64) ([]byte, error)
+	Authenticate(ctx context.Context, token string) (*domain.Principal, error)
 	GetUserByID(ctx context.Context, p Presenter, userID int64) ([]byte, error)
 }
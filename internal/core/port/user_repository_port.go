@@ -10,4 +10,32 @@ type UserRepository interface {
 	Create(ctx context.Context, u *domain.User) error
 	GetByID(ctx context.Context, userID int64) (*domain.User, error)
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	// SetPassword stores a new password hash, rejects tokens issued before now and lifts any
+	// lockout.
+	SetPassword(ctx context.Context, userID int64, hash string, now int64) error
+	// MarkEmailVerified marks the user's current email address as verified.
+	MarkEmailVerified(ctx context.Context, userID int64, now int64) error
+	// SetPendingEmail records pendingEmail as the address the user wants to switch to,
+	// provided email is still their current one; otherwise it returns false.
+	SetPendingEmail(ctx context.Context, userID int64, email, pendingEmail string, now int64) (bool, error)
+	// SetTOTPSecret stores a newly enrolled TOTP secret, unless MFA was enabled in the
+	// meantime, in which case it returns false.
+	SetTOTPSecret(ctx context.Context, userID int64, secret string, now int64) (bool, error)
+	// EnableMFA turns on TOTP multi-factor authentication with the enrolled secret.
+	EnableMFA(ctx context.Context, userID int64, now int64) error
+	// RecordLoginFailure atomically increments the failed login counter and returns its new value.
+	RecordLoginFailure(ctx context.Context, userID int64) (int, error)
+	LockUntil(ctx context.Context, userID int64, until int64) error
+	// ResetLoginFailures clears the failed login counter and any lock.
+	ResetLoginFailures(ctx context.Context, userID int64) error
+	// UseTOTPStep records step as the last accepted TOTP time step, unless that step or a
+	// later one was used already, in which case it returns false.
+	UseTOTPStep(ctx context.Context, userID int64, step int64) (bool, error)
+	// ReplacePasswordHash swaps the stored password hash for newHash if it is still oldHash;
+	// it does nothing when the password changed in the meantime.
+	ReplacePasswordHash(ctx context.Context, userID int64, oldHash, newHash string, updatedAt int64) error
+	// ChangeEmail atomically replaces the user's email with newEmail, which must still be
+	// the pending email, marking it verified. It returns false when another account holds
+	// newEmail or the change is no longer pending.
+	ChangeEmail(ctx context.Context, userID int64, newEmail string, updatedAt int64) (bool, error)
 }
@@ -3,12 +3,42 @@ package port
 import (
 	"context"
 
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
 )
 
 type UserUseCase interface {
 	Register(ctx context.Context, in dto.RegisterInput) (*dto.RegisterOutput, error)
 	Login(ctx context.Context, in dto.LoginInput) (*dto.LoginOutput, error)
+	LoginMFA(ctx context.Context, in dto.LoginMFAInput) (*dto.LoginOutput, error)
+	Refresh(ctx context.Context, in dto.RefreshInput) (*dto.LoginOutput, error)
+	Logout(ctx context.Context, in dto.LogoutInput) error
+	RequestMagicLink(ctx context.Context, in dto.MagicLinkInput) error
+	ConsumeMagicLink(ctx context.Context, in dto.ConsumeMagicLinkInput) (*dto.LoginOutput, error)
+	ForgotPassword(ctx context.Context, in dto.ForgotPasswordInput) error
+	ResetPassword(ctx context.Context, in dto.ResetPasswordInput) error
+	ChangePassword(ctx context.Context, in dto.ChangePasswordInput) error
+	VerifyEmail(ctx context.Context, in dto.VerifyEmailInput) error
+	RequestEmailChange(ctx context.Context, in dto.ChangeEmailInput) error
+	ConfirmEmailChange(ctx context.Context, in dto.ConfirmEmailChangeInput) error
+	ResendVerification(ctx context.Context, in dto.ResendVerificationInput) error
+	EnrollTOTP(ctx context.Context, userID int64) (*dto.EnrollTOTPOutput, error)
+	ConfirmTOTP(ctx context.Context, in dto.ConfirmTOTPInput) error
+	BeginPasskeyRegistration(ctx context.Context, userID int64) (*dto.BeginPasskeyRegistrationOutput, error)
+	FinishPasskeyRegistration(ctx context.Context, in dto.FinishPasskeyRegistrationInput) (*dto.PasskeyOutput, error)
+	BeginPasskeyLogin(ctx context.Context) (*dto.BeginPasskeyLoginOutput, error)
+	FinishPasskeyLogin(ctx context.Context, in dto.FinishPasskeyLoginInput) (*dto.LoginOutput, error)
+	BeginFederatedLogin(ctx context.Context, in dto.BeginFederatedLoginInput) (*dto.BeginFederatedLoginOutput, error)
+	FinishFederatedLogin(ctx context.Context, in dto.FinishFederatedLoginInput) (*dto.LoginOutput, error)
+	ListSessions(ctx context.Context, in dto.ListSessionsInput) (*dto.ListSessionsOutput, error)
+	RevokeSession(ctx context.Context, in dto.RevokeSessionInput) error
+	CreatePersonalAccessToken(ctx context.Context, in dto.CreatePersonalAccessTokenInput) (*dto.CreatePersonalAccessTokenOutput, error)
+	ListPersonalAccessTokens(ctx context.Context, userID int64) (*dto.ListPersonalAccessTokensOutput, error)
+	RevokePersonalAccessToken(ctx context.Context, in dto.RevokePersonalAccessTokenInput) error
+	Impersonate(ctx context.Context, in dto.ImpersonateInput) (*dto.ImpersonateOutput, error)
 	GetMe(ctx context.Context, userID int64) (*dto.GetMeOutput, error)
+	// Authenticate verifies an access token or personal access token and rejects those
+	// issued before the user's last password change.
+	Authenticate(ctx context.Context, token string) (*domain.Principal, error)
 	GetUserByID(ctx context.Context, userID int64) (*dto.GetUserByIDOutput, error)
 }
@@ -0,0 +1,18 @@
+package port
+
+import (
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+)
+
+// WebAuthn checks the browser responses of the passkey ceremonies: client data, relying
+// party, flags and signatures. Both methods return the challenge the response was made for;
+// matching it against an issued challenge is left to the caller.
+type WebAuthn interface {
+	// VerifyRegistration parses an attestation and returns the new credential, without
+	// UserID, Name or timestamps.
+	VerifyRegistration(in dto.FinishPasskeyRegistrationInput) (challenge string, cred *domain.PasskeyCredential, err error)
+	// VerifyAssertion checks an assertion against a registered credential and returns the
+	// signature counter reported by the authenticator.
+	VerifyAssertion(cred *domain.PasskeyCredential, in dto.FinishPasskeyLoginInput) (challenge string, signCount uint32, err error)
+}
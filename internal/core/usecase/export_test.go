@@ -0,0 +1,9 @@
+package usecase
+
+import "time"
+
+// CurrentTOTPCode exposes the code an authenticator app would show right now, for the
+// black-box tests of the MFA flows.
+func CurrentTOTPCode(secret []byte) string {
+	return totpCode(secret, time.Now().Unix()/totpPeriod)
+}
@@ -0,0 +1,334 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+)
+
+var (
+	ErrOIDCDisabled       = errors.New("OpenID Connect is not enabled")
+	ErrInvalidRedirectURI = errors.New("invalid redirect uri")
+	ErrInvalidGrant       = errors.New("invalid grant")
+	ErrInsufficientScope  = errors.New("insufficient scope")
+)
+
+const (
+	grantTypeAuthorizationCode = "authorization_code"
+
+	scopeOpenID  = "openid"
+	scopeEmail   = "email"
+	scopeProfile = "profile"
+)
+
+// oidcScopes are the scopes relying parties may be granted, in the order they are reported.
+var oidcScopes = []string{scopeOpenID, scopeEmail, scopeProfile}
+
+// WithOpenIDConnect makes the service an OpenID Connect provider for the registered clients
+// that have redirect URIs: it enables the authorization endpoint, the authorization code
+// grant with PKCE, and userinfo backed by users.GetMe. issuer is the API's base URL and must
+// match the iss claim of issued tokens. loginURL is the frontend page that signs the user in
+// and asks for their consent; the provider stays disabled without it. Codes expire after
+// codeTTL; the access and ID tokens after tokenTTL. It requires WithClientCredentials, which
+// provides the client registry.
+func WithOpenIDConnect(codes port.AuthorizationCodeRepository, users port.UserUseCase, issuer, loginURL string, codeTTL, tokenTTL time.Duration) OAuthOption {
+	return func(o *oauthUseCase) {
+		o.codes = codes
+		o.users = users
+		o.issuer = strings.TrimRight(issuer, "/")
+		o.loginURL = loginURL
+		o.codeTTL = codeTTL
+		o.userTokenTTL = tokenTTL
+	}
+}
+
+// Authorize handles an OpenID Connect authentication request. A request without a signed-in
+// user is forwarded to the login page, which signs the user in, asks for their consent and
+// sends the request back with the user's answer; only then is a code issued. Once the client
+// and redirect URI check out, every other outcome, errors included, is a redirect back to the
+// client (RFC 6749 section 4.1.2.1); only an unknown client or redirect URI is returned as an
+// error, since there is nowhere safe to send the user then.
+func (o *oauthUseCase) Authorize(ctx context.Context, in dto.AuthorizeInput) (*dto.AuthorizeOutput, error) {
+	if !o.oidcEnabled() {
+		return nil, ErrOIDCDisabled
+	}
+	if in.ClientID == "" {
+		return nil, ErrInvalidClient
+	}
+	client, err := o.clients.GetByID(ctx, in.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, ErrInvalidClient
+	}
+	if !client.AllowsRedirectURI(in.RedirectURI) {
+		return nil, ErrInvalidRedirectURI
+	}
+
+	scopes := grantedScopes(in.Scope)
+	switch {
+	case in.ResponseType != "code":
+		return authorizeError(in, "unsupported_response_type", "only the authorization code flow is supported")
+	case !slices.Contains(scopes, scopeOpenID):
+		return authorizeError(in, "invalid_scope", "the openid scope is required")
+	case in.CodeChallenge == "" || in.CodeChallengeMethod != "S256":
+		return authorizeError(in, "invalid_request", "PKCE with the S256 method is required")
+	case in.UserID <= 0 && slices.Contains(strings.Fields(in.Prompt), "none"):
+		// the sign-in happens on the login page, so it can never be silent
+		return authorizeError(in, "login_required", "the user is not signed in")
+	case in.UserID <= 0:
+		return o.loginRedirect(in)
+	case !in.Consent:
+		return authorizeError(in, "access_denied", "the user denied the request")
+	}
+
+	code, hash, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := o.codes.Create(ctx, &domain.AuthorizationCode{
+		CodeHash:      hash,
+		ClientID:      client.ClientID,
+		UserID:        in.UserID,
+		RedirectURI:   in.RedirectURI,
+		Scopes:        scopes,
+		Nonce:         in.Nonce,
+		CodeChallenge: in.CodeChallenge,
+		AuthTime:      in.AuthTime,
+		CreatedAt:     now.Unix(),
+		ExpiresAt:     now.Add(o.codeTTL).Unix(),
+	}); err != nil {
+		return nil, err
+	}
+	return authorizeRedirect(in, url.Values{"code": {code}})
+}
+
+// exchangeCode implements the authorization code grant: the code is redeemed once, by the
+// client it was issued to, with the redirect URI and PKCE verifier of the original request.
+func (o *oauthUseCase) exchangeCode(ctx context.Context, in dto.TokenInput) (*dto.TokenOutput, error) {
+	if in.Code == "" || in.RedirectURI == "" || in.CodeVerifier == "" {
+		return nil, ErrInvalidInput
+	}
+	client, err := o.authenticateRelyingParty(ctx, in.ClientID, in.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	code, err := o.codes.Consume(ctx, hashOpaqueToken(in.Code), time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	if code == nil || code.ClientID != client.ClientID || code.RedirectURI != in.RedirectURI ||
+		!constantTimeEqual(pkceChallenge(in.CodeVerifier), code.CodeChallenge) {
+		return nil, ErrInvalidGrant
+	}
+	user, err := o.repo.GetByID(ctx, code.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.AcceptsTokenIssuedAt(code.AuthTime) {
+		return nil, ErrInvalidGrant
+	}
+
+	expiresAt := time.Now().Add(o.userTokenTTL).Unix()
+	principal := domain.Principal{
+		UserID:    user.UserID,
+		ClientID:  client.ClientID,
+		Scopes:    code.Scopes,
+		ExpiresAt: expiresAt,
+	}
+	idToken := domain.IDToken{
+		UserID:    user.UserID,
+		Audience:  client.ClientID,
+		Nonce:     code.Nonce,
+		AuthTime:  code.AuthTime,
+		ExpiresAt: expiresAt,
+	}
+	if slices.Contains(code.Scopes, scopeEmail) {
+		principal.Email = user.Email
+		idToken.Email = user.Email
+		idToken.EmailVerified = user.EmailVerified
+	}
+	if slices.Contains(code.Scopes, scopeProfile) {
+		idToken.Name = user.Name
+	}
+	access, err := o.jwtSigner.Sign(principal)
+	if err != nil {
+		return nil, err
+	}
+	id, err := o.jwtSigner.SignIDToken(idToken)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TokenOutput{
+		AccessToken: access,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(o.userTokenTTL.Seconds()),
+		Scope:       strings.Join(code.Scopes, " "),
+		IDToken:     id,
+	}, nil
+}
+
+// authenticateRelyingParty authenticates the client of the authorization code grant.
+// Confidential clients must present their secret; public clients only identify themselves,
+// their PKCE verifier being the proof that they started the flow.
+func (o *oauthUseCase) authenticateRelyingParty(ctx context.Context, clientID, clientSecret string) (*domain.OAuthClient, error) {
+	if clientSecret != "" {
+		return o.authenticateClient(ctx, clientID, clientSecret)
+	}
+	if clientID == "" {
+		return nil, ErrInvalidClient
+	}
+	client, err := o.clients.GetByID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil || !client.IsPublic() {
+		return nil, ErrInvalidClient
+	}
+	return client, nil
+}
+
+// UserInfo returns the claims about the token's user. Tokens issued to relying parties need
+// the openid scope and only see the claims of the scopes they were granted; tokens of our
+// own apps see them all.
+func (o *oauthUseCase) UserInfo(ctx context.Context, p domain.Principal) (*dto.UserInfoOutput, error) {
+	if o.users == nil {
+		return nil, ErrOIDCDisabled
+	}
+	if p.IsClient() || p.IsDelegated() && !p.HasScope(scopeOpenID) {
+		return nil, ErrInsufficientScope
+	}
+	me, err := o.users.GetMe(ctx, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	out := &dto.UserInfoOutput{Sub: strconv.FormatInt(me.UserID, 10)}
+	if !p.IsDelegated() || p.HasScope(scopeEmail) {
+		out.Email = me.Email
+		out.EmailVerified = me.EmailVerified
+	}
+	if !p.IsDelegated() || p.HasScope(scopeProfile) {
+		out.Name = me.Name
+	}
+	return out, nil
+}
+
+// Discovery returns the OpenID Connect discovery document. ID tokens are signed with the
+// access token keys, so the advertised algorithms are those of the published JWKS. The
+// introspection endpoint is only advertised when an introspection client is configured.
+func (o *oauthUseCase) Discovery(_ context.Context) (*dto.OpenIDConfigurationOutput, error) {
+	if !o.oidcEnabled() {
+		return nil, ErrOIDCDisabled
+	}
+	var algs []string
+	for _, k := range o.jwtSigner.JWKS().Keys {
+		if k.Alg != "" && !slices.Contains(algs, k.Alg) {
+			algs = append(algs, k.Alg)
+		}
+	}
+	var introspection string
+	if o.introspectionClientID != "" && o.introspectionClientSecret != "" {
+		introspection = o.issuer + "/oauth/introspect"
+	}
+	return &dto.OpenIDConfigurationOutput{
+		Issuer:                            o.issuer,
+		AuthorizationEndpoint:             o.issuer + "/oauth/authorize",
+		TokenEndpoint:                     o.issuer + "/oauth/token",
+		UserInfoEndpoint:                  o.issuer + "/oauth/userinfo",
+		JWKSURI:                           o.issuer + "/.well-known/jwks.json",
+		IntrospectionEndpoint:             introspection,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{grantTypeAuthorizationCode, grantTypeClientCredentials},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  algs,
+		ScopesSupported:                   oidcScopes,
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "email", "email_verified", "name"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+	}, nil
+}
+
+// grantedScopes keeps the OpenID Connect scopes of a space-delimited request; others are
+// ignored, as the specification asks of unknown scopes.
+func grantedScopes(requested string) []string {
+	fields := strings.Fields(requested)
+	var scopes []string
+	for _, s := range oidcScopes {
+		if slices.Contains(fields, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// oidcEnabled reports whether the service acts as an OpenID Connect provider. Without a login
+// page browsers could not sign in, so the provider is neither served nor advertised then.
+func (o *oauthUseCase) oidcEnabled() bool {
+	return o.codes != nil && o.clients != nil && o.loginURL != ""
+}
+
+// loginRedirect sends the user agent to the login page with the parameters of the request,
+// which the page posts back once the user has signed in and answered the consent prompt.
+func (o *oauthUseCase) loginRedirect(in dto.AuthorizeInput) (*dto.AuthorizeOutput, error) {
+	u, err := url.Parse(o.loginURL)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	for k, v := range map[string]string{
+		"response_type":         in.ResponseType,
+		"client_id":             in.ClientID,
+		"redirect_uri":          in.RedirectURI,
+		"scope":                 in.Scope,
+		"state":                 in.State,
+		"nonce":                 in.Nonce,
+		"code_challenge":        in.CodeChallenge,
+		"code_challenge_method": in.CodeChallengeMethod,
+	} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	u.RawQuery = q.Encode()
+	return &dto.AuthorizeOutput{RedirectURI: u.String()}, nil
+}
+
+func authorizeError(in dto.AuthorizeInput, code, description string) (*dto.AuthorizeOutput, error) {
+	return authorizeRedirect(in, url.Values{"error": {code}, "error_description": {description}})
+}
+
+// authorizeRedirect adds params and the request's state to the redirect URI, keeping any
+// query the client registered it with.
+func authorizeRedirect(in dto.AuthorizeInput, params url.Values) (*dto.AuthorizeOutput, error) {
+	u, err := url.Parse(in.RedirectURI)
+	if err != nil {
+		return nil, ErrInvalidRedirectURI
+	}
+	q := u.Query()
+	for k, v := range params {
+		q[k] = v
+	}
+	if in.State != "" {
+		q.Set("state", in.State)
+	}
+	u.RawQuery = q.Encode()
+	return &dto.AuthorizeOutput{RedirectURI: u.String()}, nil
+}
+
+// pkceChallenge derives the S256 code challenge of a verifier (RFC 7636 section 4.2).
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
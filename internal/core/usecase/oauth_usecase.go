@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+)
+
+var (
+	ErrInvalidClient        = errors.New("invalid client")
+	ErrUnsupportedGrantType = errors.New("unsupported grant type")
+	ErrInvalidScope         = errors.New("invalid scope")
+)
+
+const grantTypeClientCredentials = "client_credentials"
+
+type oauthUseCase struct {
+	repo      port.UserRepository
+	jwtSigner port.JWTSigner
+
+	introspectionClientID     string
+	introspectionClientSecret string
+
+	clients        port.OAuthClientRepository // nil disables the client credentials grant
+	clientTokenTTL time.Duration
+
+	codes        port.AuthorizationCodeRepository // nil disables OpenID Connect
+	users        port.UserUseCase
+	issuer       string
+	loginURL     string // empty disables OpenID Connect
+	codeTTL      time.Duration
+	userTokenTTL time.Duration
+}
+
+// OAuthOption configures optional collaborators and settings of the OAuth use case.
+type OAuthOption func(*oauthUseCase)
+
+// WithClientCredentials enables the client credentials grant for the registered clients.
+func WithClientCredentials(clients port.OAuthClientRepository, ttl time.Duration) OAuthOption {
+	return func(o *oauthUseCase) {
+		o.clients = clients
+		o.clientTokenTTL = ttl
+	}
+}
+
+// NewOAuthUseCase builds the OAuth endpoints. Token introspection is only granted to the
+// given service credential; leaving it empty disables introspection.
+func NewOAuthUseCase(repo port.UserRepository, jwtSigner port.JWTSigner, introspectionClientID, introspectionClientSecret string, opts ...OAuthOption) port.OAuthUseCase {
+	o := &oauthUseCase{
+		repo:                      repo,
+		jwtSigner:                 jwtSigner,
+		introspectionClientID:     introspectionClientID,
+		introspectionClientSecret: introspectionClientSecret,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Token implements the client credentials grant (RFC 6749 section 4.4). The client gets
+// the scopes it asked for, or all of its allowed scopes when it asked for none. With
+// OpenID Connect enabled it also redeems authorization codes.
+func (o *oauthUseCase) Token(ctx context.Context, in dto.TokenInput) (*dto.TokenOutput, error) {
+	if in.GrantType == "" {
+		return nil, ErrInvalidInput
+	}
+	if in.GrantType == grantTypeAuthorizationCode && o.oidcEnabled() {
+		return o.exchangeCode(ctx, in)
+	}
+	if in.GrantType != grantTypeClientCredentials || o.clients == nil {
+		return nil, ErrUnsupportedGrantType
+	}
+	client, err := o.authenticateClient(ctx, in.ClientID, in.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	scopes := strings.Fields(in.Scope)
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+	for _, s := range scopes {
+		if !client.AllowsScope(s) {
+			return nil, ErrInvalidScope
+		}
+	}
+
+	expiresAt := time.Now().Add(o.clientTokenTTL)
+	token, err := o.jwtSigner.Sign(domain.Principal{
+		SubjectType: domain.SubjectTypeClient,
+		ClientID:    client.ClientID,
+		Scopes:      scopes,
+		ExpiresAt:   expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TokenOutput{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(o.clientTokenTTL.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
+
+// authenticateClient checks the secret against the stored hash. Unknown clients still go
+// through a comparison so they cannot be told apart from wrong secrets by timing.
+func (o *oauthUseCase) authenticateClient(ctx context.Context, clientID, clientSecret string) (*domain.OAuthClient, error) {
+	if clientID == "" || clientSecret == "" {
+		return nil, ErrInvalidClient
+	}
+	client, err := o.clients.GetByID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		constantTimeEqual(hashOpaqueToken(clientSecret), "")
+		return nil, ErrInvalidClient
+	}
+	if !constantTimeEqual(hashOpaqueToken(clientSecret), client.SecretHash) {
+		return nil, ErrInvalidClient
+	}
+	return client, nil
+}
+
+// Introspect reports whether a token is currently usable (RFC 7662). Tokens that fail
+// verification, were revoked or belong to deleted users or clients are reported as
+// inactive rather than as errors, so callers never learn why a token was rejected.
+func (o *oauthUseCase) Introspect(ctx context.Context, in dto.IntrospectInput) (*dto.IntrospectOutput, error) {
+	if !o.isIntrospectionClient(in.ClientID, in.ClientSecret) {
+		return nil, ErrInvalidClient
+	}
+	if in.Token == "" {
+		return nil, ErrInvalidInput
+	}
+	p, err := o.jwtSigner.VerifyPrincipal(ctx, in.Token)
+	if err != nil {
+		return &dto.IntrospectOutput{Active: false}, nil
+	}
+	out := &dto.IntrospectOutput{
+		Active:    true,
+		Scope:     strings.Join(p.Scopes, " "),
+		TokenType: "Bearer",
+		Jti:       p.TokenID,
+		Exp:       p.ExpiresAt,
+		Iat:       p.IssuedAt,
+	}
+	if p.IsClient() {
+		if o.clients == nil {
+			return &dto.IntrospectOutput{Active: false}, nil
+		}
+		client, err := o.clients.GetByID(ctx, p.ClientID)
+		if err != nil {
+			return nil, err
+		}
+		if client == nil {
+			return &dto.IntrospectOutput{Active: false}, nil
+		}
+		out.Sub = client.ClientID
+		out.ClientID = client.ClientID
+		return out, nil
+	}
+	user, err := o.repo.GetByID(ctx, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.AcceptsTokenIssuedAt(p.IssuedAt) {
+		// deleted user, or a token from before the last password change
+		return &dto.IntrospectOutput{Active: false}, nil
+	}
+	out.Sub = strconv.FormatInt(p.UserID, 10)
+	out.Username = user.Email
+	if p.IsImpersonated() {
+		out.Act = strconv.FormatInt(p.ActorUserID, 10)
+	}
+	return out, nil
+}
+
+func (o *oauthUseCase) isIntrospectionClient(clientID, clientSecret string) bool {
+	if o.introspectionClientID == "" || o.introspectionClientSecret == "" {
+		return false
+	}
+	return constantTimeEqual(clientID, o.introspectionClientID) &&
+		constantTimeEqual(clientSecret, o.introspectionClientSecret)
+}
+
+// constantTimeEqual compares hashes so neither content nor length leaks through timing.
+func constantTimeEqual(a, b string) bool {
+	ha, hb := sha256.Sum256([]byte(a)), sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(ha[:], hb[:]) == 1
+}
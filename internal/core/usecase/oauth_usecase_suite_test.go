@@ -0,0 +1,53 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	mockport "github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port/mocks"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/usecase"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testIntrospectionClientID     = "video-api"
+	testIntrospectionClientSecret = "s3cr3t"
+	testClientSecret              = "worker-secret"
+	// hex SHA-256 of testClientSecret
+	testClientSecretHash = "6fb46f7a92742970166379ed5195e79c4493a7cc5664280c039cfd4095ba5faf"
+)
+
+type OAuthUsecaseSuiteTest struct {
+	suite.Suite
+	mockRepo      *mockport.MockUserRepository
+	mockJWTSigner *mockport.MockJWTSigner
+	mockClients   *mockport.MockOAuthClientRepository
+	mockCodes     *mockport.MockAuthorizationCodeRepository
+	mockUsers     *mockport.MockUserUseCase
+	useCase       port.OAuthUseCase
+	ctx           context.Context
+	ctrl          *gomock.Controller
+}
+
+func (s *OAuthUsecaseSuiteTest) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockRepo = mockport.NewMockUserRepository(s.ctrl)
+	s.mockJWTSigner = mockport.NewMockJWTSigner(s.ctrl)
+	s.mockClients = mockport.NewMockOAuthClientRepository(s.ctrl)
+	s.mockCodes = mockport.NewMockAuthorizationCodeRepository(s.ctrl)
+	s.mockUsers = mockport.NewMockUserUseCase(s.ctrl)
+	s.useCase = usecase.NewOAuthUseCase(s.mockRepo, s.mockJWTSigner, testIntrospectionClientID, testIntrospectionClientSecret,
+		usecase.WithClientCredentials(s.mockClients, time.Hour))
+	s.ctx = context.Background()
+}
+
+func (s *OAuthUsecaseSuiteTest) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func TestOAuthUsecaseSuiteTest(t *testing.T) {
+	suite.Run(t, new(OAuthUsecaseSuiteTest))
+}
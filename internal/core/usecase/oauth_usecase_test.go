@@ -0,0 +1,913 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/usecase"
+)
+
+func (s *OAuthUsecaseSuiteTest) TestOAuthUseCase_Token() {
+	worker := &domain.OAuthClient{
+		ClientID:   "video-worker",
+		SecretHash: testClientSecretHash,
+		Name:       "Video processing worker",
+		Scopes:     []string{"users:read", "videos:write"},
+	}
+
+	tests := []struct {
+		name        string
+		input       dto.TokenInput
+		setupMocks  func()
+		checkResult func(*testing.T, *dto.TokenOutput, error)
+	}{
+		{
+			name:  "should issue a client token with every allowed scope",
+			input: dto.TokenInput{ClientID: "video-worker", ClientSecret: testClientSecret, GrantType: "client_credentials"},
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "video-worker").Return(worker, nil)
+				s.mockJWTSigner.EXPECT().
+					Sign(gomock.Any()).
+					DoAndReturn(func(p domain.Principal) (string, error) {
+						assert.True(s.T(), p.IsClient())
+						assert.Equal(s.T(), "video-worker", p.ClientID)
+						assert.Zero(s.T(), p.UserID)
+						assert.Equal(s.T(), []string{"users:read", "videos:write"}, p.Scopes)
+						assert.InDelta(s.T(), time.Now().Add(time.Hour).Unix(), p.ExpiresAt, 5)
+						return "client.jwt", nil
+					})
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &dto.TokenOutput{
+					AccessToken: "client.jwt",
+					TokenType:   "Bearer",
+					ExpiresIn:   3600,
+					Scope:       "users:read videos:write",
+				}, output)
+			},
+		},
+		{
+			name:  "should narrow the token to the requested scopes",
+			input: dto.TokenInput{ClientID: "video-worker", ClientSecret: testClientSecret, GrantType: "client_credentials", Scope: "users:read"},
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "video-worker").Return(worker, nil)
+				s.mockJWTSigner.EXPECT().
+					Sign(gomock.Any()).
+					DoAndReturn(func(p domain.Principal) (string, error) {
+						assert.Equal(s.T(), []string{"users:read"}, p.Scopes)
+						return "client.jwt", nil
+					})
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "users:read", output.Scope)
+			},
+		},
+		{
+			name:  "should reject a scope the client is not allowed",
+			input: dto.TokenInput{ClientID: "video-worker", ClientSecret: testClientSecret, GrantType: "client_credentials", Scope: "users:read users:admin"},
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "video-worker").Return(worker, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidScope, err)
+				assert.Nil(t, output)
+			},
+		},
+		{
+			name:  "should reject a wrong client secret",
+			input: dto.TokenInput{ClientID: "video-worker", ClientSecret: "wrong", GrantType: "client_credentials"},
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "video-worker").Return(worker, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidClient, err)
+				assert.Nil(t, output)
+			},
+		},
+		{
+			name:  "should reject an unknown client",
+			input: dto.TokenInput{ClientID: "nobody", ClientSecret: testClientSecret, GrantType: "client_credentials"},
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "nobody").Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidClient, err)
+				assert.Nil(t, output)
+			},
+		},
+		{
+			name:  "should return error when client lookup fails",
+			input: dto.TokenInput{ClientID: "video-worker", ClientSecret: testClientSecret, GrantType: "client_credentials"},
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "video-worker").Return(nil, assert.AnError)
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.ErrorIs(t, err, assert.AnError)
+				assert.Nil(t, output)
+			},
+		},
+		{
+			name:       "should reject other grant types",
+			input:      dto.TokenInput{ClientID: "video-worker", ClientSecret: testClientSecret, GrantType: "password"},
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.Equal(t, usecase.ErrUnsupportedGrantType, err)
+				assert.Nil(t, output)
+			},
+		},
+		{
+			name:       "should reject a missing grant type",
+			input:      dto.TokenInput{ClientID: "video-worker", ClientSecret: testClientSecret},
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+				assert.Nil(t, output)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			output, err := s.useCase.Token(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, output, err)
+		})
+	}
+}
+
+func (s *OAuthUsecaseSuiteTest) TestOAuthUseCase_Introspect() {
+	validInput := dto.IntrospectInput{
+		ClientID:     testIntrospectionClientID,
+		ClientSecret: testIntrospectionClientSecret,
+		Token:        "access.token",
+	}
+
+	tests := []struct {
+		name        string
+		input       dto.IntrospectInput
+		setupMocks  func()
+		checkResult func(*testing.T, *dto.IntrospectOutput, error)
+	}{
+		{
+			name:  "should describe an active token",
+			input: validInput,
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().
+					VerifyPrincipal(s.ctx, "access.token").
+					Return(&domain.Principal{UserID: 1, Scopes: []string{"videos:read", "videos:write"}, TokenID: "jti-1", IssuedAt: 100, ExpiresAt: 200}, nil)
+				s.mockRepo.EXPECT().
+					GetByID(s.ctx, int64(1)).
+					Return(&domain.User{UserID: 1, Email: "john@example.com"}, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.IntrospectOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &dto.IntrospectOutput{
+					Active:    true,
+					Scope:     "videos:read videos:write",
+					Username:  "john@example.com",
+					TokenType: "Bearer",
+					Sub:       "1",
+					Jti:       "jti-1",
+					Exp:       200,
+					Iat:       100,
+				}, output)
+			},
+		},
+		{
+			name:  "should name the acting admin of an impersonation token",
+			input: validInput,
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().
+					VerifyPrincipal(s.ctx, "access.token").
+					Return(&domain.Principal{UserID: 1, IssuedAt: 100, ActorUserID: 9, ActorEmail: "admin@example.com"}, nil)
+				s.mockRepo.EXPECT().
+					GetByID(s.ctx, int64(1)).
+					Return(&domain.User{UserID: 1, Email: "john@example.com"}, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.IntrospectOutput, err error) {
+				assert.NoError(t, err)
+				assert.True(t, output.Active)
+				assert.Equal(t, "1", output.Sub)
+				assert.Equal(t, "9", output.Act)
+			},
+		},
+		{
+			name:  "should describe an active client token",
+			input: validInput,
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().
+					VerifyPrincipal(s.ctx, "access.token").
+					Return(&domain.Principal{SubjectType: domain.SubjectTypeClient, ClientID: "video-worker", Scopes: []string{"users:read"}, TokenID: "jti-2"}, nil)
+				s.mockClients.EXPECT().
+					GetByID(s.ctx, "video-worker").
+					Return(&domain.OAuthClient{ClientID: "video-worker"}, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.IntrospectOutput, err error) {
+				assert.NoError(t, err)
+				assert.True(t, output.Active)
+				assert.Equal(t, "video-worker", output.Sub)
+				assert.Equal(t, "video-worker", output.ClientID)
+				assert.Empty(t, output.Username)
+				assert.Equal(t, "users:read", output.Scope)
+			},
+		},
+		{
+			name:  "should report token of deleted client as inactive",
+			input: validInput,
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().
+					VerifyPrincipal(s.ctx, "access.token").
+					Return(&domain.Principal{SubjectType: domain.SubjectTypeClient, ClientID: "video-worker"}, nil)
+				s.mockClients.EXPECT().
+					GetByID(s.ctx, "video-worker").
+					Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.IntrospectOutput, err error) {
+				assert.NoError(t, err)
+				assert.False(t, output.Active)
+			},
+		},
+		{
+			name:  "should report invalid or revoked token as inactive",
+			input: validInput,
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().
+					VerifyPrincipal(s.ctx, "access.token").
+					Return(nil, errors.New("token revoked"))
+			},
+			checkResult: func(t *testing.T, output *dto.IntrospectOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &dto.IntrospectOutput{Active: false}, output)
+			},
+		},
+		{
+			name:  "should report token of deleted user as inactive",
+			input: validInput,
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().
+					VerifyPrincipal(s.ctx, "access.token").
+					Return(&domain.Principal{UserID: 1}, nil)
+				s.mockRepo.EXPECT().
+					GetByID(s.ctx, int64(1)).
+					Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.IntrospectOutput, err error) {
+				assert.NoError(t, err)
+				assert.False(t, output.Active)
+			},
+		},
+		{
+			name:  "should report token issued before a password change as inactive",
+			input: validInput,
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().
+					VerifyPrincipal(s.ctx, "access.token").
+					Return(&domain.Principal{UserID: 1, IssuedAt: 100, ExpiresAt: 200}, nil)
+				s.mockRepo.EXPECT().
+					GetByID(s.ctx, int64(1)).
+					Return(&domain.User{UserID: 1, Email: "john@example.com", TokensValidAfter: 150}, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.IntrospectOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &dto.IntrospectOutput{Active: false}, output)
+			},
+		},
+		{
+			name:  "should return error when repository fails",
+			input: validInput,
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().
+					VerifyPrincipal(s.ctx, "access.token").
+					Return(&domain.Principal{UserID: 1}, nil)
+				s.mockRepo.EXPECT().
+					GetByID(s.ctx, int64(1)).
+					Return(nil, assert.AnError)
+			},
+			checkResult: func(t *testing.T, output *dto.IntrospectOutput, err error) {
+				assert.ErrorIs(t, err, assert.AnError)
+				assert.Nil(t, output)
+			},
+		},
+		{
+			name:       "should reject wrong client secret",
+			input:      dto.IntrospectInput{ClientID: testIntrospectionClientID, ClientSecret: "wrong", Token: "access.token"},
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, output *dto.IntrospectOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidClient, err)
+				assert.Nil(t, output)
+			},
+		},
+		{
+			name:       "should reject missing client credentials",
+			input:      dto.IntrospectInput{Token: "access.token"},
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, output *dto.IntrospectOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidClient, err)
+				assert.Nil(t, output)
+			},
+		},
+		{
+			name:       "should reject missing token",
+			input:      dto.IntrospectInput{ClientID: testIntrospectionClientID, ClientSecret: testIntrospectionClientSecret},
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, output *dto.IntrospectOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+				assert.Nil(t, output)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			output, err := s.useCase.Introspect(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, output, err)
+		})
+	}
+}
+
+func TestOAuthUseCase_Introspect_DisabledWithoutClient(t *testing.T) {
+	uc := usecase.NewOAuthUseCase(nil, nil, "", "")
+	_, err := uc.Introspect(t.Context(), dto.IntrospectInput{Token: "access.token"})
+	assert.Equal(t, usecase.ErrInvalidClient, err)
+}
+
+// PKCE example of RFC 7636 appendix B
+const (
+	testCodeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	testCodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	testLoginURL      = "https://login.example.com/authorize?theme=dark"
+)
+
+func (s *OAuthUsecaseSuiteTest) oidcUseCase() port.OAuthUseCase {
+	return usecase.NewOAuthUseCase(s.mockRepo, s.mockJWTSigner, testIntrospectionClientID, testIntrospectionClientSecret,
+		usecase.WithClientCredentials(s.mockClients, time.Hour),
+		usecase.WithOpenIDConnect(s.mockCodes, s.mockUsers, "https://auth.example.com/prod/", testLoginURL, time.Minute, 15*time.Minute))
+}
+
+func (s *OAuthUsecaseSuiteTest) TestOAuthUseCase_Authorize() {
+	partner := &domain.OAuthClient{ClientID: "partner-app", RedirectURIs: []string{"https://partner.example.com/callback?tenant=acme"}}
+	valid := dto.AuthorizeInput{
+		UserID:              7,
+		AuthTime:            1700000000,
+		Consent:             true,
+		ResponseType:        "code",
+		ClientID:            "partner-app",
+		RedirectURI:         "https://partner.example.com/callback?tenant=acme",
+		Scope:               "openid email offline_access",
+		State:               "af0ifjsldkj",
+		Nonce:               "n-0S6_WzA2Mj",
+		CodeChallenge:       testCodeChallenge,
+		CodeChallengeMethod: "S256",
+	}
+	with := func(change func(*dto.AuthorizeInput)) dto.AuthorizeInput {
+		in := valid
+		change(&in)
+		return in
+	}
+	redirectQuery := func(t *testing.T, out *dto.AuthorizeOutput) url.Values {
+		u, err := url.Parse(out.RedirectURI)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://partner.example.com/callback", u.Scheme+"://"+u.Host+u.Path)
+		return u.Query()
+	}
+
+	tests := []struct {
+		name        string
+		input       dto.AuthorizeInput
+		setupMocks  func()
+		checkResult func(*testing.T, *dto.AuthorizeOutput, error)
+	}{
+		{
+			name:  "should store a code bound to the request and redirect with it",
+			input: valid,
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(partner, nil)
+				s.mockCodes.EXPECT().
+					Create(s.ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, c *domain.AuthorizationCode) error {
+						assert.Equal(s.T(), "partner-app", c.ClientID)
+						assert.Equal(s.T(), int64(7), c.UserID)
+						assert.Equal(s.T(), valid.RedirectURI, c.RedirectURI)
+						assert.Equal(s.T(), []string{"openid", "email"}, c.Scopes)
+						assert.Equal(s.T(), "n-0S6_WzA2Mj", c.Nonce)
+						assert.Equal(s.T(), testCodeChallenge, c.CodeChallenge)
+						assert.Equal(s.T(), int64(1700000000), c.AuthTime)
+						assert.InDelta(s.T(), time.Now().Add(time.Minute).Unix(), c.ExpiresAt, 5)
+						return nil
+					})
+			},
+			checkResult: func(t *testing.T, out *dto.AuthorizeOutput, err error) {
+				assert.NoError(t, err)
+				q := redirectQuery(t, out)
+				assert.NotEmpty(t, q.Get("code"))
+				assert.Equal(t, "af0ifjsldkj", q.Get("state"))
+				assert.Equal(t, "acme", q.Get("tenant"))
+			},
+		},
+		{
+			name:  "should send a user who is not signed in to the login page with the request",
+			input: with(func(in *dto.AuthorizeInput) { in.UserID = 0; in.Consent = false }),
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(partner, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.AuthorizeOutput, err error) {
+				assert.NoError(t, err)
+				u, err := url.Parse(out.RedirectURI)
+				assert.NoError(t, err)
+				assert.Equal(t, "https://login.example.com/authorize", u.Scheme+"://"+u.Host+u.Path)
+				q := u.Query()
+				assert.Equal(t, "dark", q.Get("theme"))
+				assert.Equal(t, "partner-app", q.Get("client_id"))
+				assert.Equal(t, valid.RedirectURI, q.Get("redirect_uri"))
+				assert.Equal(t, "openid email offline_access", q.Get("scope"))
+				assert.Equal(t, "af0ifjsldkj", q.Get("state"))
+				assert.Equal(t, "n-0S6_WzA2Mj", q.Get("nonce"))
+				assert.Equal(t, testCodeChallenge, q.Get("code_challenge"))
+				assert.Equal(t, "S256", q.Get("code_challenge_method"))
+				assert.Empty(t, q.Get("code"))
+			},
+		},
+		{
+			name:  "should redirect with login_required when a silent sign-in is asked for",
+			input: with(func(in *dto.AuthorizeInput) { in.UserID = 0; in.Prompt = "none" }),
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(partner, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.AuthorizeOutput, err error) {
+				assert.NoError(t, err)
+				q := redirectQuery(t, out)
+				assert.Equal(t, "login_required", q.Get("error"))
+				assert.Equal(t, "af0ifjsldkj", q.Get("state"))
+				assert.Empty(t, q.Get("code"))
+			},
+		},
+		{
+			name:  "should redirect with access_denied when the user declines",
+			input: with(func(in *dto.AuthorizeInput) { in.Consent = false }),
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(partner, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.AuthorizeOutput, err error) {
+				assert.NoError(t, err)
+				q := redirectQuery(t, out)
+				assert.Equal(t, "access_denied", q.Get("error"))
+				assert.Equal(t, "af0ifjsldkj", q.Get("state"))
+				assert.Empty(t, q.Get("code"))
+			},
+		},
+		{
+			name:  "should require PKCE with S256",
+			input: with(func(in *dto.AuthorizeInput) { in.CodeChallengeMethod = "plain" }),
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(partner, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.AuthorizeOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "invalid_request", redirectQuery(t, out).Get("error"))
+			},
+		},
+		{
+			name:  "should require the openid scope",
+			input: with(func(in *dto.AuthorizeInput) { in.Scope = "email profile" }),
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(partner, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.AuthorizeOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "invalid_scope", redirectQuery(t, out).Get("error"))
+			},
+		},
+		{
+			name:  "should only support the code response type",
+			input: with(func(in *dto.AuthorizeInput) { in.ResponseType = "id_token token" }),
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(partner, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.AuthorizeOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "unsupported_response_type", redirectQuery(t, out).Get("error"))
+			},
+		},
+		{
+			name:  "should not redirect to an unregistered URI",
+			input: with(func(in *dto.AuthorizeInput) { in.RedirectURI = "https://evil.example.com/callback" }),
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(partner, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.AuthorizeOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidRedirectURI, err)
+				assert.Nil(t, out)
+			},
+		},
+		{
+			name:  "should reject an unknown client",
+			input: valid,
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.AuthorizeOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidClient, err)
+				assert.Nil(t, out)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			output, err := s.oidcUseCase().Authorize(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, output, err)
+		})
+	}
+
+	s.T().Run("should refuse when OpenID Connect is not enabled", func(t *testing.T) {
+		_, err := s.useCase.Authorize(s.ctx, valid)
+		assert.Equal(t, usecase.ErrOIDCDisabled, err)
+	})
+
+	s.T().Run("should refuse without a login page", func(t *testing.T) {
+		uc := usecase.NewOAuthUseCase(s.mockRepo, s.mockJWTSigner, testIntrospectionClientID, testIntrospectionClientSecret,
+			usecase.WithClientCredentials(s.mockClients, time.Hour),
+			usecase.WithOpenIDConnect(s.mockCodes, s.mockUsers, "https://auth.example.com/prod/", "", time.Minute, 15*time.Minute))
+
+		_, err := uc.Authorize(s.ctx, valid)
+		assert.Equal(t, usecase.ErrOIDCDisabled, err)
+	})
+}
+
+func (s *OAuthUsecaseSuiteTest) TestOAuthUseCase_Token_AuthorizationCode() {
+	const code = "auth-code"
+	sum := sha256.Sum256([]byte(code))
+	codeHash := hex.EncodeToString(sum[:])
+	const redirectURI = "https://partner.example.com/callback"
+
+	confidential := &domain.OAuthClient{ClientID: "partner-app", SecretHash: testClientSecretHash, RedirectURIs: []string{redirectURI}}
+	public := &domain.OAuthClient{ClientID: "partner-spa", RedirectURIs: []string{redirectURI}}
+	issued := func(clientID string, scopes ...string) *domain.AuthorizationCode {
+		return &domain.AuthorizationCode{
+			CodeHash:      codeHash,
+			ClientID:      clientID,
+			UserID:        7,
+			RedirectURI:   redirectURI,
+			Scopes:        scopes,
+			Nonce:         "n-0S6_WzA2Mj",
+			CodeChallenge: testCodeChallenge,
+			AuthTime:      1700000000,
+		}
+	}
+	user := &domain.User{UserID: 7, Name: "John Doe", Email: "john@example.com", EmailVerified: true, Roles: []string{"admin"}}
+	valid := dto.TokenInput{
+		GrantType:    "authorization_code",
+		ClientID:     "partner-app",
+		ClientSecret: testClientSecret,
+		Code:         code,
+		RedirectURI:  redirectURI,
+		CodeVerifier: testCodeVerifier,
+	}
+	with := func(change func(*dto.TokenInput)) dto.TokenInput {
+		in := valid
+		change(&in)
+		return in
+	}
+
+	tests := []struct {
+		name        string
+		input       dto.TokenInput
+		setupMocks  func()
+		checkResult func(*testing.T, *dto.TokenOutput, error)
+	}{
+		{
+			name:  "should issue an access token and an ID token built from the user",
+			input: valid,
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(confidential, nil)
+				s.mockCodes.EXPECT().Consume(s.ctx, codeHash, gomock.Any()).Return(issued("partner-app", "openid", "email", "profile"), nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(7)).Return(user, nil)
+				s.mockJWTSigner.EXPECT().
+					Sign(gomock.Any()).
+					DoAndReturn(func(p domain.Principal) (string, error) {
+						assert.Equal(s.T(), int64(7), p.UserID)
+						assert.True(s.T(), p.IsDelegated())
+						assert.Equal(s.T(), "partner-app", p.ClientID)
+						assert.Equal(s.T(), []string{"openid", "email", "profile"}, p.Scopes)
+						assert.Empty(s.T(), p.Roles)
+						assert.InDelta(s.T(), time.Now().Add(15*time.Minute).Unix(), p.ExpiresAt, 5)
+						return "access.jwt", nil
+					})
+				s.mockJWTSigner.EXPECT().
+					SignIDToken(gomock.Any()).
+					DoAndReturn(func(t domain.IDToken) (string, error) {
+						assert.Equal(s.T(), int64(7), t.UserID)
+						assert.Equal(s.T(), "partner-app", t.Audience)
+						assert.Equal(s.T(), "n-0S6_WzA2Mj", t.Nonce)
+						assert.Equal(s.T(), int64(1700000000), t.AuthTime)
+						assert.Equal(s.T(), "john@example.com", t.Email)
+						assert.True(s.T(), t.EmailVerified)
+						assert.Equal(s.T(), "John Doe", t.Name)
+						return "id.jwt", nil
+					})
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &dto.TokenOutput{
+					AccessToken: "access.jwt",
+					TokenType:   "Bearer",
+					ExpiresIn:   900,
+					Scope:       "openid email profile",
+					IDToken:     "id.jwt",
+				}, output)
+			},
+		},
+		{
+			name:  "should leave out the claims of scopes that were not granted",
+			input: valid,
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(confidential, nil)
+				s.mockCodes.EXPECT().Consume(s.ctx, codeHash, gomock.Any()).Return(issued("partner-app", "openid"), nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(7)).Return(user, nil)
+				s.mockJWTSigner.EXPECT().
+					Sign(gomock.Any()).
+					DoAndReturn(func(p domain.Principal) (string, error) {
+						assert.Empty(s.T(), p.Email)
+						return "access.jwt", nil
+					})
+				s.mockJWTSigner.EXPECT().
+					SignIDToken(gomock.Any()).
+					DoAndReturn(func(t domain.IDToken) (string, error) {
+						assert.Empty(s.T(), t.Email)
+						assert.Empty(s.T(), t.Name)
+						return "id.jwt", nil
+					})
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "openid", output.Scope)
+			},
+		},
+		{
+			name:  "should let a public client redeem its code without a secret",
+			input: with(func(in *dto.TokenInput) { in.ClientID = "partner-spa"; in.ClientSecret = "" }),
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-spa").Return(public, nil)
+				s.mockCodes.EXPECT().Consume(s.ctx, codeHash, gomock.Any()).Return(issued("partner-spa", "openid"), nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(7)).Return(user, nil)
+				s.mockJWTSigner.EXPECT().Sign(gomock.Any()).Return("access.jwt", nil)
+				s.mockJWTSigner.EXPECT().SignIDToken(gomock.Any()).Return("id.jwt", nil)
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "id.jwt", output.IDToken)
+			},
+		},
+		{
+			name:  "should require the secret of a confidential client",
+			input: with(func(in *dto.TokenInput) { in.ClientSecret = "" }),
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(confidential, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidClient, err)
+				assert.Nil(t, output)
+			},
+		},
+		{
+			name:  "should reject a wrong code verifier",
+			input: with(func(in *dto.TokenInput) { in.CodeVerifier = "not-the-verifier" }),
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(confidential, nil)
+				s.mockCodes.EXPECT().Consume(s.ctx, codeHash, gomock.Any()).Return(issued("partner-app", "openid"), nil)
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidGrant, err)
+				assert.Nil(t, output)
+			},
+		},
+		{
+			name:  "should reject a different redirect URI",
+			input: with(func(in *dto.TokenInput) { in.RedirectURI = "https://partner.example.com/other" }),
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(confidential, nil)
+				s.mockCodes.EXPECT().Consume(s.ctx, codeHash, gomock.Any()).Return(issued("partner-app", "openid"), nil)
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidGrant, err)
+			},
+		},
+		{
+			name:  "should reject a code issued to another client",
+			input: valid,
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(confidential, nil)
+				s.mockCodes.EXPECT().Consume(s.ctx, codeHash, gomock.Any()).Return(issued("partner-spa", "openid"), nil)
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidGrant, err)
+			},
+		},
+		{
+			name:  "should reject an unknown, used or expired code",
+			input: valid,
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(confidential, nil)
+				s.mockCodes.EXPECT().Consume(s.ctx, codeHash, gomock.Any()).Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidGrant, err)
+			},
+		},
+		{
+			name:  "should reject a code from a sign-in before the last password change",
+			input: valid,
+			setupMocks: func() {
+				s.mockClients.EXPECT().GetByID(s.ctx, "partner-app").Return(confidential, nil)
+				s.mockCodes.EXPECT().Consume(s.ctx, codeHash, gomock.Any()).Return(issued("partner-app", "openid"), nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(7)).Return(&domain.User{UserID: 7, TokensValidAfter: 1700000001}, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidGrant, err)
+			},
+		},
+		{
+			name:  "should require the code verifier",
+			input: with(func(in *dto.TokenInput) { in.CodeVerifier = "" }),
+			setupMocks: func() {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, output *dto.TokenOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			output, err := s.oidcUseCase().Token(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, output, err)
+		})
+	}
+
+	s.T().Run("should not support the grant when OpenID Connect is not enabled", func(t *testing.T) {
+		_, err := s.useCase.Token(s.ctx, valid)
+		assert.Equal(t, usecase.ErrUnsupportedGrantType, err)
+	})
+}
+
+func (s *OAuthUsecaseSuiteTest) TestOAuthUseCase_UserInfo() {
+	me := &dto.GetMeOutput{UserID: 7, Name: "John Doe", Email: "john@example.com", EmailVerified: true}
+
+	tests := []struct {
+		name        string
+		principal   domain.Principal
+		setupMocks  func()
+		checkResult func(*testing.T, *dto.UserInfoOutput, error)
+	}{
+		{
+			name:      "should return every claim to our own apps",
+			principal: domain.Principal{UserID: 7},
+			setupMocks: func() {
+				s.mockUsers.EXPECT().GetMe(s.ctx, int64(7)).Return(me, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.UserInfoOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &dto.UserInfoOutput{Sub: "7", Name: "John Doe", Email: "john@example.com", EmailVerified: true}, out)
+			},
+		},
+		{
+			name:      "should return the claims of the granted scopes to a relying party",
+			principal: domain.Principal{UserID: 7, ClientID: "partner-app", Scopes: []string{"openid", "email"}},
+			setupMocks: func() {
+				s.mockUsers.EXPECT().GetMe(s.ctx, int64(7)).Return(me, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.UserInfoOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &dto.UserInfoOutput{Sub: "7", Email: "john@example.com", EmailVerified: true}, out)
+			},
+		},
+		{
+			name:      "should require the openid scope from a relying party",
+			principal: domain.Principal{UserID: 7, ClientID: "partner-app", Scopes: []string{"email"}},
+			setupMocks: func() {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, out *dto.UserInfoOutput, err error) {
+				assert.Equal(t, usecase.ErrInsufficientScope, err)
+				assert.Nil(t, out)
+			},
+		},
+		{
+			name:      "should refuse client tokens",
+			principal: domain.Principal{SubjectType: domain.SubjectTypeClient, ClientID: "video-worker"},
+			setupMocks: func() {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, out *dto.UserInfoOutput, err error) {
+				assert.Equal(t, usecase.ErrInsufficientScope, err)
+			},
+		},
+		{
+			name:      "should return error when the user is gone",
+			principal: domain.Principal{UserID: 7},
+			setupMocks: func() {
+				s.mockUsers.EXPECT().GetMe(s.ctx, int64(7)).Return(nil, usecase.ErrUserNotFound)
+			},
+			checkResult: func(t *testing.T, out *dto.UserInfoOutput, err error) {
+				assert.Equal(t, usecase.ErrUserNotFound, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			output, err := s.oidcUseCase().UserInfo(s.ctx, tt.principal)
+
+			// Assert
+			tt.checkResult(t, output, err)
+		})
+	}
+}
+
+func (s *OAuthUsecaseSuiteTest) TestOAuthUseCase_Discovery() {
+	s.T().Run("should advertise the endpoints under the issuer and the JWKS algorithms", func(t *testing.T) {
+		s.mockJWTSigner.EXPECT().JWKS().Return(dto.JWKSOutput{Keys: []dto.JWK{
+			{Kid: "2024-06", Alg: "RS256"},
+			{Kid: "2024-01", Alg: "RS256"},
+			{Kid: "ec-1", Alg: "ES256"},
+		}})
+
+		out, err := s.oidcUseCase().Discovery(s.ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://auth.example.com/prod", out.Issuer)
+		assert.Equal(t, "https://auth.example.com/prod/oauth/authorize", out.AuthorizationEndpoint)
+		assert.Equal(t, "https://auth.example.com/prod/oauth/token", out.TokenEndpoint)
+		assert.Equal(t, "https://auth.example.com/prod/oauth/userinfo", out.UserInfoEndpoint)
+		assert.Equal(t, "https://auth.example.com/prod/.well-known/jwks.json", out.JWKSURI)
+		assert.Equal(t, "https://auth.example.com/prod/oauth/introspect", out.IntrospectionEndpoint)
+		assert.Equal(t, []string{"RS256", "ES256"}, out.IDTokenSigningAlgValuesSupported)
+		assert.Equal(t, []string{"S256"}, out.CodeChallengeMethodsSupported)
+		assert.Equal(t, []string{"openid", "email", "profile"}, out.ScopesSupported)
+	})
+
+	s.T().Run("should not advertise introspection without an introspection client", func(t *testing.T) {
+		s.mockJWTSigner.EXPECT().JWKS().Return(dto.JWKSOutput{Keys: []dto.JWK{{Kid: "2024-06", Alg: "RS256"}}})
+		uc := usecase.NewOAuthUseCase(s.mockRepo, s.mockJWTSigner, "", "",
+			usecase.WithClientCredentials(s.mockClients, time.Hour),
+			usecase.WithOpenIDConnect(s.mockCodes, s.mockUsers, "https://auth.example.com/prod/", testLoginURL, time.Minute, 15*time.Minute))
+
+		out, err := uc.Discovery(s.ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, out.IntrospectionEndpoint)
+	})
+
+	s.T().Run("should refuse when OpenID Connect is not enabled", func(t *testing.T) {
+		_, err := s.useCase.Discovery(s.ctx)
+		assert.Equal(t, usecase.ErrOIDCDisabled, err)
+	})
+
+	s.T().Run("should not advertise a provider browsers cannot sign in to", func(t *testing.T) {
+		uc := usecase.NewOAuthUseCase(s.mockRepo, s.mockJWTSigner, testIntrospectionClientID, testIntrospectionClientSecret,
+			usecase.WithClientCredentials(s.mockClients, time.Hour),
+			usecase.WithOpenIDConnect(s.mockCodes, s.mockUsers, "https://auth.example.com/prod/", "", time.Minute, 15*time.Minute))
+
+		_, err := uc.Discovery(s.ctx)
+		assert.Equal(t, usecase.ErrOIDCDisabled, err)
+	})
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+)
+
+// Option configures optional collaborators and settings of the user use case.
+type Option func(*userUseCase)
+
+// WithNotifier sets how one-time tokens such as password reset tokens reach the user.
+func WithNotifier(n port.Notifier) Option {
+	return func(u *userUseCase) {
+		u.notifier = n
+	}
+}
+
+// WithPasswordPolicy sets the rules new passwords must follow on registration and reset.
+func WithPasswordPolicy(policy PasswordPolicy) Option {
+	return func(u *userUseCase) {
+		u.passwordPolicy = policy
+	}
+}
+
+// WithPasswordReset enables the forgot/reset password flow; reset tokens expire after ttl.
+// It requires WithNotifier.
+func WithPasswordReset(tokens port.OneTimeTokenRepository, ttl time.Duration) Option {
+	return func(u *userUseCase) {
+		u.oneTimeTokens = tokens
+		u.resetTTL = ttl
+	}
+}
+
+// WithMagicLinks enables passwordless login through single-use links emailed to the user
+// that expire after ttl. Links point at linkURL, the frontend page that exchanges the token
+// query parameter for tokens. It requires WithNotifier.
+func WithMagicLinks(tokens port.OneTimeTokenRepository, ttl time.Duration, linkURL string) Option {
+	return func(u *userUseCase) {
+		u.oneTimeTokens = tokens
+		u.magicLinkTTL = ttl
+		u.magicLinkURL = linkURL
+	}
+}
+
+// WithEmailVerification makes new accounts start unverified and sends them a verification
+// token that expires after ttl. When required is set, Login refuses unverified accounts.
+// It requires WithNotifier.
+func WithEmailVerification(tokens port.OneTimeTokenRepository, ttl time.Duration, required bool) Option {
+	return func(u *userUseCase) {
+		u.oneTimeTokens = tokens
+		u.verifyTTL = ttl
+		u.verifyEmail = true
+		u.requireVerified = required
+	}
+}
+
+// WithEmailChange lets users change their email address; the new address must be
+// confirmed with a token that expires after ttl. It requires WithNotifier.
+func WithEmailChange(tokens port.OneTimeTokenRepository, ttl time.Duration) Option {
+	return func(u *userUseCase) {
+		u.oneTimeTokens = tokens
+		u.emailChangeTTL = ttl
+	}
+}
+
+// WithEnumerationSafeRegistration makes Register answer a registration for an email that is
+// already taken exactly like a successful one, without the new user's ID, and notify the
+// account's owner instead. It requires WithNotifier.
+func WithEnumerationSafeRegistration() Option {
+	return func(u *userUseCase) {
+		u.enumerationSafe = true
+	}
+}
+
+// WithTOTP enables TOTP multi-factor authentication. Secrets are encrypted with cipher and
+// labelled with issuer in authenticator apps; login challenges expire after challengeTTL.
+func WithTOTP(cipher port.SecretCipher, tokens port.OneTimeTokenRepository, issuer string, challengeTTL time.Duration) Option {
+	return func(u *userUseCase) {
+		u.cipher = cipher
+		u.oneTimeTokens = tokens
+		u.mfaIssuer = issuer
+		u.mfaChallengeTTL = challengeTTL
+	}
+}
+
+// WithPasskeys enables WebAuthn registration and login for the relying party rpID (the
+// site's domain), shown to users as rpName. Ceremony challenges expire after challengeTTL.
+func WithPasskeys(webAuthn port.WebAuthn, credentials port.PasskeyCredentialRepository, tokens port.OneTimeTokenRepository, rpID, rpName string, challengeTTL time.Duration) Option {
+	return func(u *userUseCase) {
+		u.webAuthn = webAuthn
+		u.passkeys = credentials
+		u.oneTimeTokens = tokens
+		u.rpID = rpID
+		u.rpName = rpName
+		u.passkeyTTL = challengeTTL
+	}
+}
+
+// WithFederatedLogin enables sign-in with external OpenID Connect providers, keyed by the
+// name used in the login URLs. A first sign-in links the external account to the user with
+// the same verified email, creating one when there is none. Sign-ins must be completed
+// within stateTTL.
+func WithFederatedLogin(providers map[string]port.IdentityProvider, identities port.FederatedIdentityRepository, tokens port.OneTimeTokenRepository, stateTTL time.Duration) Option {
+	return func(u *userUseCase) {
+		u.identityProviders = providers
+		u.federatedIdentities = identities
+		u.oneTimeTokens = tokens
+		u.federatedLoginTTL = stateTTL
+	}
+}
+
+// WithPersonalAccessTokens lets users create personal access tokens and authenticate with
+// them. When maxTTL is set, tokens must expire within it.
+func WithPersonalAccessTokens(repo port.PersonalAccessTokenRepository, maxTTL time.Duration) Option {
+	return func(u *userUseCase) {
+		u.personalAccessTokens = repo
+		u.personalAccessTokenMaxTTL = maxTTL
+	}
+}
+
+// WithImpersonation lets admins obtain access tokens acting as other users, valid for ttl.
+// Every impersonation is recorded in audit.
+func WithImpersonation(audit port.AuditLog, ttl time.Duration) Option {
+	return func(u *userUseCase) {
+		u.auditLog = audit
+		u.impersonationTTL = ttl
+	}
+}
+
+// WithLockout locks an account for base after threshold consecutive failed logins (wrong
+// password or MFA code). Each further failure locks it again for twice as long, up to max.
+func WithLockout(threshold int, base, max time.Duration) Option {
+	return func(u *userUseCase) {
+		u.lockoutThreshold = threshold
+		u.lockoutBase = base
+		u.lockoutMax = max
+	}
+}
+
+// WithRefreshTokens enables refresh-token issuance on login and the refresh grant.
+func WithRefreshTokens(repo port.RefreshTokenRepository, ttl time.Duration) Option {
+	return func(u *userUseCase) {
+		u.refreshTokens = repo
+		u.refreshTTL = ttl
+	}
+}
+
+// WithSessions records a session for every login, embeds its ID in the access tokens and
+// lets users list and revoke their sessions. It requires WithRefreshTokens: a session
+// lives as long as its refresh token family.
+func WithSessions(repo port.SessionRepository) Option {
+	return func(u *userUseCase) {
+		u.sessions = repo
+	}
+}
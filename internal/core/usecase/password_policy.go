@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// bcryptMaxBytes is the longest password bcrypt hashes; it refuses longer ones.
+const bcryptMaxBytes = 72
+
+// minPersonalLength is the shortest name or email part the policy looks for in a password,
+// so that short names such as "Li" do not rule out half the dictionary.
+const minPersonalLength = 3
+
+var ErrWeakPassword = errors.New("password does not meet the password policy")
+
+// PasswordPolicyError lists every rule a password broke, in a form meant for the client.
+// It matches ErrWeakPassword with errors.Is.
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return ErrWeakPassword.Error() + ": " + strings.Join(e.Violations, "; ")
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrWeakPassword
+}
+
+// PasswordPolicy holds the rules new passwords must follow. Passwords are NFKC-normalized
+// before they are checked and hashed, so the same password typed on different keyboards
+// or input methods matches. The zero value only enforces bcrypt's 72-byte limit.
+type PasswordPolicy struct {
+	MinLength      int // in characters
+	MaxLength      int // in bytes of the normalized password; capped at bcrypt's 72
+	RequireLower   bool
+	RequireUpper   bool
+	RequireDigit   bool
+	RequireSymbol  bool // anything but a letter or a digit, including spaces
+	RejectPersonal bool // refuse passwords containing the user's email or name
+	RejectCommon   bool // refuse passwords from the built-in list of common passwords
+}
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var commonPasswords = sync.OnceValue(func() map[string]struct{} {
+	set := map[string]struct{}{}
+	for _, line := range strings.Split(commonPasswordsFile, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			set[line] = struct{}{}
+		}
+	}
+	return set
+})
+
+// normalizePassword applies the Unicode normalization NIST SP 800-63B recommends before
+// hashing a password.
+func normalizePassword(password string) string {
+	return norm.NFKC.String(password)
+}
+
+// Check returns a *PasswordPolicyError listing every rule password breaks, or nil. Personal
+// information is only checked when email or name is given.
+func (p PasswordPolicy) Check(password, email, name string) error {
+	password = normalizePassword(password)
+	var violations []string
+
+	if utf8.RuneCountInString(password) < p.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", p.MinLength))
+	}
+	maxBytes := p.MaxLength
+	if maxBytes <= 0 || maxBytes > bcryptMaxBytes {
+		maxBytes = bcryptMaxBytes
+	}
+	if len(password) > maxBytes {
+		violations = append(violations, fmt.Sprintf("must be at most %d bytes long", maxBytes))
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r):
+			symbol = true
+		}
+	}
+	if p.RequireLower && !lower {
+		violations = append(violations, "must contain a lowercase letter")
+	}
+	if p.RequireUpper && !upper {
+		violations = append(violations, "must contain an uppercase letter")
+	}
+	if p.RequireDigit && !digit {
+		violations = append(violations, "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		violations = append(violations, "must contain a symbol")
+	}
+
+	folded := strings.ToLower(password)
+	if p.RejectPersonal {
+		if containsEmail(folded, email) {
+			violations = append(violations, "must not contain your email address")
+		}
+		if containsName(folded, name) {
+			violations = append(violations, "must not contain your name")
+		}
+	}
+	if p.RejectCommon {
+		if _, ok := commonPasswords()[folded]; ok {
+			violations = append(violations, "is too common")
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// containsEmail reports whether the lowercased password contains the email or its local part.
+func containsEmail(password, email string) bool {
+	email = strings.ToLower(normalizePassword(strings.TrimSpace(email)))
+	local, _, _ := strings.Cut(email, "@")
+	return utf8.RuneCountInString(local) >= minPersonalLength && strings.Contains(password, local)
+}
+
+// containsName reports whether the lowercased password contains any word of the name.
+func containsName(password, name string) bool {
+	for _, word := range strings.Fields(strings.ToLower(normalizePassword(name))) {
+		if utf8.RuneCountInString(word) >= minPersonalLength && strings.Contains(password, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	strict := PasswordPolicy{
+		MinLength:      10,
+		RequireLower:   true,
+		RequireUpper:   true,
+		RequireDigit:   true,
+		RequireSymbol:  true,
+		RejectPersonal: true,
+		RejectCommon:   true,
+	}
+	tests := []struct {
+		name       string
+		policy     PasswordPolicy
+		password   string
+		userName   string // defaults to John Doe
+		violations []string
+	}{
+		{
+			name:     "zero policy accepts anything up to 72 bytes",
+			password: "a",
+		},
+		{
+			name:       "zero policy refuses what bcrypt cannot hash",
+			password:   strings.Repeat("a", 73),
+			violations: []string{"must be at most 72 bytes long"},
+		},
+		{
+			name:       "max length is capped at 72 bytes",
+			policy:     PasswordPolicy{MaxLength: 100},
+			password:   strings.Repeat("a", 73),
+			violations: []string{"must be at most 72 bytes long"},
+		},
+		{
+			name:     "strict policy accepts a strong password",
+			policy:   strict,
+			password: "Tr0ub4dor&3-horse",
+		},
+		{
+			name:     "reports every broken rule",
+			policy:   strict,
+			password: "john",
+			violations: []string{
+				"must be at least 10 characters long",
+				"must contain an uppercase letter",
+				"must contain a digit",
+				"must contain a symbol",
+				"must not contain your email address",
+				"must not contain your name",
+			},
+		},
+		{
+			name:       "length counts characters, not bytes",
+			policy:     PasswordPolicy{MinLength: 4},
+			password:   "ççç",
+			violations: []string{"must be at least 4 characters long"},
+		},
+		{
+			name:       "rejects common passwords regardless of case",
+			policy:     PasswordPolicy{RejectCommon: true},
+			password:   "PassWord123",
+			violations: []string{"is too common"},
+		},
+		{
+			name:       "normalizes before checking",
+			policy:     PasswordPolicy{RejectCommon: true},
+			password:   "ｐａｓｓｗｏｒｄ", // fullwidth forms fold to ASCII under NFKC
+			violations: []string{"is too common"},
+		},
+		{
+			name:       "looks for name parts case-insensitively",
+			policy:     PasswordPolicy{RejectPersonal: true},
+			password:   "correct-DOE-battery",
+			violations: []string{"must not contain your name"},
+		},
+		{
+			name:     "ignores name parts too short to matter",
+			policy:   PasswordPolicy{RejectPersonal: true},
+			password: "correct-li-battery",
+			userName: "Li Wei",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			name := tt.userName
+			if name == "" {
+				name = "John Doe"
+			}
+
+			// Act
+			err := tt.policy.Check(tt.password, "john@example.com", name)
+
+			// Assert
+			if tt.violations == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var policyErr *PasswordPolicyError
+			assert.True(t, errors.As(err, &policyErr))
+			assert.ErrorIs(t, err, ErrWeakPassword)
+			assert.Equal(t, tt.violations, policyErr.Violations)
+		})
+	}
+}
+
+func TestPasswordPolicy_Check_WithoutPersonalInfo(t *testing.T) {
+	policy := PasswordPolicy{RejectPersonal: true}
+	assert.NoError(t, policy.Check("john-doe-example", "", ""))
+}
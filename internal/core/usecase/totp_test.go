@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 appendix B, SHA-1 vectors truncated to six digits.
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, totpCode(secret, tt.unix/totpPeriod), "t=%d", tt.unix)
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1111111109, 0)
+	step := now.Unix() / totpPeriod
+
+	got, ok := verifyTOTP(secret, totpCode(secret, step), now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, step, got)
+
+	_, ok = verifyTOTP(secret, totpCode(secret, step-1), now, 0)
+	assert.True(t, ok, "previous step is accepted for clock drift")
+
+	_, ok = verifyTOTP(secret, totpCode(secret, step-2), now, 0)
+	assert.False(t, ok)
+
+	_, ok = verifyTOTP(secret, totpCode(secret, step), now, step)
+	assert.False(t, ok, "a code cannot be replayed")
+
+	_, ok = verifyTOTP(secret, "12345", now, 0)
+	assert.False(t, ok)
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := totpURI("Hackathon", "john@example.com", []byte("12345678901234567890"))
+	assert.Equal(t, "otpauth://totp/Hackathon:john@example.com?algorithm=SHA1&digits=6&issuer=Hackathon&period=30&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", uri)
+}
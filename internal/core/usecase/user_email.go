@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+)
+
+var (
+	ErrEmailChangeDisabled     = errors.New("email change is not enabled")
+	ErrInvalidEmailChangeToken = errors.New("invalid or expired email change token")
+	ErrEmailChangeConflict     = errors.New("the email address changed in the meantime, try again")
+)
+
+// RequestEmailChange records newEmail as the user's pending email and sends a confirmation
+// token to it, plus a notice to the current address. The email only changes once the token
+// is confirmed; a later request supersedes the pending one. The current password is
+// required, and wrong ones count towards the account lockout.
+func (u *userUseCase) RequestEmailChange(ctx context.Context, in dto.ChangeEmailInput) error {
+	if in.UserID <= 0 || in.NewEmail == "" || in.Password == "" {
+		return ErrInvalidInput
+	}
+	if u.emailChangeTTL <= 0 || u.oneTimeTokens == nil || u.notifier == nil {
+		return ErrEmailChangeDisabled
+	}
+	user, err := u.repo.GetByID(ctx, in.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if u.isLocked(user) {
+		return ErrAccountLocked
+	}
+	ok, err := u.checkPassword(user, in.Password)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return u.failLogin(ctx, user, ErrInvalidCredentials)
+	}
+	if in.NewEmail == user.Email {
+		return ErrInvalidInput
+	}
+	existing, err := u.repo.GetByEmail(ctx, in.NewEmail)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if u.enumerationSafe {
+			return nil
+		}
+		return ErrEmailAlreadyExists
+	}
+
+	now := time.Now()
+	set, err := u.repo.SetPendingEmail(ctx, user.UserID, user.Email, in.NewEmail, now.Unix())
+	if err != nil {
+		return err
+	}
+	if !set {
+		return ErrEmailChangeConflict
+	}
+	token, hash, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+	ott := &domain.OneTimeToken{
+		TokenHash: hash,
+		Purpose:   domain.TokenPurposeEmailChange,
+		UserID:    user.UserID,
+		Email:     in.NewEmail,
+		CreatedAt: now.Unix(),
+		ExpiresAt: now.Add(u.emailChangeTTL).Unix(),
+	}
+	if err := u.oneTimeTokens.Create(ctx, ott); err != nil {
+		return err
+	}
+	if err := u.notifier.Notify(ctx, domain.Notification{
+		Type:      domain.NotificationEmailChange,
+		UserID:    user.UserID,
+		To:        in.NewEmail,
+		Name:      user.Name,
+		Token:     token,
+		ExpiresAt: ott.ExpiresAt,
+	}); err != nil {
+		return err
+	}
+	return u.notifier.Notify(ctx, domain.Notification{
+		Type:   domain.NotificationEmailChangeNotice,
+		UserID: user.UserID,
+		To:     user.Email,
+		Name:   user.Name,
+	})
+}
+
+// ConfirmEmailChange consumes an email change token and makes the address it was sent to
+// the user's email, provided it is still the pending one and no other account took it in
+// the meantime. The new address counts as verified.
+func (u *userUseCase) ConfirmEmailChange(ctx context.Context, in dto.ConfirmEmailChangeInput) error {
+	if in.Token == "" {
+		return ErrInvalidInput
+	}
+	if u.emailChangeTTL <= 0 || u.oneTimeTokens == nil {
+		return ErrEmailChangeDisabled
+	}
+	now := time.Now().Unix()
+	ott, err := u.oneTimeTokens.Consume(ctx, hashOpaqueToken(in.Token), domain.TokenPurposeEmailChange, now)
+	if err != nil {
+		return err
+	}
+	if ott == nil {
+		return ErrInvalidEmailChangeToken
+	}
+	user, err := u.repo.GetByID(ctx, ott.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil || ott.Email == "" || user.PendingEmail != ott.Email {
+		return ErrInvalidEmailChangeToken
+	}
+	// accounts created before email claims existed are only found through the index
+	existing, err := u.repo.GetByEmail(ctx, ott.Email)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.UserID != user.UserID {
+		return ErrEmailAlreadyExists
+	}
+	changed, err := u.repo.ChangeEmail(ctx, user.UserID, ott.Email, now)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return ErrEmailAlreadyExists
+	}
+	return nil
+}
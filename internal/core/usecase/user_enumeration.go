@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+)
+
+// burnPasswordCheck verifies password against a hash of a random password, so that a login
+// for an unknown email takes as long as one with a wrong password.
+func (u *userUseCase) burnPasswordCheck(password string) {
+	u.dummyHashOnce.Do(func() {
+		if secret, _, err := newOpaqueToken(); err == nil {
+			u.dummyHash, _ = u.hasher.Hash(secret)
+		}
+	})
+	if u.dummyHash != "" {
+		_, _ = u.hasher.Verify(u.dummyHash, normalizePassword(password))
+	}
+}
+
+// registerExisting answers a registration for an email that already has an account the
+// same way a successful registration is answered, and lets the owner know out-of-band
+// instead. The notice carries no token: an owner who forgot the password can ask for a
+// reset like anyone else.
+func (u *userUseCase) registerExisting(ctx context.Context, owner *domain.User, in dto.RegisterInput) (*dto.RegisterOutput, error) {
+	// a new account would get its password hashed; take as long
+	_, _ = u.hasher.Hash(normalizePassword(in.Password))
+
+	// delivery failures must not show in the response either
+	_ = u.notifier.Notify(ctx, domain.Notification{
+		Type:   domain.NotificationAccountExists,
+		UserID: owner.UserID,
+		To:     owner.Email,
+		Name:   owner.Name,
+	})
+	return &dto.RegisterOutput{Name: in.Name, Email: in.Email}, nil
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+)
+
+var (
+	ErrFederatedLoginDisabled    = errors.New("federated login is not enabled")
+	ErrUnknownIdentityProvider   = errors.New("unknown identity provider")
+	ErrInvalidFederatedLogin     = errors.New("invalid or expired federated login")
+	ErrFederatedEmailNotVerified = errors.New("identity provider did not assert a verified email")
+	ErrFederatedAccountConflict  = errors.New("an account with this email exists but its email is not verified")
+)
+
+// BeginFederatedLogin starts a sign-in at an external identity provider. The returned state
+// identifies the attempt; the nonce and PKCE verifier that go with it never leave the
+// server.
+func (u *userUseCase) BeginFederatedLogin(ctx context.Context, in dto.BeginFederatedLoginInput) (*dto.BeginFederatedLoginOutput, error) {
+	idp, err := u.identityProvider(in.Provider)
+	if err != nil {
+		return nil, err
+	}
+	state, hash, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, _, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, _, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := u.oneTimeTokens.Create(ctx, &domain.OneTimeToken{
+		TokenHash:    hash,
+		Purpose:      domain.TokenPurposeFederatedLogin,
+		Provider:     in.Provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		CreatedAt:    now.Unix(),
+		ExpiresAt:    now.Add(u.federatedLoginTTL).Unix(),
+	}); err != nil {
+		return nil, err
+	}
+	authURL, err := idp.AuthorizationURL(ctx, state, nonce, pkceChallenge(verifier))
+	if err != nil {
+		return nil, err
+	}
+	return &dto.BeginFederatedLoginOutput{AuthorizationURL: authURL, State: state}, nil
+}
+
+// FinishFederatedLogin redeems the provider's authorization code for the sign-in started
+// with state and issues the tokens of a password login. Like a magic link, the provider
+// stands in for the password only: accounts with MFA still get a challenge.
+func (u *userUseCase) FinishFederatedLogin(ctx context.Context, in dto.FinishFederatedLoginInput) (*dto.LoginOutput, error) {
+	if in.Code == "" || in.State == "" {
+		return nil, ErrInvalidInput
+	}
+	idp, err := u.identityProvider(in.Provider)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	ott, err := u.oneTimeTokens.Consume(ctx, hashOpaqueToken(in.State), domain.TokenPurposeFederatedLogin, now)
+	if err != nil {
+		return nil, err
+	}
+	if ott == nil || ott.Provider != in.Provider {
+		return nil, ErrInvalidFederatedLogin
+	}
+	ext, err := idp.Exchange(ctx, in.Code, ott.CodeVerifier, ott.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFederatedLogin, err)
+	}
+	ext.Provider = in.Provider
+	user, err := u.federatedUser(ctx, ext, now)
+	if err != nil {
+		return nil, err
+	}
+	if u.isLocked(user) {
+		return nil, ErrAccountLocked
+	}
+	if user.MFAEnabled {
+		return u.mfaChallenge(ctx, user)
+	}
+	if err := u.clearLoginFailures(ctx, user); err != nil {
+		return nil, err
+	}
+	return u.startSession(ctx, user, in.Client)
+}
+
+// federatedUser returns the user an external identity signs in as. A linked identity keeps
+// its user; otherwise it is linked to the account with the same email, which both sides
+// must have verified, or to a new account without a password.
+func (u *userUseCase) federatedUser(ctx context.Context, ext *domain.ExternalIdentity, now int64) (*domain.User, error) {
+	link, err := u.federatedIdentities.Get(ctx, ext.Provider, ext.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if link != nil {
+		user, err := u.repo.GetByID(ctx, link.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, ErrInvalidFederatedLogin
+		}
+		return user, nil
+	}
+
+	if ext.Email == "" || !ext.EmailVerified {
+		return nil, ErrFederatedEmailNotVerified
+	}
+	user, err := u.repo.GetByEmail(ctx, ext.Email)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case user == nil:
+		user = &domain.User{
+			Name:          federatedName(ext),
+			Email:         ext.Email,
+			EmailVerified: true,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if err := u.repo.Create(ctx, user); err != nil {
+			return nil, err
+		}
+	case !user.EmailVerified:
+		// whoever registered the address never proved owning it; linking would let them in
+		// to an account the provider's user then believes is theirs
+		return nil, ErrFederatedAccountConflict
+	}
+	created, err := u.federatedIdentities.Create(ctx, &domain.FederatedIdentity{
+		Provider:  ext.Provider,
+		Subject:   ext.Subject,
+		UserID:    user.UserID,
+		Email:     ext.Email,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		// a concurrent sign-in linked the identity first; the user can simply retry
+		return nil, ErrInvalidFederatedLogin
+	}
+	return user, nil
+}
+
+func (u *userUseCase) identityProvider(name string) (port.IdentityProvider, error) {
+	if len(u.identityProviders) == 0 || u.federatedIdentities == nil || u.oneTimeTokens == nil {
+		return nil, ErrFederatedLoginDisabled
+	}
+	idp, ok := u.identityProviders[name]
+	if !ok {
+		return nil, ErrUnknownIdentityProvider
+	}
+	return idp, nil
+}
+
+// federatedName is the name of an account created by federated sign-in: the one asserted
+// by the provider, else the local part of the email.
+func federatedName(ext *domain.ExternalIdentity) string {
+	if ext.Name != "" {
+		return ext.Name
+	}
+	local, _, _ := strings.Cut(ext.Email, "@")
+	return local
+}
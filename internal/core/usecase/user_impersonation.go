@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+)
+
+var (
+	ErrImpersonationDisabled = errors.New("impersonation is not enabled")
+	ErrAdminRequired         = errors.New("admin role required")
+	ErrCannotImpersonate     = errors.New("admins cannot be impersonated")
+)
+
+// Impersonate issues a short-lived access token for a user on behalf of an admin, so
+// support staff can reproduce what the user sees. The token names the admin in its act
+// claim and comes without a refresh token or session. The admin role is read back from the
+// repository rather than trusted from the caller's token, and the impersonation is written
+// to the audit log, with its reason, before the token exists.
+func (u *userUseCase) Impersonate(ctx context.Context, in dto.ImpersonateInput) (*dto.ImpersonateOutput, error) {
+	if in.AdminID <= 0 || in.UserID <= 0 || strings.TrimSpace(in.Reason) == "" {
+		return nil, ErrInvalidInput
+	}
+	if u.impersonationTTL <= 0 || u.auditLog == nil {
+		return nil, ErrImpersonationDisabled
+	}
+	admin, err := u.repo.GetByID(ctx, in.AdminID)
+	if err != nil {
+		return nil, err
+	}
+	if admin == nil || !admin.HasRole(domain.RoleAdmin) {
+		return nil, ErrAdminRequired
+	}
+	user, err := u.repo.GetByID(ctx, in.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.HasRole(domain.RoleAdmin) {
+		// this also keeps admins from impersonating themselves
+		return nil, ErrCannotImpersonate
+	}
+
+	now := time.Now()
+	if err := u.auditLog.Record(ctx, domain.AuditEvent{
+		Type:        domain.AuditImpersonationStarted,
+		ActorUserID: admin.UserID,
+		UserID:      user.UserID,
+		Reason:      strings.TrimSpace(in.Reason),
+		At:          now.Unix(),
+	}); err != nil {
+		return nil, err
+	}
+	principal := principalFor(user)
+	principal.ActorUserID = admin.UserID
+	principal.ActorEmail = admin.Email
+	principal.ExpiresAt = now.Add(u.impersonationTTL).Unix()
+	token, err := u.jwtSigner.Sign(principal)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ImpersonateOutput{Token: token, ExpiresIn: int64(u.impersonationTTL.Seconds()), UserID: user.UserID}, nil
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+var ErrAccountLocked = errors.New("account temporarily locked after too many failed logins")
+
+// isLocked reports whether logins of the user are currently refused.
+func (u *userUseCase) isLocked(user *domain.User) bool {
+	return user.LockedUntil > time.Now().Unix()
+}
+
+// failLogin records a wrong password or MFA code and returns the error to report: cause,
+// or ErrAccountLocked once the failures reached the lockout threshold. Every failure past
+// the threshold locks the account again, for twice as long as the previous time.
+func (u *userUseCase) failLogin(ctx context.Context, user *domain.User, cause error) error {
+	if u.lockoutThreshold <= 0 {
+		return cause
+	}
+	failures, err := u.repo.RecordLoginFailure(ctx, user.UserID)
+	if err != nil {
+		return err
+	}
+	if failures < u.lockoutThreshold {
+		return cause
+	}
+	window := lockoutWindow(failures-u.lockoutThreshold, u.lockoutBase, u.lockoutMax)
+	if err := u.repo.LockUntil(ctx, user.UserID, time.Now().Add(window).Unix()); err != nil {
+		return err
+	}
+	return ErrAccountLocked
+}
+
+// clearLoginFailures resets the failure counter after a successful login.
+func (u *userUseCase) clearLoginFailures(ctx context.Context, user *domain.User) error {
+	if user.FailedLogins == 0 && user.LockedUntil == 0 {
+		return nil
+	}
+	return u.repo.ResetLoginFailures(ctx, user.UserID)
+}
+
+// lockoutWindow doubles base once per failure beyond the threshold, capped at max.
+func lockoutWindow(excess int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 0; i < excess && d < max; i++ {
+		d *= 2
+	}
+	return min(d, max)
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+)
+
+var (
+	ErrMagicLinkDisabled = errors.New("magic-link login is not enabled")
+	ErrInvalidMagicLink  = errors.New("invalid or expired magic link")
+)
+
+// RequestMagicLink emails a single-use login link to the account. Like ForgotPassword it
+// succeeds whether or not the email is registered.
+func (u *userUseCase) RequestMagicLink(ctx context.Context, in dto.MagicLinkInput) error {
+	if in.Email == "" {
+		return ErrInvalidInput
+	}
+	if u.magicLinkTTL <= 0 || u.magicLinkURL == "" || u.oneTimeTokens == nil || u.notifier == nil {
+		return ErrMagicLinkDisabled
+	}
+	user, err := u.repo.GetByEmail(ctx, in.Email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+	token, ott, err := u.createOneTimeToken(ctx, user.UserID, domain.TokenPurposeMagicLink, u.magicLinkTTL)
+	if err != nil {
+		return err
+	}
+	link, err := magicLink(u.magicLinkURL, token)
+	if err != nil {
+		return err
+	}
+	return u.notifier.Notify(ctx, domain.Notification{
+		Type:      domain.NotificationMagicLink,
+		UserID:    user.UserID,
+		To:        user.Email,
+		Name:      user.Name,
+		Token:     token,
+		Link:      link,
+		ExpiresAt: ott.ExpiresAt,
+	})
+}
+
+// magicLink adds token to the query of base, keeping any parameters it already has.
+func magicLink(base, token string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// ConsumeMagicLink exchanges a magic-link token for the tokens of a password login. The
+// link stands in for the password only: accounts with MFA still get a challenge. Since it
+// was delivered by email, using it verifies the account's email as well.
+func (u *userUseCase) ConsumeMagicLink(ctx context.Context, in dto.ConsumeMagicLinkInput) (*dto.LoginOutput, error) {
+	if in.Token == "" {
+		return nil, ErrInvalidInput
+	}
+	if u.magicLinkTTL <= 0 || u.magicLinkURL == "" || u.oneTimeTokens == nil {
+		return nil, ErrMagicLinkDisabled
+	}
+	now := time.Now().Unix()
+	ott, err := u.oneTimeTokens.Consume(ctx, hashOpaqueToken(in.Token), domain.TokenPurposeMagicLink, now)
+	if err != nil {
+		return nil, err
+	}
+	if ott == nil {
+		return nil, ErrInvalidMagicLink
+	}
+	user, err := u.repo.GetByID(ctx, ott.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidMagicLink
+	}
+	if u.isLocked(user) {
+		return nil, ErrAccountLocked
+	}
+	if !user.EmailVerified {
+		if err := u.repo.MarkEmailVerified(ctx, user.UserID, now); err != nil {
+			return nil, err
+		}
+		user.EmailVerified = true
+	}
+	if user.MFAEnabled {
+		return u.mfaChallenge(ctx, user)
+	}
+	if err := u.clearLoginFailures(ctx, user); err != nil {
+		return nil, err
+	}
+	return u.startSession(ctx, user, in.Client)
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+)
+
+var (
+	ErrMFADisabled       = errors.New("multi-factor authentication is not enabled")
+	ErrMFAAlreadyEnabled = errors.New("multi-factor authentication is already enabled")
+	ErrMFANotEnrolled    = errors.New("no pending TOTP enrollment")
+	ErrInvalidMFAToken   = errors.New("invalid or expired mfa token")
+	ErrInvalidMFACode    = errors.New("invalid mfa code")
+)
+
+// EnrollTOTP generates a new TOTP secret for the user and stores it encrypted. MFA only
+// takes effect once the user proves their authenticator works through ConfirmTOTP; until
+// then enrolling again simply replaces the secret.
+func (u *userUseCase) EnrollTOTP(ctx context.Context, userID int64) (*dto.EnrollTOTPOutput, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if u.cipher == nil {
+		return nil, ErrMFADisabled
+	}
+	user, err := u.repo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := u.cipher.Encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+	set, err := u.repo.SetTOTPSecret(ctx, user.UserID, encrypted, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	if !set {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	return &dto.EnrollTOTPOutput{
+		Secret: totpEncoding.EncodeToString(secret),
+		URI:    totpURI(u.mfaIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables MFA once the user submits a valid code for the enrolled secret.
+func (u *userUseCase) ConfirmTOTP(ctx context.Context, in dto.ConfirmTOTPInput) error {
+	if in.UserID == 0 {
+		return ErrInvalidUserID
+	}
+	if in.Code == "" {
+		return ErrInvalidInput
+	}
+	if u.cipher == nil {
+		return ErrMFADisabled
+	}
+	user, err := u.repo.GetByID(ctx, in.UserID)
+	if err != nil || user == nil {
+		return ErrUserNotFound
+	}
+	if user.MFAEnabled {
+		return ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return ErrMFANotEnrolled
+	}
+	ok, err := u.checkTOTP(ctx, user, in.Code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidMFACode
+	}
+	return u.repo.EnableMFA(ctx, user.UserID, time.Now().Unix())
+}
+
+// LoginMFA completes a login that was answered with an MFA challenge. The challenge token
+// is single-use: a wrong code means starting over with the password.
+func (u *userUseCase) LoginMFA(ctx context.Context, in dto.LoginMFAInput) (*dto.LoginOutput, error) {
+	if in.MFAToken == "" || in.Code == "" {
+		return nil, ErrInvalidInput
+	}
+	if u.cipher == nil || u.oneTimeTokens == nil {
+		return nil, ErrMFADisabled
+	}
+	ott, err := u.oneTimeTokens.Consume(ctx, hashOpaqueToken(in.MFAToken), domain.TokenPurposeMFAChallenge, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	if ott == nil {
+		return nil, ErrInvalidMFAToken
+	}
+	user, err := u.repo.GetByID(ctx, ott.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.MFAEnabled {
+		return nil, ErrInvalidMFAToken
+	}
+	if u.isLocked(user) {
+		return nil, ErrAccountLocked
+	}
+	ok, err := u.checkTOTP(ctx, user, in.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, u.failLogin(ctx, user, ErrInvalidMFACode)
+	}
+	if err := u.clearLoginFailures(ctx, user); err != nil {
+		return nil, err
+	}
+	return u.startSession(ctx, user, in.Client)
+}
+
+// mfaChallenge answers a successful password check of an MFA-enabled account.
+func (u *userUseCase) mfaChallenge(ctx context.Context, user *domain.User) (*dto.LoginOutput, error) {
+	if u.cipher == nil || u.oneTimeTokens == nil {
+		return nil, ErrMFADisabled
+	}
+	token, _, err := u.createOneTimeToken(ctx, user.UserID, domain.TokenPurposeMFAChallenge, u.mfaChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.LoginOutput{MFARequired: true, MFAToken: token}, nil
+}
+
+// checkTOTP verifies a code against the user's secret and, on success, stores its time step
+// so the same code is refused next time. The step is claimed atomically: when a concurrent
+// request used the code first, the code counts as invalid.
+func (u *userUseCase) checkTOTP(ctx context.Context, user *domain.User, code string) (bool, error) {
+	secret, err := u.cipher.Decrypt(user.TOTPSecret)
+	if err != nil {
+		return false, err
+	}
+	step, ok := verifyTOTP(secret, code, time.Now(), user.TOTPLastStep)
+	if !ok {
+		return false, nil
+	}
+	used, err := u.repo.UseTOTPStep(ctx, user.UserID, step)
+	if err != nil || !used {
+		return false, err
+	}
+	user.TOTPLastStep = step
+	return true, nil
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+)
+
+var (
+	ErrPasskeysDisabled = errors.New("passkeys are not enabled")
+	ErrInvalidPasskey   = errors.New("invalid passkey response")
+	ErrPasskeyExists    = errors.New("passkey already registered")
+	ErrPasskeyCloned    = errors.New("passkey signature counter did not increase")
+)
+
+// passkeyAlgorithms are offered to authenticators in this order of preference.
+var passkeyAlgorithms = []int64{domain.COSEAlgES256, domain.COSEAlgEdDSA, domain.COSEAlgRS256}
+
+// BeginPasskeyRegistration issues the challenge and options for creating a passkey on the
+// user's authenticator. Credentials the user already registered are excluded so the same
+// authenticator is not enrolled twice.
+func (u *userUseCase) BeginPasskeyRegistration(ctx context.Context, userID int64) (*dto.BeginPasskeyRegistrationOutput, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if u.webAuthn == nil {
+		return nil, ErrPasskeysDisabled
+	}
+	user, err := u.repo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, ErrUserNotFound
+	}
+	existing, err := u.passkeys.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	exclude := make([]string, 0, len(existing))
+	for _, c := range existing {
+		exclude = append(exclude, c.CredentialID)
+	}
+	challenge, _, err := u.createOneTimeToken(ctx, userID, domain.TokenPurposePasskeyRegistration, u.passkeyTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.BeginPasskeyRegistrationOutput{
+		Challenge:          challenge,
+		RPID:               u.rpID,
+		RPName:             u.rpName,
+		UserHandle:         passkeyUserHandle(userID),
+		UserName:           user.Email,
+		Algorithms:         passkeyAlgorithms,
+		ExcludeCredentials: exclude,
+		Timeout:            u.passkeyTTL.Milliseconds(),
+	}, nil
+}
+
+// FinishPasskeyRegistration verifies the authenticator's attestation and stores the new
+// credential. The challenge must have been issued to the same user.
+func (u *userUseCase) FinishPasskeyRegistration(ctx context.Context, in dto.FinishPasskeyRegistrationInput) (*dto.PasskeyOutput, error) {
+	if in.UserID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if in.ClientDataJSON == "" || in.AttestationObject == "" {
+		return nil, ErrInvalidInput
+	}
+	if u.webAuthn == nil {
+		return nil, ErrPasskeysDisabled
+	}
+	challenge, cred, err := u.webAuthn.VerifyRegistration(in)
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+	now := time.Now().Unix()
+	ott, err := u.oneTimeTokens.Consume(ctx, hashOpaqueToken(challenge), domain.TokenPurposePasskeyRegistration, now)
+	if err != nil {
+		return nil, err
+	}
+	if ott == nil || ott.UserID != in.UserID {
+		return nil, ErrInvalidPasskey
+	}
+	cred.UserID = in.UserID
+	cred.Name = in.Name
+	if cred.Name == "" {
+		cred.Name = "Passkey"
+	}
+	cred.CreatedAt = now
+	created, err := u.passkeys.Create(ctx, cred)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrPasskeyExists
+	}
+	return &dto.PasskeyOutput{CredentialID: cred.CredentialID, Name: cred.Name, CreatedAt: cred.CreatedAt}, nil
+}
+
+// BeginPasskeyLogin issues a login challenge. It is not tied to an account: the user picks
+// one of their discoverable passkeys and the assertion names the credential.
+func (u *userUseCase) BeginPasskeyLogin(ctx context.Context) (*dto.BeginPasskeyLoginOutput, error) {
+	if u.webAuthn == nil {
+		return nil, ErrPasskeysDisabled
+	}
+	challenge, _, err := u.createOneTimeToken(ctx, 0, domain.TokenPurposePasskeyLogin, u.passkeyTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.BeginPasskeyLoginOutput{Challenge: challenge, RPID: u.rpID, Timeout: u.passkeyTTL.Milliseconds()}, nil
+}
+
+// FinishPasskeyLogin verifies an assertion and issues the same tokens as a password login.
+// A passkey already combines possession and user verification, so no TOTP code is asked,
+// but a locked account stays locked.
+func (u *userUseCase) FinishPasskeyLogin(ctx context.Context, in dto.FinishPasskeyLoginInput) (*dto.LoginOutput, error) {
+	if in.CredentialID == "" || in.ClientDataJSON == "" || in.AuthenticatorData == "" || in.Signature == "" {
+		return nil, ErrInvalidInput
+	}
+	if u.webAuthn == nil {
+		return nil, ErrPasskeysDisabled
+	}
+	cred, err := u.passkeys.GetByID(ctx, in.CredentialID)
+	if err != nil {
+		return nil, err
+	}
+	if cred == nil {
+		return nil, ErrInvalidPasskey
+	}
+	if in.UserHandle != "" && in.UserHandle != passkeyUserHandle(cred.UserID) {
+		return nil, ErrInvalidPasskey
+	}
+	challenge, signCount, err := u.webAuthn.VerifyAssertion(cred, in)
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+	now := time.Now().Unix()
+	ott, err := u.oneTimeTokens.Consume(ctx, hashOpaqueToken(challenge), domain.TokenPurposePasskeyLogin, now)
+	if err != nil {
+		return nil, err
+	}
+	if ott == nil {
+		return nil, ErrInvalidPasskey
+	}
+	// WebAuthn §6.1.1: a counter that does not move forward hints at a cloned authenticator.
+	// Synced passkeys report 0 on every use and are exempt.
+	if (signCount != 0 || cred.SignCount != 0) && signCount <= cred.SignCount {
+		return nil, ErrPasskeyCloned
+	}
+	// the check above ran on a snapshot; the store repeats it atomically
+	updated, err := u.passkeys.UpdateSignCount(ctx, cred.CredentialID, signCount, now)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrPasskeyCloned
+	}
+	user, err := u.repo.GetByID(ctx, cred.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidPasskey
+	}
+	if u.isLocked(user) {
+		return nil, ErrAccountLocked
+	}
+	if u.requireVerified && !user.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+	return u.startSession(ctx, user, in.Client)
+}
+
+// passkeyUserHandle is the WebAuthn user.id of an account, in its base64url JSON form.
+func passkeyUserHandle(userID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(userID, 10)))
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+)
+
+// ChangePassword replaces the password of a signed-in user who proves they know the
+// current one. Every token issued before the change stops working, the caller's and the
+// user's personal access tokens included.
+// Wrong current passwords count towards the account lockout like failed logins.
+func (u *userUseCase) ChangePassword(ctx context.Context, in dto.ChangePasswordInput) error {
+	if in.UserID <= 0 || in.CurrentPassword == "" || in.NewPassword == "" {
+		return ErrInvalidInput
+	}
+	user, err := u.repo.GetByID(ctx, in.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if u.isLocked(user) {
+		return ErrAccountLocked
+	}
+	ok, err := u.checkPassword(user, in.CurrentPassword)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return u.failLogin(ctx, user, ErrInvalidCredentials)
+	}
+	if err := u.passwordPolicy.Check(in.NewPassword, user.Email, user.Name); err != nil {
+		return err
+	}
+	hash, err := u.hasher.Hash(normalizePassword(in.NewPassword))
+	if err != nil {
+		return err
+	}
+	if err := u.repo.SetPassword(ctx, user.UserID, hash, time.Now().Unix()); err != nil {
+		return err
+	}
+	if err := u.endAllSessions(ctx, user.UserID); err != nil {
+		return err
+	}
+	return u.deletePersonalAccessTokens(ctx, user.UserID)
+}
+
+// endAllSessions removes the user's sessions after a password change. Their tokens are
+// already rejected through TokensValidAfter; this only keeps them off the session list.
+func (u *userUseCase) endAllSessions(ctx context.Context, userID int64) error {
+	if u.sessions == nil {
+		return nil
+	}
+	sessions, err := u.sessions.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, s := range sessions {
+		if err := u.sessions.Delete(ctx, s.SessionID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Authenticate verifies an access token or personal access token for a protected route.
+// Beyond the signature, expiry and revocation checks of the signer, user tokens must belong
+// to an existing user and be issued after the user's last password change.
+func (u *userUseCase) Authenticate(ctx context.Context, token string) (*domain.Principal, error) {
+	if token == "" {
+		return nil, ErrInvalidInput
+	}
+	if strings.HasPrefix(token, personalAccessTokenPrefix) {
+		return u.authenticatePersonalAccessToken(ctx, token)
+	}
+	principal, err := u.jwtSigner.VerifyPrincipal(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if principal.IsClient() {
+		return principal, nil
+	}
+	user, err := u.repo.GetByID(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.AcceptsTokenIssuedAt(principal.IssuedAt) {
+		return nil, ErrInvalidToken
+	}
+	return principal, nil
+}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+)
+
+var (
+	ErrPersonalAccessTokensDisabled = errors.New("personal access tokens are not enabled")
+	ErrPersonalAccessTokenNotFound  = errors.New("personal access token not found")
+	ErrUnknownScope                 = errors.New("unknown scope")
+	ErrTokenLifetimeTooLong         = errors.New("token lifetime exceeds the maximum allowed")
+	ErrPersonalAccessTokenLogout    = errors.New("personal access tokens cannot log out; revoke them instead")
+)
+
+const (
+	// personalAccessTokenPrefix tells personal access tokens apart from signed access tokens,
+	// and makes them easy to spot by secret scanners.
+	personalAccessTokenPrefix = "pat_"
+
+	maxPersonalAccessTokenName = 100
+	maxPersonalAccessTokenDays = 3650
+
+	// personalAccessTokenTouchInterval limits how often a token's last use is written back.
+	personalAccessTokenTouchInterval = 60
+)
+
+// CreatePersonalAccessToken issues a named token the user can send as a Bearer credential
+// in place of an access token. Only its hash is stored, so the returned token cannot be
+// retrieved again. Without scopes the token may both read and write.
+func (u *userUseCase) CreatePersonalAccessToken(ctx context.Context, in dto.CreatePersonalAccessTokenInput) (*dto.CreatePersonalAccessTokenOutput, error) {
+	name := strings.TrimSpace(in.Name)
+	if in.UserID <= 0 || name == "" || utf8.RuneCountInString(name) > maxPersonalAccessTokenName ||
+		in.ExpiresInDays < 0 || in.ExpiresInDays > maxPersonalAccessTokenDays {
+		return nil, ErrInvalidInput
+	}
+	if u.personalAccessTokens == nil {
+		return nil, ErrPersonalAccessTokensDisabled
+	}
+	scopes, err := personalAccessTokenScopes(in.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var expiresAt int64
+	if in.ExpiresInDays > 0 {
+		expiresAt = now.AddDate(0, 0, in.ExpiresInDays).Unix()
+	}
+	if u.personalAccessTokenMaxTTL > 0 && (expiresAt == 0 || expiresAt > now.Add(u.personalAccessTokenMaxTTL).Unix()) {
+		return nil, ErrTokenLifetimeTooLong
+	}
+
+	secret, _, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	id, err := newRandomID()
+	if err != nil {
+		return nil, err
+	}
+	token := personalAccessTokenPrefix + secret
+	t := &domain.PersonalAccessToken{
+		TokenHash: hashOpaqueToken(token),
+		TokenID:   id,
+		UserID:    in.UserID,
+		Name:      name,
+		Scopes:    scopes,
+		CreatedAt: now.Unix(),
+		ExpiresAt: expiresAt,
+	}
+	if err := u.personalAccessTokens.Create(ctx, t); err != nil {
+		return nil, err
+	}
+	return &dto.CreatePersonalAccessTokenOutput{PersonalAccessTokenOutput: personalAccessTokenOutput(t), Token: token}, nil
+}
+
+// ListPersonalAccessTokens returns the user's live tokens, newest first.
+func (u *userUseCase) ListPersonalAccessTokens(ctx context.Context, userID int64) (*dto.ListPersonalAccessTokensOutput, error) {
+	if userID <= 0 {
+		return nil, ErrInvalidUserID
+	}
+	if u.personalAccessTokens == nil {
+		return nil, ErrPersonalAccessTokensDisabled
+	}
+	tokens, err := u.personalAccessTokens.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	out := &dto.ListPersonalAccessTokensOutput{Tokens: []dto.PersonalAccessTokenOutput{}}
+	for _, t := range tokens {
+		if t.Expired(now) {
+			continue // expired but not purged by the TTL yet
+		}
+		out.Tokens = append(out.Tokens, personalAccessTokenOutput(t))
+	}
+	sort.SliceStable(out.Tokens, func(i, j int) bool {
+		return out.Tokens[i].CreatedAt > out.Tokens[j].CreatedAt
+	})
+	return out, nil
+}
+
+// RevokePersonalAccessToken deletes one of the user's tokens; it stops working at once.
+// Tokens of other users are reported as not found.
+func (u *userUseCase) RevokePersonalAccessToken(ctx context.Context, in dto.RevokePersonalAccessTokenInput) error {
+	if in.UserID <= 0 || in.TokenID == "" {
+		return ErrInvalidInput
+	}
+	if u.personalAccessTokens == nil {
+		return ErrPersonalAccessTokensDisabled
+	}
+	tokens, err := u.personalAccessTokens.ListByUser(ctx, in.UserID)
+	if err != nil {
+		return err
+	}
+	for _, t := range tokens {
+		if t.TokenID == in.TokenID {
+			return u.personalAccessTokens.Delete(ctx, t.TokenHash)
+		}
+	}
+	return ErrPersonalAccessTokenNotFound
+}
+
+// authenticatePersonalAccessToken is Authenticate for personal access tokens. Like access
+// tokens they stop working when the password changes.
+func (u *userUseCase) authenticatePersonalAccessToken(ctx context.Context, token string) (*domain.Principal, error) {
+	if u.personalAccessTokens == nil {
+		return nil, ErrInvalidToken
+	}
+	t, err := u.personalAccessTokens.GetByHash(ctx, hashOpaqueToken(token))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	if t == nil || t.Expired(now) {
+		return nil, ErrInvalidToken
+	}
+	user, err := u.repo.GetByID(ctx, t.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.AcceptsTokenIssuedAt(t.CreatedAt) {
+		return nil, ErrInvalidToken
+	}
+	if now-t.LastUsedAt >= personalAccessTokenTouchInterval {
+		// best effort: a failure only leaves the last-used time behind
+		_ = u.personalAccessTokens.Touch(ctx, t.TokenHash, now)
+	}
+	principal := principalFor(user)
+	principal.SubjectType = domain.SubjectTypeUser
+	principal.Scopes = t.Scopes
+	principal.TokenID = t.TokenID
+	principal.IssuedAt = t.CreatedAt
+	principal.ExpiresAt = t.ExpiresAt
+	principal.PersonalAccessToken = true
+	return &principal, nil
+}
+
+// deletePersonalAccessTokens removes the user's tokens after a password change. They are
+// already rejected through TokensValidAfter; this only keeps them off the token list.
+func (u *userUseCase) deletePersonalAccessTokens(ctx context.Context, userID int64) error {
+	if u.personalAccessTokens == nil {
+		return nil
+	}
+	tokens, err := u.personalAccessTokens.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, t := range tokens {
+		if err := u.personalAccessTokens.Delete(ctx, t.TokenHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// personalAccessTokenScopes validates requested scopes and returns them in a canonical
+// order; none means all of them.
+func personalAccessTokenScopes(requested []string) ([]string, error) {
+	for _, s := range requested {
+		if !slices.Contains(domain.PersonalAccessTokenScopes, s) {
+			return nil, ErrUnknownScope
+		}
+	}
+	var scopes []string
+	for _, s := range domain.PersonalAccessTokenScopes {
+		if len(requested) == 0 || slices.Contains(requested, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes, nil
+}
+
+func personalAccessTokenOutput(t *domain.PersonalAccessToken) dto.PersonalAccessTokenOutput {
+	return dto.PersonalAccessTokenOutput{
+		TokenID:    t.TokenID,
+		Name:       t.Name,
+		Scopes:     t.Scopes,
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+	}
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+)
+
+var (
+	ErrSessionsDisabled = errors.New("sessions are not enabled")
+	ErrSessionNotFound  = errors.New("session not found")
+)
+
+// startSession issues the tokens of a completed login. With sessions enabled the login is
+// recorded first, under the ID its refresh token family and sid claim will share.
+func (u *userUseCase) startSession(ctx context.Context, user *domain.User, client dto.ClientInfo) (*dto.LoginOutput, error) {
+	sessionID, err := newRandomID()
+	if err != nil {
+		return nil, err
+	}
+	if u.sessions != nil {
+		now := time.Now()
+		s := &domain.Session{
+			SessionID:  sessionID,
+			UserID:     user.UserID,
+			Device:     describeDevice(client.UserAgent),
+			UserAgent:  client.UserAgent,
+			IPAddress:  client.IPAddress,
+			CreatedAt:  now.Unix(),
+			LastSeenAt: now.Unix(),
+			ExpiresAt:  now.Add(u.refreshTTL).Unix(),
+		}
+		if err := u.sessions.Create(ctx, s); err != nil {
+			return nil, err
+		}
+	}
+	return u.issueTokens(ctx, user, sessionID)
+}
+
+// touchSession records a token refresh on the session. It is best effort: a failure only
+// leaves the last-seen time behind.
+func (u *userUseCase) touchSession(ctx context.Context, sessionID string) {
+	if u.sessions == nil {
+		return
+	}
+	now := time.Now()
+	_ = u.sessions.Touch(ctx, sessionID, now.Unix(), now.Add(u.refreshTTL).Unix())
+}
+
+// ListSessions returns the user's live sessions, most recently active first.
+func (u *userUseCase) ListSessions(ctx context.Context, in dto.ListSessionsInput) (*dto.ListSessionsOutput, error) {
+	if in.UserID <= 0 {
+		return nil, ErrInvalidUserID
+	}
+	if u.sessions == nil {
+		return nil, ErrSessionsDisabled
+	}
+	sessions, err := u.sessions.ListByUser(ctx, in.UserID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	out := &dto.ListSessionsOutput{Sessions: []dto.SessionOutput{}}
+	for _, s := range sessions {
+		if s.ExpiresAt <= now {
+			continue // expired but not purged by the TTL yet
+		}
+		out.Sessions = append(out.Sessions, dto.SessionOutput{
+			SessionID:  s.SessionID,
+			Device:     s.Device,
+			UserAgent:  s.UserAgent,
+			IPAddress:  s.IPAddress,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+			Current:    s.SessionID == in.CurrentSessionID,
+		})
+	}
+	sort.SliceStable(out.Sessions, func(i, j int) bool {
+		return out.Sessions[i].LastSeenAt > out.Sessions[j].LastSeenAt
+	})
+	return out, nil
+}
+
+// RevokeSession signs the user out of one of their sessions: its refresh tokens stop
+// working and its access tokens are rejected from then on. Sessions of other users are
+// reported as not found.
+func (u *userUseCase) RevokeSession(ctx context.Context, in dto.RevokeSessionInput) error {
+	if in.UserID <= 0 || in.SessionID == "" {
+		return ErrInvalidInput
+	}
+	if u.sessions == nil {
+		return ErrSessionsDisabled
+	}
+	s, err := u.sessions.GetByID(ctx, in.SessionID)
+	if err != nil {
+		return err
+	}
+	if s == nil || s.UserID != in.UserID {
+		return ErrSessionNotFound
+	}
+	return u.endSession(ctx, s.SessionID)
+}
+
+func (u *userUseCase) endSession(ctx context.Context, sessionID string) error {
+	if u.refreshTokens != nil {
+		if err := u.refreshTokens.RevokeFamily(ctx, sessionID); err != nil {
+			return err
+		}
+	}
+	if err := u.jwtSigner.RevokeSession(ctx, sessionID); err != nil {
+		return err
+	}
+	return u.sessions.Delete(ctx, sessionID)
+}
+
+// describeDevice turns a user agent into a short label such as "Firefox on Windows". It
+// only knows the common browsers and platforms; anything else is "Unknown device".
+func describeDevice(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	browser := firstMatch(ua, []deviceMatch{
+		{"edg/", "Edge"},
+		{"opr/", "Opera"},
+		{"firefox/", "Firefox"},
+		{"chrome/", "Chrome"},
+		{"crios/", "Chrome"},
+		{"safari/", "Safari"},
+		{"curl/", "curl"},
+	})
+	platform := firstMatch(ua, []deviceMatch{
+		{"iphone", "iOS"},
+		{"ipad", "iPadOS"},
+		{"android", "Android"},
+		{"windows", "Windows"},
+		{"mac os x", "macOS"},
+		{"cros", "ChromeOS"},
+		{"linux", "Linux"},
+	})
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	default:
+		return "Unknown device"
+	}
+}
+
+type deviceMatch struct {
+	token string
+	label string
+}
+
+// firstMatch returns the label of the first token found in ua; order matters because
+// user agents name the engines they are compatible with as well as their own.
+func firstMatch(ua string, matches []deviceMatch) string {
+	for _, m := range matches {
+		if strings.Contains(ua, m.token) {
+			return m.label
+		}
+	}
+	return ""
+}
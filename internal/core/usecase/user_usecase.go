@@ -3,43 +3,108 @@ package usecase
 import (
 	"context"
 	"errors"
+	"strings"
+	"sync"
 	"time"
 
-	"golang.org/x/crypto/bcrypt"
-
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
 )
 
 var (
-	ErrInvalidInput       = errors.New("invalid input")
-	ErrEmailAlreadyExists = errors.New("email already registered")
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrInvalidUserID      = errors.New("invalid user id")
-	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidInput             = errors.New("invalid input")
+	ErrEmailAlreadyExists       = errors.New("email already registered")
+	ErrInvalidCredentials       = errors.New("invalid credentials")
+	ErrInvalidUserID            = errors.New("invalid user id")
+	ErrUserNotFound             = errors.New("user not found")
+	ErrInvalidRefreshToken      = errors.New("invalid refresh token")
+	ErrRefreshTokenReused       = errors.New("refresh token reuse detected")
+	ErrInvalidToken             = errors.New("invalid token")
+	ErrInvalidResetToken        = errors.New("invalid or expired reset token")
+	ErrPasswordResetDisabled    = errors.New("password reset is not enabled")
+	ErrEmailNotVerified         = errors.New("email not verified")
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
 )
 
 type userUseCase struct {
 	repo      port.UserRepository
+	hasher    port.PasswordHasher
 	jwtSigner port.JWTSigner
+
+	passwordPolicy PasswordPolicy
+
+	dummyHashOnce   sync.Once
+	dummyHash       string // hash of a random password, for constant-time logins of unknown emails
+	enumerationSafe bool   // Register does not reveal whether an email is taken
+
+	refreshTokens port.RefreshTokenRepository
+	refreshTTL    time.Duration
+
+	sessions port.SessionRepository // nil disables session tracking
+
+	notifier      port.Notifier
+	oneTimeTokens port.OneTimeTokenRepository
+	resetTTL      time.Duration
+
+	verifyEmail     bool
+	verifyTTL       time.Duration
+	requireVerified bool
+
+	emailChangeTTL time.Duration // 0 disables email changes
+	magicLinkTTL   time.Duration // 0 disables magic-link login
+	magicLinkURL   string        // page that consumes magic-link tokens
+
+	cipher          port.SecretCipher // nil disables TOTP MFA
+	mfaIssuer       string
+	mfaChallengeTTL time.Duration
+
+	webAuthn   port.WebAuthn // nil disables passkeys
+	passkeys   port.PasskeyCredentialRepository
+	rpID       string
+	rpName     string
+	passkeyTTL time.Duration
+
+	identityProviders   map[string]port.IdentityProvider // nil disables federated login
+	federatedIdentities port.FederatedIdentityRepository
+	federatedLoginTTL   time.Duration
+
+	personalAccessTokens      port.PersonalAccessTokenRepository // nil disables personal access tokens
+	personalAccessTokenMaxTTL time.Duration                      // 0 allows tokens that never expire
+
+	auditLog         port.AuditLog
+	impersonationTTL time.Duration // 0 disables impersonation
+
+	lockoutThreshold int // failed logins before the account locks; 0 disables lockout
+	lockoutBase      time.Duration
+	lockoutMax       time.Duration
 }
 
-func NewUserUseCase(repo port.UserRepository, jwtSigner port.JWTSigner) port.UserUseCase {
-	return &userUseCase{repo: repo, jwtSigner: jwtSigner}
+func NewUserUseCase(repo port.UserRepository, hasher port.PasswordHasher, jwtSigner port.JWTSigner, opts ...Option) port.UserUseCase {
+	u := &userUseCase{repo: repo, hasher: hasher, jwtSigner: jwtSigner}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
 }
 
 func (u *userUseCase) Register(ctx context.Context, in dto.RegisterInput) (*dto.RegisterOutput, error) {
 	if in.Name == "" || in.Email == "" || in.Password == "" {
 		return nil, ErrInvalidInput
 	}
+	if err := u.passwordPolicy.Check(in.Password, in.Email, in.Name); err != nil {
+		return nil, err
+	}
 
 	// check if email already exists
 	if existing, _ := u.repo.GetByEmail(ctx, in.Email); existing != nil {
+		if u.enumerationSafe {
+			return u.registerExisting(ctx, existing, in)
+		}
 		return nil, ErrEmailAlreadyExists
 	}
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(in.Password), bcrypt.DefaultCost)
+	hash, err := u.hasher.Hash(normalizePassword(in.Password))
 	if err != nil {
 		return nil, err
 	}
@@ -47,17 +112,26 @@ func (u *userUseCase) Register(ctx context.Context, in dto.RegisterInput) (*dto.
 	now := time.Now().Unix()
 	user := &domain.User{
 		// UserID will be assigned by repository (sequential)
-		Name:      in.Name,
-		Email:     in.Email,
-		Password:  string(hash),
-		CreatedAt: now,
-		UpdatedAt: now,
+		Name:          in.Name,
+		Email:         in.Email,
+		Password:      hash,
+		EmailVerified: !u.verifyEmail,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
 	if err := u.repo.Create(ctx, user); err != nil {
 		return nil, err
 	}
+	if u.verifyEmail {
+		// the account exists at this point; if delivery fails the user can ask for a new link
+		_ = u.sendOneTimeToken(ctx, user, domain.TokenPurposeEmailVerification, domain.NotificationEmailVerification, u.verifyTTL)
+	}
 
-	return &dto.RegisterOutput{UserID: user.UserID, Name: user.Name, Email: user.Email}, nil
+	out := &dto.RegisterOutput{UserID: user.UserID, Name: user.Name, Email: user.Email}
+	if u.enumerationSafe {
+		out.UserID = 0 // an existing account's answer has none to give
+	}
+	return out, nil
 }
 
 func (u *userUseCase) Login(ctx context.Context, in dto.LoginInput) (*dto.LoginOutput, error) {
@@ -66,16 +140,302 @@ func (u *userUseCase) Login(ctx context.Context, in dto.LoginInput) (*dto.LoginO
 	}
 	user, err := u.repo.GetByEmail(ctx, in.Email)
 	if err != nil || user == nil {
+		u.burnPasswordCheck(in.Password)
 		return nil, ErrInvalidCredentials
 	}
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(in.Password)); err != nil {
+	// The lock is only revealed to callers who know the password: a fast or distinct answer
+	// for a wrong one would tell that the email has an account.
+	locked := u.isLocked(user)
+	ok, err := u.checkPassword(user, in.Password)
+	if err != nil {
+		return nil, err
+	}
+	if locked {
+		if !ok {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, ErrAccountLocked
+	}
+	if !ok {
+		if err := u.failLogin(ctx, user, ErrInvalidCredentials); !errors.Is(err, ErrAccountLocked) {
+			return nil, err
+		}
 		return nil, ErrInvalidCredentials
 	}
-	token, err := u.jwtSigner.Sign(user.UserID)
+	u.rehashPassword(ctx, user, in.Password)
+	if u.requireVerified && !user.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+	if user.MFAEnabled {
+		return u.mfaChallenge(ctx, user)
+	}
+	if err := u.clearLoginFailures(ctx, user); err != nil {
+		return nil, err
+	}
+	return u.startSession(ctx, user, in.Client)
+}
+
+// Refresh rotates a refresh token: the presented token is marked as used and a new
+// access/refresh pair from the same family is returned. Presenting a token that was
+// already rotated revokes the whole family, logging out every holder of it.
+func (u *userUseCase) Refresh(ctx context.Context, in dto.RefreshInput) (*dto.LoginOutput, error) {
+	if in.RefreshToken == "" {
+		return nil, ErrInvalidInput
+	}
+	if u.refreshTokens == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	hash := hashOpaqueToken(in.RefreshToken)
+	rt, err := u.refreshTokens.GetByHash(ctx, hash)
+	if err != nil || rt == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	now := time.Now().Unix()
+	if rt.Revoked || rt.ExpiresAt <= now {
+		return nil, ErrInvalidRefreshToken
+	}
+	if rt.UsedAt != 0 {
+		return nil, u.revokeFamily(ctx, rt.FamilyID)
+	}
+	swapped, err := u.refreshTokens.MarkUsed(ctx, hash, now)
+	if err != nil {
+		return nil, err
+	}
+	if !swapped {
+		// lost a race against another request presenting the same token
+		return nil, u.revokeFamily(ctx, rt.FamilyID)
+	}
+	user, err := u.repo.GetByID(ctx, rt.UserID)
+	if err != nil || user == nil || !user.AcceptsTokenIssuedAt(rt.CreatedAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+	u.touchSession(ctx, rt.FamilyID)
+	return u.issueTokens(ctx, user, rt.FamilyID)
+}
+
+// Logout revokes the presented access token and, when given, the refresh token family it
+// was issued with, so neither can be used again. With sessions enabled the refresh token's
+// session is ended as well. Personal access tokens are refused; they are revoked by ID.
+func (u *userUseCase) Logout(ctx context.Context, in dto.LogoutInput) error {
+	if in.AccessToken == "" {
+		return ErrInvalidInput
+	}
+	if strings.HasPrefix(in.AccessToken, personalAccessTokenPrefix) {
+		return ErrPersonalAccessTokenLogout
+	}
+	userID, actorUserID, err := u.jwtSigner.Verify(ctx, in.AccessToken)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	if err := u.jwtSigner.Revoke(ctx, in.AccessToken); err != nil {
+		return err
+	}
+	// an admin impersonating the user only ends their own access, never the user's session
+	if in.RefreshToken == "" || u.refreshTokens == nil || actorUserID != 0 {
+		return nil
+	}
+	rt, err := u.refreshTokens.GetByHash(ctx, hashOpaqueToken(in.RefreshToken))
+	if err != nil {
+		return err
+	}
+	if rt == nil || rt.UserID != userID {
+		return nil
+	}
+	if u.sessions != nil {
+		return u.endSession(ctx, rt.FamilyID)
+	}
+	return u.refreshTokens.RevokeFamily(ctx, rt.FamilyID)
+}
+
+// ForgotPassword sends a single-use reset token to the account's email address. It
+// succeeds whether or not the email is registered, so it cannot be used to probe accounts.
+func (u *userUseCase) ForgotPassword(ctx context.Context, in dto.ForgotPasswordInput) error {
+	if in.Email == "" {
+		return ErrInvalidInput
+	}
+	if u.oneTimeTokens == nil || u.notifier == nil {
+		return ErrPasswordResetDisabled
+	}
+	user, err := u.repo.GetByEmail(ctx, in.Email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+	return u.sendOneTimeToken(ctx, user, domain.TokenPurposePasswordReset, domain.NotificationPasswordReset, u.resetTTL)
+}
+
+// ResetPassword consumes a reset token and replaces the user's password. Like a password
+// change, it invalidates every token issued before it.
+func (u *userUseCase) ResetPassword(ctx context.Context, in dto.ResetPasswordInput) error {
+	if in.Token == "" || in.NewPassword == "" {
+		return ErrInvalidInput
+	}
+	if u.oneTimeTokens == nil {
+		return ErrPasswordResetDisabled
+	}
+	// rules that do not depend on the account are checked before the token is spent
+	if err := u.passwordPolicy.Check(in.NewPassword, "", ""); err != nil {
+		return err
+	}
+	now := time.Now().Unix()
+	ott, err := u.oneTimeTokens.Consume(ctx, hashOpaqueToken(in.Token), domain.TokenPurposePasswordReset, now)
+	if err != nil {
+		return err
+	}
+	if ott == nil {
+		return ErrInvalidResetToken
+	}
+	user, err := u.repo.GetByID(ctx, ott.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrInvalidResetToken
+	}
+	if err := u.passwordPolicy.Check(in.NewPassword, user.Email, user.Name); err != nil {
+		return err
+	}
+	hash, err := u.hasher.Hash(normalizePassword(in.NewPassword))
+	if err != nil {
+		return err
+	}
+	// proving control of the email lifts a lockout
+	if err := u.repo.SetPassword(ctx, user.UserID, hash, now); err != nil {
+		return err
+	}
+	if err := u.endAllSessions(ctx, user.UserID); err != nil {
+		return err
+	}
+	return u.deletePersonalAccessTokens(ctx, user.UserID)
+}
+
+// VerifyEmail consumes a verification token and marks the account's email as verified.
+func (u *userUseCase) VerifyEmail(ctx context.Context, in dto.VerifyEmailInput) error {
+	if in.Token == "" {
+		return ErrInvalidInput
+	}
+	if !u.verifyEmail {
+		return ErrInvalidVerificationToken
+	}
+	now := time.Now().Unix()
+	ott, err := u.oneTimeTokens.Consume(ctx, hashOpaqueToken(in.Token), domain.TokenPurposeEmailVerification, now)
+	if err != nil {
+		return err
+	}
+	if ott == nil {
+		return ErrInvalidVerificationToken
+	}
+	user, err := u.repo.GetByID(ctx, ott.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrInvalidVerificationToken
+	}
+	if user.EmailVerified {
+		return nil
+	}
+	return u.repo.MarkEmailVerified(ctx, user.UserID, now)
+}
+
+// ResendVerification sends a new verification token to an unverified account. Like
+// ForgotPassword it reports success for unknown or already verified emails.
+func (u *userUseCase) ResendVerification(ctx context.Context, in dto.ResendVerificationInput) error {
+	if in.Email == "" {
+		return ErrInvalidInput
+	}
+	if !u.verifyEmail {
+		return nil
+	}
+	user, err := u.repo.GetByEmail(ctx, in.Email)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.EmailVerified {
+		return nil
+	}
+	return u.sendOneTimeToken(ctx, user, domain.TokenPurposeEmailVerification, domain.NotificationEmailVerification, u.verifyTTL)
+}
+
+// sendOneTimeToken stores the hash of a new one-time token for the user and delivers the
+// token itself through the notifier.
+func (u *userUseCase) sendOneTimeToken(ctx context.Context, user *domain.User, purpose, notification string, ttl time.Duration) error {
+	token, ott, err := u.createOneTimeToken(ctx, user.UserID, purpose, ttl)
+	if err != nil {
+		return err
+	}
+	return u.notifier.Notify(ctx, domain.Notification{
+		Type:      notification,
+		UserID:    user.UserID,
+		To:        user.Email,
+		Name:      user.Name,
+		Token:     token,
+		ExpiresAt: ott.ExpiresAt,
+	})
+}
+
+// createOneTimeToken persists the hash of a new one-time token and returns the token itself.
+func (u *userUseCase) createOneTimeToken(ctx context.Context, userID int64, purpose string, ttl time.Duration) (string, *domain.OneTimeToken, error) {
+	token, hash, err := newOpaqueToken()
+	if err != nil {
+		return "", nil, err
+	}
+	now := time.Now()
+	ott := &domain.OneTimeToken{
+		TokenHash: hash,
+		Purpose:   purpose,
+		UserID:    userID,
+		CreatedAt: now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+	if err := u.oneTimeTokens.Create(ctx, ott); err != nil {
+		return "", nil, err
+	}
+	return token, ott, nil
+}
+
+func (u *userUseCase) revokeFamily(ctx context.Context, familyID string) error {
+	if err := u.refreshTokens.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// issueTokens signs an access token for the user and, when refresh tokens are enabled,
+// persists a new refresh token in the given family. With sessions enabled the family ID
+// is the session ID and goes into the access token as well.
+func (u *userUseCase) issueTokens(ctx context.Context, user *domain.User, familyID string) (*dto.LoginOutput, error) {
+	principal := principalFor(user)
+	if u.sessions != nil {
+		principal.SessionID = familyID
+	}
+	token, err := u.jwtSigner.Sign(principal)
+	if err != nil {
+		return nil, err
+	}
+	out := &dto.LoginOutput{Token: token}
+	if u.refreshTokens == nil {
+		return out, nil
+	}
+	refresh, hash, err := newOpaqueToken()
 	if err != nil {
 		return nil, err
 	}
-	return &dto.LoginOutput{Token: token}, nil
+	now := time.Now()
+	rt := &domain.RefreshToken{
+		TokenHash: hash,
+		FamilyID:  familyID,
+		UserID:    user.UserID,
+		CreatedAt: now.Unix(),
+		ExpiresAt: now.Add(u.refreshTTL).Unix(),
+	}
+	if err := u.refreshTokens.Create(ctx, rt); err != nil {
+		return nil, err
+	}
+	out.RefreshToken = refresh
+	return out, nil
 }
 
 func (u *userUseCase) GetMe(ctx context.Context, userID int64) (*dto.GetMeOutput, error) {
@@ -86,7 +446,13 @@ func (u *userUseCase) GetMe(ctx context.Context, userID int64) (*dto.GetMeOutput
 	if err != nil || user == nil {
 		return nil, ErrUserNotFound
 	}
-	return &dto.GetMeOutput{UserID: user.UserID, Name: user.Name, Email: user.Email}, nil
+	return &dto.GetMeOutput{
+		UserID:        user.UserID,
+		Name:          user.Name,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		PendingEmail:  user.PendingEmail,
+	}, nil
 }
 
 func (u *userUseCase) GetUserByID(ctx context.Context, userID int64) (*dto.GetUserByIDOutput, error) {
@@ -99,3 +465,41 @@ func (u *userUseCase) GetUserByID(ctx context.Context, userID int64) (*dto.GetUs
 	}
 	return &dto.GetUserByIDOutput{UserID: user.UserID, Name: user.Name, Email: user.Email}, nil
 }
+
+// checkPassword compares a password with the user's stored hash. Passwords are hashed in
+// their normalized form; the raw form is tried as well for hashes stored before normalization.
+func (u *userUseCase) checkPassword(user *domain.User, password string) (bool, error) {
+	if user.Password == "" {
+		// accounts created through federated sign-in have none until they reset it
+		u.burnPasswordCheck(password)
+		return false, nil
+	}
+	normalized := normalizePassword(password)
+	ok, err := u.hasher.Verify(user.Password, normalized)
+	if err != nil || ok || normalized == password {
+		return ok, err
+	}
+	return u.hasher.Verify(user.Password, password)
+}
+
+// rehashPassword replaces a hash made with an outdated algorithm or parameters once the
+// password is known to be right. It is best effort: the login goes ahead if it fails.
+func (u *userUseCase) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	if !u.hasher.NeedsRehash(user.Password) {
+		return
+	}
+	hash, err := u.hasher.Hash(normalizePassword(password))
+	if err != nil {
+		return
+	}
+	now := time.Now().Unix()
+	if err := u.repo.ReplacePasswordHash(ctx, user.UserID, user.Password, hash, now); err != nil {
+		return
+	}
+	user.Password = hash
+	user.UpdatedAt = now
+}
+
+func principalFor(user *domain.User) domain.Principal {
+	return domain.Principal{UserID: user.UserID, Email: user.Email, Roles: user.Roles}
+}
@@ -17,7 +17,19 @@ type UserUsecaseSuiteTest struct {
 	suite.Suite
 	mockUsers     []*domain.User
 	mockRepo      *mockport.MockUserRepository
+	mockHasher    *mockport.MockPasswordHasher
 	mockJWTSigner *mockport.MockJWTSigner
+	mockRefresh   *mockport.MockRefreshTokenRepository
+	mockNotifier  *mockport.MockNotifier
+	mockOneTime   *mockport.MockOneTimeTokenRepository
+	mockCipher    *mockport.MockSecretCipher
+	mockWebAuthn  *mockport.MockWebAuthn
+	mockPasskeys  *mockport.MockPasskeyCredentialRepository
+	mockSessions  *mockport.MockSessionRepository
+	mockIdP       *mockport.MockIdentityProvider
+	mockFederated *mockport.MockFederatedIdentityRepository
+	mockPATs      *mockport.MockPersonalAccessTokenRepository
+	mockAudit     *mockport.MockAuditLog
 	useCase       port.UserUseCase
 	ctx           context.Context
 	ctrl          *gomock.Controller
@@ -26,8 +38,24 @@ type UserUsecaseSuiteTest struct {
 func (s *UserUsecaseSuiteTest) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.mockRepo = mockport.NewMockUserRepository(s.ctrl)
+	s.mockHasher = mockport.NewMockPasswordHasher(s.ctrl)
 	s.mockJWTSigner = mockport.NewMockJWTSigner(s.ctrl)
-	s.useCase = usecase.NewUserUseCase(s.mockRepo, s.mockJWTSigner)
+	s.mockRefresh = mockport.NewMockRefreshTokenRepository(s.ctrl)
+	s.mockNotifier = mockport.NewMockNotifier(s.ctrl)
+	s.mockOneTime = mockport.NewMockOneTimeTokenRepository(s.ctrl)
+	s.mockCipher = mockport.NewMockSecretCipher(s.ctrl)
+	s.mockWebAuthn = mockport.NewMockWebAuthn(s.ctrl)
+	s.mockPasskeys = mockport.NewMockPasskeyCredentialRepository(s.ctrl)
+	s.mockSessions = mockport.NewMockSessionRepository(s.ctrl)
+	s.mockIdP = mockport.NewMockIdentityProvider(s.ctrl)
+	s.mockFederated = mockport.NewMockFederatedIdentityRepository(s.ctrl)
+	s.mockPATs = mockport.NewMockPersonalAccessTokenRepository(s.ctrl)
+	s.mockAudit = mockport.NewMockAuditLog(s.ctrl)
+	s.useCase = usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+		usecase.WithRefreshTokens(s.mockRefresh, 24*time.Hour),
+		usecase.WithNotifier(s.mockNotifier),
+		usecase.WithPasswordReset(s.mockOneTime, time.Hour),
+	)
 	s.ctx = context.Background()
 	currentTime := time.Now().Unix()
 	s.mockUsers = []*domain.User{
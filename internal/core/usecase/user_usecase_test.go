@@ -1,18 +1,35 @@
 package usecase_test
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/usecase"
 )
 
 const testHashedPassword = "$2a$12$5CEGdJIUSFrHCyrSOPVEE.mdHjVucN38e2xRzCb8zM1XAB7ZfqdTS" // bcrypt hash for "password123"
 
+// expectPasswordCheck expects the login's password verification against hash, and no
+// rehash when the password matches.
+func (s *UserUsecaseSuiteTest) expectPasswordCheck(hash, password string, ok bool) {
+	s.mockHasher.EXPECT().Verify(hash, password).Return(ok, nil)
+	if ok {
+		s.mockHasher.EXPECT().NeedsRehash(hash).Return(false)
+	}
+}
+
 func (s *UserUsecaseSuiteTest) TestUserUseCase_Register() {
 	tests := []struct {
 		name        string
@@ -32,10 +49,12 @@ func (s *UserUsecaseSuiteTest) TestUserUseCase_Register() {
 				s.mockRepo.EXPECT().
 					GetByEmail(s.ctx, "john@example.com").
 					Return(nil, nil)
+				s.mockHasher.EXPECT().Hash("password123").Return("hashed-password", nil)
 				// Create user
 				s.mockRepo.EXPECT().
 					Create(s.ctx, gomock.Any()).
 					DoAndReturn(func(ctx interface{}, user *domain.User) error {
+						assert.Equal(s.T(), "hashed-password", user.Password)
 						user.UserID = 1 // Simulate repository assigning ID
 						return nil
 					})
@@ -114,6 +133,24 @@ func (s *UserUsecaseSuiteTest) TestUserUseCase_Register() {
 				assert.Equal(t, usecase.ErrInvalidInput, err)
 			},
 		},
+		{
+			name: "should return error when hashing fails",
+			input: dto.RegisterInput{
+				Name:     "John Doe",
+				Email:    "john@example.com",
+				Password: "password123",
+			},
+			setupMocks: func() {
+				s.mockRepo.EXPECT().
+					GetByEmail(s.ctx, "john@example.com").
+					Return(nil, nil)
+				s.mockHasher.EXPECT().Hash("password123").Return("", assert.AnError)
+			},
+			checkResult: func(t *testing.T, output *dto.RegisterOutput, err error) {
+				assert.Nil(t, output)
+				assert.Equal(t, assert.AnError, err)
+			},
+		},
 		{
 			name: "should return error when repository create fails",
 			input: dto.RegisterInput{
@@ -125,6 +162,7 @@ func (s *UserUsecaseSuiteTest) TestUserUseCase_Register() {
 				s.mockRepo.EXPECT().
 					GetByEmail(s.ctx, "john@example.com").
 					Return(nil, nil)
+				s.mockHasher.EXPECT().Hash("password123").Return("hashed-password", nil)
 				s.mockRepo.EXPECT().
 					Create(s.ctx, gomock.Any()).
 					Return(assert.AnError)
@@ -175,14 +213,54 @@ func (s *UserUsecaseSuiteTest) TestUserUseCase_Login() {
 				s.mockRepo.EXPECT().
 					GetByEmail(s.ctx, "john@example.com").
 					Return(user, nil)
+				s.expectPasswordCheck(testHashedPassword, "password123", true)
 				s.mockJWTSigner.EXPECT().
-					Sign(int64(1)).
+					Sign(domain.Principal{UserID: 1, Email: "john@example.com"}).
 					Return("jwt-token", nil)
+				s.mockRefresh.EXPECT().
+					Create(s.ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, rt *domain.RefreshToken) error {
+						assert.Equal(s.T(), int64(1), rt.UserID)
+						assert.NotEmpty(s.T(), rt.FamilyID)
+						assert.NotEmpty(s.T(), rt.TokenHash)
+						assert.Greater(s.T(), rt.ExpiresAt, time.Now().Unix())
+						return nil
+					})
 			},
 			checkResult: func(t *testing.T, output *dto.LoginOutput, err error) {
 				assert.NoError(t, err)
 				assert.NotNil(t, output)
 				assert.Equal(t, "jwt-token", output.Token)
+				assert.NotEmpty(t, output.RefreshToken)
+			},
+		},
+		{
+			name: "should return error when refresh token cannot be stored",
+			input: dto.LoginInput{
+				Email:    "john@example.com",
+				Password: "password123",
+			},
+			setupMocks: func() {
+				user := &domain.User{
+					UserID:   1,
+					Name:     "John Doe",
+					Email:    "john@example.com",
+					Password: testHashedPassword,
+				}
+				s.mockRepo.EXPECT().
+					GetByEmail(s.ctx, "john@example.com").
+					Return(user, nil)
+				s.expectPasswordCheck(testHashedPassword, "password123", true)
+				s.mockJWTSigner.EXPECT().
+					Sign(domain.Principal{UserID: 1, Email: "john@example.com"}).
+					Return("jwt-token", nil)
+				s.mockRefresh.EXPECT().
+					Create(s.ctx, gomock.Any()).
+					Return(assert.AnError)
+			},
+			checkResult: func(t *testing.T, output *dto.LoginOutput, err error) {
+				assert.Nil(t, output)
+				assert.Equal(t, assert.AnError, err)
 			},
 		},
 		{
@@ -225,6 +303,9 @@ func (s *UserUsecaseSuiteTest) TestUserUseCase_Login() {
 				s.mockRepo.EXPECT().
 					GetByEmail(s.ctx, "nonexistent@example.com").
 					Return(nil, nil)
+				// a wrong password is checked against a dummy hash to take as long as for a known email
+				s.mockHasher.EXPECT().Hash(gomock.Any()).Return("dummy-hash", nil)
+				s.mockHasher.EXPECT().Verify("dummy-hash", "password123").Return(false, nil)
 			},
 			checkResult: func(t *testing.T, output *dto.LoginOutput, err error) {
 				assert.Error(t, err)
@@ -248,6 +329,7 @@ func (s *UserUsecaseSuiteTest) TestUserUseCase_Login() {
 				s.mockRepo.EXPECT().
 					GetByEmail(s.ctx, "john@example.com").
 					Return(user, nil)
+				s.expectPasswordCheck(testHashedPassword, "wrongpassword", false)
 			},
 			checkResult: func(t *testing.T, output *dto.LoginOutput, err error) {
 				assert.Error(t, err)
@@ -271,8 +353,9 @@ func (s *UserUsecaseSuiteTest) TestUserUseCase_Login() {
 				s.mockRepo.EXPECT().
 					GetByEmail(s.ctx, "john@example.com").
 					Return(user, nil)
+				s.expectPasswordCheck(testHashedPassword, "password123", true)
 				s.mockJWTSigner.EXPECT().
-					Sign(int64(1)).
+					Sign(domain.Principal{UserID: 1, Email: "john@example.com"}).
 					Return("", assert.AnError)
 			},
 			checkResult: func(t *testing.T, output *dto.LoginOutput, err error) {
@@ -297,67 +380,249 @@ func (s *UserUsecaseSuiteTest) TestUserUseCase_Login() {
 	}
 }
 
-func (s *UserUsecaseSuiteTest) TestUserUseCase_GetMe() {
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Login_PasswordHashing() {
+	newUser := func() *domain.User {
+		return &domain.User{UserID: 1, Email: "john@example.com", Password: testHashedPassword}
+	}
+
 	tests := []struct {
 		name        string
-		userID      int64
+		password    string
 		setupMocks  func()
-		checkResult func(*testing.T, *dto.GetMeOutput, error)
+		expectError error
 	}{
 		{
-			name:   "should get user successfully",
-			userID: 1,
+			name:     "should upgrade an outdated hash",
+			password: "password123",
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "password123").Return(true, nil)
+				s.mockHasher.EXPECT().NeedsRehash(testHashedPassword).Return(true)
+				s.mockHasher.EXPECT().Hash("password123").Return("$argon2id$new", nil)
+				s.mockRepo.EXPECT().ReplacePasswordHash(s.ctx, int64(1), testHashedPassword, "$argon2id$new", gomock.Any()).Return(nil)
+				s.mockJWTSigner.EXPECT().Sign(gomock.Any()).Return("jwt-token", nil)
+				s.mockRefresh.EXPECT().Create(s.ctx, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:     "should log in even when the upgrade fails",
+			password: "password123",
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "password123").Return(true, nil)
+				s.mockHasher.EXPECT().NeedsRehash(testHashedPassword).Return(true)
+				s.mockHasher.EXPECT().Hash("password123").Return("$argon2id$new", nil)
+				s.mockRepo.EXPECT().ReplacePasswordHash(s.ctx, int64(1), testHashedPassword, "$argon2id$new", gomock.Any()).Return(assert.AnError)
+				s.mockJWTSigner.EXPECT().Sign(gomock.Any()).Return("jwt-token", nil)
+				s.mockRefresh.EXPECT().Create(s.ctx, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:     "should accept the raw form of a password hashed before normalization",
+			password: "ｐａｓｓ",
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "pass").Return(false, nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "ｐａｓｓ").Return(true, nil)
+				s.mockHasher.EXPECT().NeedsRehash(testHashedPassword).Return(false)
+				s.mockJWTSigner.EXPECT().Sign(gomock.Any()).Return("jwt-token", nil)
+				s.mockRefresh.EXPECT().Create(s.ctx, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:     "should fail on a hash the hasher cannot parse",
+			password: "password123",
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "password123").Return(false, assert.AnError)
+			},
+			expectError: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			out, err := s.useCase.Login(s.ctx, dto.LoginInput{Email: "john@example.com", Password: tt.password})
+
+			// Assert
+			if tt.expectError != nil {
+				assert.Equal(t, tt.expectError, err)
+				assert.Nil(t, out)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "jwt-token", out.Token)
+			}
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Login_UnknownEmail() {
+	s.T().Run("should hash the dummy password once and verify against it every time", func(t *testing.T) {
+		// Arrange
+		s.mockRepo.EXPECT().GetByEmail(s.ctx, "nobody@example.com").Return(nil, nil).Times(2)
+		s.mockHasher.EXPECT().Hash(gomock.Any()).Return("dummy-hash", nil)
+		s.mockHasher.EXPECT().Verify("dummy-hash", "guess-1").Return(false, nil)
+		s.mockHasher.EXPECT().Verify("dummy-hash", "guess-2").Return(false, nil)
+
+		// Act
+		_, err1 := s.useCase.Login(s.ctx, dto.LoginInput{Email: "nobody@example.com", Password: "guess-1"})
+		_, err2 := s.useCase.Login(s.ctx, dto.LoginInput{Email: "nobody@example.com", Password: "guess-2"})
+
+		// Assert
+		assert.Equal(t, usecase.ErrInvalidCredentials, err1)
+		assert.Equal(t, usecase.ErrInvalidCredentials, err2)
+	})
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Refresh() {
+	const refreshToken = "refresh-token"
+	sum := sha256.Sum256([]byte(refreshToken))
+	refreshHash := hex.EncodeToString(sum[:])
+	future := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name        string
+		input       dto.RefreshInput
+		setupMocks  func()
+		checkResult func(*testing.T, *dto.LoginOutput, error)
+	}{
+		{
+			name:  "should rotate refresh token successfully",
+			input: dto.RefreshInput{RefreshToken: refreshToken},
 			setupMocks: func() {
+				s.mockRefresh.EXPECT().
+					GetByHash(s.ctx, refreshHash).
+					Return(&domain.RefreshToken{TokenHash: refreshHash, FamilyID: "fam-1", UserID: 1, CreatedAt: 100, ExpiresAt: future}, nil)
+				s.mockRefresh.EXPECT().
+					MarkUsed(s.ctx, refreshHash, gomock.Any()).
+					Return(true, nil)
 				s.mockRepo.EXPECT().
 					GetByID(s.ctx, int64(1)).
 					Return(s.mockUsers[0], nil)
+				s.mockJWTSigner.EXPECT().
+					Sign(domain.Principal{UserID: 1, Email: "john@example.com"}).
+					Return("new-jwt-token", nil)
+				s.mockRefresh.EXPECT().
+					Create(s.ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, rt *domain.RefreshToken) error {
+						assert.Equal(s.T(), "fam-1", rt.FamilyID)
+						assert.NotEqual(s.T(), refreshHash, rt.TokenHash)
+						return nil
+					})
 			},
-			checkResult: func(t *testing.T, output *dto.GetMeOutput, err error) {
+			checkResult: func(t *testing.T, output *dto.LoginOutput, err error) {
 				assert.NoError(t, err)
-				assert.NotNil(t, output)
-				assert.Equal(t, int64(1), output.UserID)
-				assert.Equal(t, "John Doe", output.Name)
-				assert.Equal(t, "john@example.com", output.Email)
+				assert.Equal(t, "new-jwt-token", output.Token)
+				assert.NotEmpty(t, output.RefreshToken)
+				assert.NotEqual(t, refreshToken, output.RefreshToken)
 			},
 		},
 		{
-			name:   "should return error when userID is invalid",
-			userID: 0,
+			name:  "should return error when input is invalid",
+			input: dto.RefreshInput{},
 			setupMocks: func() {
 				// No mock calls expected
 			},
-			checkResult: func(t *testing.T, output *dto.GetMeOutput, err error) {
-				assert.Error(t, err)
+			checkResult: func(t *testing.T, output *dto.LoginOutput, err error) {
 				assert.Nil(t, output)
-				assert.Equal(t, usecase.ErrInvalidUserID, err)
+				assert.Equal(t, usecase.ErrInvalidInput, err)
 			},
 		},
 		{
-			name:   "should return error when user not found",
-			userID: 999,
+			name:  "should return error when refresh token is unknown",
+			input: dto.RefreshInput{RefreshToken: refreshToken},
 			setupMocks: func() {
-				s.mockRepo.EXPECT().
-					GetByID(s.ctx, int64(999)).
+				s.mockRefresh.EXPECT().
+					GetByHash(s.ctx, refreshHash).
 					Return(nil, nil)
 			},
-			checkResult: func(t *testing.T, output *dto.GetMeOutput, err error) {
-				assert.Error(t, err)
+			checkResult: func(t *testing.T, output *dto.LoginOutput, err error) {
 				assert.Nil(t, output)
-				assert.Equal(t, usecase.ErrUserNotFound, err)
+				assert.Equal(t, usecase.ErrInvalidRefreshToken, err)
 			},
 		},
 		{
-			name:   "should return error when repository fails",
-			userID: 1,
+			name:  "should return error when refresh token is expired",
+			input: dto.RefreshInput{RefreshToken: refreshToken},
+			setupMocks: func() {
+				s.mockRefresh.EXPECT().
+					GetByHash(s.ctx, refreshHash).
+					Return(&domain.RefreshToken{TokenHash: refreshHash, FamilyID: "fam-1", UserID: 1, ExpiresAt: time.Now().Add(-time.Minute).Unix()}, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.LoginOutput, err error) {
+				assert.Nil(t, output)
+				assert.Equal(t, usecase.ErrInvalidRefreshToken, err)
+			},
+		},
+		{
+			name:  "should return error when refresh token family was revoked",
+			input: dto.RefreshInput{RefreshToken: refreshToken},
+			setupMocks: func() {
+				s.mockRefresh.EXPECT().
+					GetByHash(s.ctx, refreshHash).
+					Return(&domain.RefreshToken{TokenHash: refreshHash, FamilyID: "fam-1", UserID: 1, ExpiresAt: future, Revoked: true}, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.LoginOutput, err error) {
+				assert.Nil(t, output)
+				assert.Equal(t, usecase.ErrInvalidRefreshToken, err)
+			},
+		},
+		{
+			name:  "should revoke family when a used refresh token is replayed",
+			input: dto.RefreshInput{RefreshToken: refreshToken},
+			setupMocks: func() {
+				s.mockRefresh.EXPECT().
+					GetByHash(s.ctx, refreshHash).
+					Return(&domain.RefreshToken{TokenHash: refreshHash, FamilyID: "fam-1", UserID: 1, ExpiresAt: future, UsedAt: time.Now().Unix()}, nil)
+				s.mockRefresh.EXPECT().
+					RevokeFamily(s.ctx, "fam-1").
+					Return(nil)
+			},
+			checkResult: func(t *testing.T, output *dto.LoginOutput, err error) {
+				assert.Nil(t, output)
+				assert.Equal(t, usecase.ErrRefreshTokenReused, err)
+			},
+		},
+		{
+			name:  "should revoke family when a concurrent rotation wins",
+			input: dto.RefreshInput{RefreshToken: refreshToken},
+			setupMocks: func() {
+				s.mockRefresh.EXPECT().
+					GetByHash(s.ctx, refreshHash).
+					Return(&domain.RefreshToken{TokenHash: refreshHash, FamilyID: "fam-1", UserID: 1, ExpiresAt: future}, nil)
+				s.mockRefresh.EXPECT().
+					MarkUsed(s.ctx, refreshHash, gomock.Any()).
+					Return(false, nil)
+				s.mockRefresh.EXPECT().
+					RevokeFamily(s.ctx, "fam-1").
+					Return(nil)
+			},
+			checkResult: func(t *testing.T, output *dto.LoginOutput, err error) {
+				assert.Nil(t, output)
+				assert.Equal(t, usecase.ErrRefreshTokenReused, err)
+			},
+		},
+		{
+			name:  "should return error when user no longer exists",
+			input: dto.RefreshInput{RefreshToken: refreshToken},
 			setupMocks: func() {
+				s.mockRefresh.EXPECT().
+					GetByHash(s.ctx, refreshHash).
+					Return(&domain.RefreshToken{TokenHash: refreshHash, FamilyID: "fam-1", UserID: 1, ExpiresAt: future}, nil)
+				s.mockRefresh.EXPECT().
+					MarkUsed(s.ctx, refreshHash, gomock.Any()).
+					Return(true, nil)
 				s.mockRepo.EXPECT().
 					GetByID(s.ctx, int64(1)).
-					Return(nil, assert.AnError)
+					Return(nil, nil)
 			},
-			checkResult: func(t *testing.T, output *dto.GetMeOutput, err error) {
-				assert.Error(t, err)
+			checkResult: func(t *testing.T, output *dto.LoginOutput, err error) {
 				assert.Nil(t, output)
-				assert.Equal(t, usecase.ErrUserNotFound, err)
+				assert.Equal(t, usecase.ErrInvalidRefreshToken, err)
 			},
 		},
 	}
@@ -368,7 +633,7 @@ func (s *UserUsecaseSuiteTest) TestUserUseCase_GetMe() {
 			tt.setupMocks()
 
 			// Act
-			output, err := s.useCase.GetMe(s.ctx, tt.userID)
+			output, err := s.useCase.Refresh(s.ctx, tt.input)
 
 			// Assert
 			tt.checkResult(t, output, err)
@@ -376,67 +641,107 @@ func (s *UserUsecaseSuiteTest) TestUserUseCase_GetMe() {
 	}
 }
 
-func (s *UserUsecaseSuiteTest) TestUserUseCase_GetUserByID() {
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Logout() {
+	const refreshToken = "refresh-token"
+	sum := sha256.Sum256([]byte(refreshToken))
+	refreshHash := hex.EncodeToString(sum[:])
+
 	tests := []struct {
 		name        string
-		userID      int64
+		input       dto.LogoutInput
 		setupMocks  func()
-		checkResult func(*testing.T, *dto.GetUserByIDOutput, error)
+		checkResult func(*testing.T, error)
 	}{
 		{
-			name:   "should get user by ID successfully",
-			userID: 1,
+			name:  "should revoke access token and refresh family",
+			input: dto.LogoutInput{AccessToken: "jwt-token", RefreshToken: refreshToken},
 			setupMocks: func() {
-				s.mockRepo.EXPECT().
-					GetByID(s.ctx, int64(1)).
-					Return(s.mockUsers[0], nil)
+				s.mockJWTSigner.EXPECT().Verify(s.ctx, "jwt-token").Return(int64(1), int64(0), nil)
+				s.mockJWTSigner.EXPECT().Revoke(s.ctx, "jwt-token").Return(nil)
+				s.mockRefresh.EXPECT().
+					GetByHash(s.ctx, refreshHash).
+					Return(&domain.RefreshToken{TokenHash: refreshHash, FamilyID: "fam-1", UserID: 1}, nil)
+				s.mockRefresh.EXPECT().RevokeFamily(s.ctx, "fam-1").Return(nil)
 			},
-			checkResult: func(t *testing.T, output *dto.GetUserByIDOutput, err error) {
+			checkResult: func(t *testing.T, err error) {
 				assert.NoError(t, err)
-				assert.NotNil(t, output)
-				assert.Equal(t, int64(1), output.UserID)
-				assert.Equal(t, "John Doe", output.Name)
-				assert.Equal(t, "john@example.com", output.Email)
 			},
 		},
 		{
-			name:   "should return error when userID is invalid",
-			userID: 0,
+			name:  "should revoke only access token when no refresh token is given",
+			input: dto.LogoutInput{AccessToken: "jwt-token"},
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().Verify(s.ctx, "jwt-token").Return(int64(1), int64(0), nil)
+				s.mockJWTSigner.EXPECT().Revoke(s.ctx, "jwt-token").Return(nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "should not revoke refresh family owned by another user",
+			input: dto.LogoutInput{AccessToken: "jwt-token", RefreshToken: refreshToken},
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().Verify(s.ctx, "jwt-token").Return(int64(1), int64(0), nil)
+				s.mockJWTSigner.EXPECT().Revoke(s.ctx, "jwt-token").Return(nil)
+				s.mockRefresh.EXPECT().
+					GetByHash(s.ctx, refreshHash).
+					Return(&domain.RefreshToken{TokenHash: refreshHash, FamilyID: "fam-2", UserID: 2}, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "should leave the user's refresh family alone for impersonation tokens",
+			input: dto.LogoutInput{AccessToken: "jwt-token", RefreshToken: refreshToken},
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().Verify(s.ctx, "jwt-token").Return(int64(1), int64(9), nil)
+				s.mockJWTSigner.EXPECT().Revoke(s.ctx, "jwt-token").Return(nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "should refuse personal access tokens",
+			input: dto.LogoutInput{AccessToken: "pat_abc123"},
 			setupMocks: func() {
 				// No mock calls expected
 			},
-			checkResult: func(t *testing.T, output *dto.GetUserByIDOutput, err error) {
-				assert.Error(t, err)
-				assert.Nil(t, output)
-				assert.Equal(t, usecase.ErrInvalidUserID, err)
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrPersonalAccessTokenLogout, err)
 			},
 		},
 		{
-			name:   "should return error when user not found",
-			userID: 999,
+			name:  "should return error when access token is missing",
+			input: dto.LogoutInput{},
 			setupMocks: func() {
-				s.mockRepo.EXPECT().
-					GetByID(s.ctx, int64(999)).
-					Return(nil, nil)
+				// No mock calls expected
 			},
-			checkResult: func(t *testing.T, output *dto.GetUserByIDOutput, err error) {
-				assert.Error(t, err)
-				assert.Nil(t, output)
-				assert.Equal(t, usecase.ErrUserNotFound, err)
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidInput, err)
 			},
 		},
 		{
-			name:   "should return error when repository fails",
-			userID: 1,
+			name:  "should return error when access token is invalid",
+			input: dto.LogoutInput{AccessToken: "bad-token"},
 			setupMocks: func() {
-				s.mockRepo.EXPECT().
-					GetByID(s.ctx, int64(1)).
-					Return(nil, assert.AnError)
+				s.mockJWTSigner.EXPECT().Verify(s.ctx, "bad-token").Return(int64(0), int64(0), assert.AnError)
 			},
-			checkResult: func(t *testing.T, output *dto.GetUserByIDOutput, err error) {
-				assert.Error(t, err)
-				assert.Nil(t, output)
-				assert.Equal(t, usecase.ErrUserNotFound, err)
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidToken, err)
+			},
+		},
+		{
+			name:  "should return error when revocation fails",
+			input: dto.LogoutInput{AccessToken: "jwt-token"},
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().Verify(s.ctx, "jwt-token").Return(int64(1), int64(0), nil)
+				s.mockJWTSigner.EXPECT().Revoke(s.ctx, "jwt-token").Return(assert.AnError)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, assert.AnError, err)
 			},
 		},
 	}
@@ -447,10 +752,3076 @@ func (s *UserUsecaseSuiteTest) TestUserUseCase_GetUserByID() {
 			tt.setupMocks()
 
 			// Act
-			output, err := s.useCase.GetUserByID(s.ctx, tt.userID)
+			err := s.useCase.Logout(s.ctx, tt.input)
 
 			// Assert
-			tt.checkResult(t, output, err)
+			tt.checkResult(t, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_ForgotPassword() {
+	tests := []struct {
+		name        string
+		input       dto.ForgotPasswordInput
+		setupMocks  func()
+		checkResult func(*testing.T, error)
+	}{
+		{
+			name:  "should store a hashed reset token and send the token to the user",
+			input: dto.ForgotPasswordInput{Email: "john@example.com"},
+			setupMocks: func() {
+				var stored *domain.OneTimeToken
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(s.mockUsers[0], nil)
+				s.mockOneTime.EXPECT().
+					Create(s.ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, t *domain.OneTimeToken) error {
+						stored = t
+						return nil
+					})
+				s.mockNotifier.EXPECT().
+					Notify(s.ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, n domain.Notification) error {
+						assert.Equal(s.T(), domain.NotificationPasswordReset, n.Type)
+						assert.Equal(s.T(), "john@example.com", n.To)
+						assert.NotEmpty(s.T(), n.Token)
+						sum := sha256.Sum256([]byte(n.Token))
+						assert.Equal(s.T(), hex.EncodeToString(sum[:]), stored.TokenHash)
+						assert.Equal(s.T(), domain.TokenPurposePasswordReset, stored.Purpose)
+						assert.Equal(s.T(), int64(1), stored.UserID)
+						assert.InDelta(s.T(), time.Now().Add(time.Hour).Unix(), stored.ExpiresAt, 5)
+						assert.Equal(s.T(), stored.ExpiresAt, n.ExpiresAt)
+						return nil
+					})
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "should succeed silently for an unknown email",
+			input: dto.ForgotPasswordInput{Email: "nobody@example.com"},
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "nobody@example.com").Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "should return error when token cannot be stored",
+			input: dto.ForgotPasswordInput{Email: "john@example.com"},
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(s.mockUsers[0], nil)
+				s.mockOneTime.EXPECT().Create(s.ctx, gomock.Any()).Return(assert.AnError)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, assert.AnError)
+			},
+		},
+		{
+			name:  "should return error when email is missing",
+			input: dto.ForgotPasswordInput{},
+			setupMocks: func() {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			err := s.useCase.ForgotPassword(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_ResetPassword() {
+	const resetToken = "reset-token"
+	sum := sha256.Sum256([]byte(resetToken))
+	resetHash := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name        string
+		input       dto.ResetPasswordInput
+		setupMocks  func()
+		checkResult func(*testing.T, error)
+	}{
+		{
+			name:  "should consume the token and store the new password hash",
+			input: dto.ResetPasswordInput{Token: resetToken, NewPassword: "new-password"},
+			setupMocks: func() {
+				user := *s.mockUsers[0]
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, resetHash, domain.TokenPurposePasswordReset, gomock.Any()).
+					Return(&domain.OneTimeToken{TokenHash: resetHash, Purpose: domain.TokenPurposePasswordReset, UserID: 1}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(&user, nil)
+				s.mockHasher.EXPECT().Hash("new-password").Return("new-hash", nil)
+				s.mockRepo.EXPECT().
+					SetPassword(s.ctx, int64(1), "new-hash", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int64, _ string, now int64) error {
+						assert.NotZero(s.T(), now, "a reset invalidates existing tokens")
+						return nil
+					})
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "should reject an unknown, used or expired token",
+			input: dto.ResetPasswordInput{Token: resetToken, NewPassword: "new-password"},
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, resetHash, domain.TokenPurposePasswordReset, gomock.Any()).
+					Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidResetToken, err)
+			},
+		},
+		{
+			name:  "should reject a token of a deleted user",
+			input: dto.ResetPasswordInput{Token: resetToken, NewPassword: "new-password"},
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, resetHash, domain.TokenPurposePasswordReset, gomock.Any()).
+					Return(&domain.OneTimeToken{UserID: 1}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidResetToken, err)
+			},
+		},
+		{
+			name:  "should return error when new password is missing",
+			input: dto.ResetPasswordInput{Token: resetToken},
+			setupMocks: func() {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			err := s.useCase.ResetPassword(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, err)
+		})
+	}
+}
+
+// policyUseCase builds a use case with a strict password policy, sharing the suite mocks.
+func (s *UserUsecaseSuiteTest) policyUseCase() port.UserUseCase {
+	return usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+		usecase.WithNotifier(s.mockNotifier),
+		usecase.WithPasswordReset(s.mockOneTime, time.Hour),
+		usecase.WithPasswordPolicy(usecase.PasswordPolicy{MinLength: 12, RejectPersonal: true, RejectCommon: true}),
+	)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Register_PasswordPolicy() {
+	s.T().Run("should report every broken rule without touching the repository", func(t *testing.T) {
+		// Act
+		_, err := s.policyUseCase().Register(s.ctx, dto.RegisterInput{Name: "John Doe", Email: "john@example.com", Password: "john"})
+
+		// Assert
+		var policyErr *usecase.PasswordPolicyError
+		assert.ErrorAs(t, err, &policyErr)
+		assert.ErrorIs(t, err, usecase.ErrWeakPassword)
+		assert.Equal(t, []string{
+			"must be at least 12 characters long",
+			"must not contain your email address",
+			"must not contain your name",
+		}, policyErr.Violations)
+	})
+
+	s.T().Run("should hash the normalized password", func(t *testing.T) {
+		// Arrange
+		const password = "ｃｏｒｒｅｃｔ ｈｏｒｓｅ" // fullwidth forms, NFKC "correct horse"
+		s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(nil, nil)
+		s.mockHasher.EXPECT().Hash("correct horse").Return("hashed-password", nil)
+		s.mockRepo.EXPECT().Create(s.ctx, gomock.Any()).Return(nil)
+
+		// Act
+		_, err := s.policyUseCase().Register(s.ctx, dto.RegisterInput{Name: "John Doe", Email: "john@example.com", Password: password})
+
+		// Assert
+		assert.NoError(t, err)
+	})
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_ResetPassword_PasswordPolicy() {
+	const resetToken = "reset-token"
+	sum := sha256.Sum256([]byte(resetToken))
+	resetHash := hex.EncodeToString(sum[:])
+
+	s.T().Run("should keep the token when the password breaks an account-independent rule", func(t *testing.T) {
+		// Act
+		err := s.policyUseCase().ResetPassword(s.ctx, dto.ResetPasswordInput{Token: resetToken, NewPassword: "short"})
+
+		// Assert
+		assert.ErrorIs(t, err, usecase.ErrWeakPassword)
+	})
+
+	s.T().Run("should refuse a password containing the account's name", func(t *testing.T) {
+		// Arrange
+		user := *s.mockUsers[1]
+		s.mockOneTime.EXPECT().
+			Consume(s.ctx, resetHash, domain.TokenPurposePasswordReset, gomock.Any()).
+			Return(&domain.OneTimeToken{UserID: 2}, nil)
+		s.mockRepo.EXPECT().GetByID(s.ctx, int64(2)).Return(&user, nil)
+
+		// Act
+		err := s.policyUseCase().ResetPassword(s.ctx, dto.ResetPasswordInput{Token: resetToken, NewPassword: "jane-and-the-long-river"})
+
+		// Assert
+		assert.ErrorIs(t, err, usecase.ErrWeakPassword)
+	})
+}
+
+// enumerationSafeUseCase builds a use case whose registration does not reveal taken emails,
+// sharing the suite mocks.
+func (s *UserUsecaseSuiteTest) enumerationSafeUseCase() port.UserUseCase {
+	return usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+		usecase.WithNotifier(s.mockNotifier),
+		usecase.WithPasswordReset(s.mockOneTime, time.Hour),
+		usecase.WithEnumerationSafeRegistration(),
+	)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Register_EnumerationSafe() {
+	in := dto.RegisterInput{Name: "John Doe", Email: "john@example.com", Password: "password123"}
+
+	s.T().Run("should answer a taken email like a new account and notify the owner", func(t *testing.T) {
+		// Arrange
+		s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(s.mockUsers[0], nil)
+		s.mockHasher.EXPECT().Hash("password123").Return("hashed-password", nil)
+		s.mockNotifier.EXPECT().
+			Notify(s.ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, n domain.Notification) error {
+				assert.Equal(t, domain.NotificationAccountExists, n.Type)
+				assert.Equal(t, "john@example.com", n.To)
+				assert.Empty(t, n.Token, "no reset token is minted for the owner")
+				return assert.AnError // delivery failures stay invisible
+			})
+
+		// Act
+		out, err := s.enumerationSafeUseCase().Register(s.ctx, in)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, &dto.RegisterOutput{Name: "John Doe", Email: "john@example.com"}, out)
+	})
+
+	s.T().Run("should leave the user ID out of a new account's answer", func(t *testing.T) {
+		// Arrange
+		s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(nil, nil)
+		s.mockHasher.EXPECT().Hash("password123").Return("hashed-password", nil)
+		s.mockRepo.EXPECT().
+			Create(s.ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, u *domain.User) error {
+				u.UserID = 7
+				return nil
+			})
+
+		// Act
+		out, err := s.enumerationSafeUseCase().Register(s.ctx, in)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, &dto.RegisterOutput{Name: "John Doe", Email: "john@example.com"}, out)
+	})
+}
+
+// verifyingUseCase builds a use case that requires verified emails, sharing the suite mocks.
+func (s *UserUsecaseSuiteTest) verifyingUseCase() port.UserUseCase {
+	return usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+		usecase.WithNotifier(s.mockNotifier),
+		usecase.WithEmailVerification(s.mockOneTime, 24*time.Hour, true),
+	)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Register_EmailVerification() {
+	in := dto.RegisterInput{Name: "John Doe", Email: "john@example.com", Password: "password123"}
+
+	s.T().Run("should create an unverified user and send a verification token", func(t *testing.T) {
+		s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(nil, nil)
+		s.mockHasher.EXPECT().Hash("password123").Return("hashed-password", nil)
+		s.mockRepo.EXPECT().
+			Create(s.ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, u *domain.User) error {
+				assert.False(t, u.EmailVerified)
+				u.UserID = 1
+				return nil
+			})
+		s.mockOneTime.EXPECT().
+			Create(s.ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, ott *domain.OneTimeToken) error {
+				assert.Equal(t, domain.TokenPurposeEmailVerification, ott.Purpose)
+				assert.Equal(t, int64(1), ott.UserID)
+				assert.InDelta(t, time.Now().Add(24*time.Hour).Unix(), ott.ExpiresAt, 5)
+				return nil
+			})
+		s.mockNotifier.EXPECT().
+			Notify(s.ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, n domain.Notification) error {
+				assert.Equal(t, domain.NotificationEmailVerification, n.Type)
+				assert.Equal(t, "john@example.com", n.To)
+				return nil
+			})
+
+		out, err := s.verifyingUseCase().Register(s.ctx, in)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), out.UserID)
+	})
+
+	s.T().Run("should register even when the verification email cannot be sent", func(t *testing.T) {
+		s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(nil, nil)
+		s.mockHasher.EXPECT().Hash("password123").Return("hashed-password", nil)
+		s.mockRepo.EXPECT().Create(s.ctx, gomock.Any()).Return(nil)
+		s.mockOneTime.EXPECT().Create(s.ctx, gomock.Any()).Return(nil)
+		s.mockNotifier.EXPECT().Notify(s.ctx, gomock.Any()).Return(assert.AnError)
+
+		out, err := s.verifyingUseCase().Register(s.ctx, in)
+		assert.NoError(t, err)
+		assert.NotNil(t, out)
+	})
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Login_RequiresVerifiedEmail() {
+	unverified := &domain.User{UserID: 1, Email: "john@example.com", Password: testHashedPassword}
+
+	s.T().Run("should refuse an unverified account", func(t *testing.T) {
+		s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(unverified, nil)
+		s.expectPasswordCheck(testHashedPassword, "password123", true)
+
+		out, err := s.verifyingUseCase().Login(s.ctx, dto.LoginInput{Email: "john@example.com", Password: "password123"})
+		assert.Equal(t, usecase.ErrEmailNotVerified, err)
+		assert.Nil(t, out)
+	})
+
+	s.T().Run("should check the password before the verification state", func(t *testing.T) {
+		s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(unverified, nil)
+		s.expectPasswordCheck(testHashedPassword, "wrong", false)
+
+		_, err := s.verifyingUseCase().Login(s.ctx, dto.LoginInput{Email: "john@example.com", Password: "wrong"})
+		assert.Equal(t, usecase.ErrInvalidCredentials, err)
+	})
+
+	s.T().Run("should log in a verified account", func(t *testing.T) {
+		verified := *unverified
+		verified.EmailVerified = true
+		s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(&verified, nil)
+		s.expectPasswordCheck(testHashedPassword, "password123", true)
+		s.mockJWTSigner.EXPECT().Sign(gomock.Any()).Return("jwt-token", nil)
+
+		out, err := s.verifyingUseCase().Login(s.ctx, dto.LoginInput{Email: "john@example.com", Password: "password123"})
+		assert.NoError(t, err)
+		assert.Equal(t, "jwt-token", out.Token)
+	})
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_VerifyEmail() {
+	const verifyToken = "verify-token"
+	sum := sha256.Sum256([]byte(verifyToken))
+	verifyHash := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name        string
+		input       dto.VerifyEmailInput
+		setupMocks  func()
+		checkResult func(*testing.T, error)
+	}{
+		{
+			name:  "should mark the email as verified",
+			input: dto.VerifyEmailInput{Token: verifyToken},
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, verifyHash, domain.TokenPurposeEmailVerification, gomock.Any()).
+					Return(&domain.OneTimeToken{UserID: 1}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(&domain.User{UserID: 1}, nil)
+				s.mockRepo.EXPECT().MarkEmailVerified(s.ctx, int64(1), gomock.Any()).Return(nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "should not rewrite an already verified user",
+			input: dto.VerifyEmailInput{Token: verifyToken},
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, verifyHash, domain.TokenPurposeEmailVerification, gomock.Any()).
+					Return(&domain.OneTimeToken{UserID: 1}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(&domain.User{UserID: 1, EmailVerified: true}, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "should reject an unknown, used or expired token",
+			input: dto.VerifyEmailInput{Token: verifyToken},
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, verifyHash, domain.TokenPurposeEmailVerification, gomock.Any()).
+					Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidVerificationToken, err)
+			},
+		},
+		{
+			name:  "should return error when token is missing",
+			input: dto.VerifyEmailInput{},
+			setupMocks: func() {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			err := s.verifyingUseCase().VerifyEmail(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_ResendVerification() {
+	tests := []struct {
+		name        string
+		input       dto.ResendVerificationInput
+		setupMocks  func()
+		checkResult func(*testing.T, error)
+	}{
+		{
+			name:  "should send a new token to an unverified account",
+			input: dto.ResendVerificationInput{Email: "john@example.com"},
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(&domain.User{UserID: 1, Email: "john@example.com"}, nil)
+				s.mockOneTime.EXPECT().Create(s.ctx, gomock.Any()).Return(nil)
+				s.mockNotifier.EXPECT().Notify(s.ctx, gomock.Any()).Return(nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "should do nothing for a verified account",
+			input: dto.ResendVerificationInput{Email: "john@example.com"},
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(&domain.User{UserID: 1, EmailVerified: true}, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "should do nothing for an unknown email",
+			input: dto.ResendVerificationInput{Email: "nobody@example.com"},
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "nobody@example.com").Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			err := s.verifyingUseCase().ResendVerification(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, err)
+		})
+	}
+}
+
+// lockingUseCase builds a use case that locks accounts after three failed logins.
+func (s *UserUsecaseSuiteTest) lockingUseCase() port.UserUseCase {
+	return usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+		usecase.WithLockout(3, time.Minute, time.Hour),
+		usecase.WithTOTP(s.mockCipher, s.mockOneTime, "hackathon", 5*time.Minute),
+	)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Login_Lockout() {
+	wrong := dto.LoginInput{Email: "john@example.com", Password: "wrong"}
+	right := dto.LoginInput{Email: "john@example.com", Password: "password123"}
+	newUser := func() *domain.User {
+		return &domain.User{UserID: 1, Email: "john@example.com", Password: testHashedPassword}
+	}
+
+	tests := []struct {
+		name        string
+		input       dto.LoginInput
+		setupMocks  func()
+		expectError error
+	}{
+		{
+			name:  "should count a failure below the threshold",
+			input: wrong,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(newUser(), nil)
+				s.expectPasswordCheck(testHashedPassword, "wrong", false)
+				s.mockRepo.EXPECT().RecordLoginFailure(s.ctx, int64(1)).Return(2, nil)
+			},
+			expectError: usecase.ErrInvalidCredentials,
+		},
+		{
+			name:  "should lock the account when the threshold is reached",
+			input: wrong,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(newUser(), nil)
+				s.expectPasswordCheck(testHashedPassword, "wrong", false)
+				s.mockRepo.EXPECT().RecordLoginFailure(s.ctx, int64(1)).Return(3, nil)
+				s.mockRepo.EXPECT().
+					LockUntil(s.ctx, int64(1), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int64, until int64) error {
+						assert.InDelta(s.T(), time.Now().Add(time.Minute).Unix(), until, 5)
+						return nil
+					})
+			},
+			expectError: usecase.ErrInvalidCredentials,
+		},
+		{
+			name:  "should double the lock for every further failure",
+			input: wrong,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(newUser(), nil)
+				s.expectPasswordCheck(testHashedPassword, "wrong", false)
+				s.mockRepo.EXPECT().RecordLoginFailure(s.ctx, int64(1)).Return(5, nil)
+				s.mockRepo.EXPECT().
+					LockUntil(s.ctx, int64(1), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int64, until int64) error {
+						assert.InDelta(s.T(), time.Now().Add(4*time.Minute).Unix(), until, 5)
+						return nil
+					})
+			},
+			expectError: usecase.ErrInvalidCredentials,
+		},
+		{
+			name:  "should cap the lock duration",
+			input: wrong,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(newUser(), nil)
+				s.expectPasswordCheck(testHashedPassword, "wrong", false)
+				s.mockRepo.EXPECT().RecordLoginFailure(s.ctx, int64(1)).Return(40, nil)
+				s.mockRepo.EXPECT().
+					LockUntil(s.ctx, int64(1), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int64, until int64) error {
+						assert.InDelta(s.T(), time.Now().Add(time.Hour).Unix(), until, 5)
+						return nil
+					})
+			},
+			expectError: usecase.ErrInvalidCredentials,
+		},
+		{
+			name:  "should refuse a locked account even with the right password",
+			input: right,
+			setupMocks: func() {
+				locked := newUser()
+				locked.FailedLogins = 3
+				locked.LockedUntil = time.Now().Add(time.Minute).Unix()
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(locked, nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "password123").Return(true, nil)
+			},
+			expectError: usecase.ErrAccountLocked,
+		},
+		{
+			name:  "should answer a wrong password for a locked account like any wrong password",
+			input: wrong,
+			setupMocks: func() {
+				locked := newUser()
+				locked.FailedLogins = 3
+				locked.LockedUntil = time.Now().Add(time.Minute).Unix()
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(locked, nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "wrong").Return(false, nil)
+			},
+			expectError: usecase.ErrInvalidCredentials,
+		},
+		{
+			name:  "should reset the counter on a successful login",
+			input: right,
+			setupMocks: func() {
+				expired := newUser()
+				expired.FailedLogins = 3
+				expired.LockedUntil = time.Now().Add(-time.Second).Unix()
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(expired, nil)
+				s.expectPasswordCheck(testHashedPassword, "password123", true)
+				s.mockRepo.EXPECT().ResetLoginFailures(s.ctx, int64(1)).Return(nil)
+				s.mockJWTSigner.EXPECT().Sign(gomock.Any()).Return("jwt-token", nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			out, err := s.lockingUseCase().Login(s.ctx, tt.input)
+
+			// Assert
+			if tt.expectError != nil {
+				assert.Equal(t, tt.expectError, err)
+				assert.Nil(t, out)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "jwt-token", out.Token)
+			}
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_LoginMFA_Lockout() {
+	secret := []byte("12345678901234567890")
+	challenge := &domain.OneTimeToken{Purpose: domain.TokenPurposeMFAChallenge, UserID: 1}
+
+	s.T().Run("should count a wrong code as a failed login", func(t *testing.T) {
+		s.mockOneTime.EXPECT().Consume(s.ctx, gomock.Any(), domain.TokenPurposeMFAChallenge, gomock.Any()).Return(challenge, nil)
+		s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(&domain.User{UserID: 1, MFAEnabled: true, TOTPSecret: "encrypted"}, nil)
+		s.mockCipher.EXPECT().Decrypt("encrypted").Return(secret, nil)
+		s.mockRepo.EXPECT().RecordLoginFailure(s.ctx, int64(1)).Return(3, nil)
+		s.mockRepo.EXPECT().LockUntil(s.ctx, int64(1), gomock.Any()).Return(nil)
+
+		out, err := s.lockingUseCase().LoginMFA(s.ctx, dto.LoginMFAInput{MFAToken: "mfa-token", Code: "000000"})
+		assert.Equal(t, usecase.ErrAccountLocked, err)
+		assert.Nil(t, out)
+	})
+
+	s.T().Run("should clear failures once the code is right", func(t *testing.T) {
+		s.mockOneTime.EXPECT().Consume(s.ctx, gomock.Any(), domain.TokenPurposeMFAChallenge, gomock.Any()).Return(challenge, nil)
+		s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(&domain.User{UserID: 1, MFAEnabled: true, TOTPSecret: "encrypted", FailedLogins: 2}, nil)
+		s.mockCipher.EXPECT().Decrypt("encrypted").Return(secret, nil)
+		s.mockRepo.EXPECT().UseTOTPStep(s.ctx, int64(1), gomock.Any()).Return(true, nil)
+		s.mockRepo.EXPECT().ResetLoginFailures(s.ctx, int64(1)).Return(nil)
+		s.mockJWTSigner.EXPECT().Sign(gomock.Any()).Return("jwt-token", nil)
+
+		out, err := s.lockingUseCase().LoginMFA(s.ctx, dto.LoginMFAInput{MFAToken: "mfa-token", Code: usecase.CurrentTOTPCode(secret)})
+		assert.NoError(t, err)
+		assert.Equal(t, "jwt-token", out.Token)
+	})
+}
+
+// mfaUseCase builds a use case with TOTP enabled, sharing the suite mocks.
+func (s *UserUsecaseSuiteTest) mfaUseCase() port.UserUseCase {
+	return usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+		usecase.WithTOTP(s.mockCipher, s.mockOneTime, "hackathon", 5*time.Minute),
+	)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Login_MFAChallenge() {
+	s.T().Run("should answer with a challenge instead of tokens", func(t *testing.T) {
+		user := &domain.User{UserID: 1, Email: "john@example.com", Password: testHashedPassword, MFAEnabled: true, TOTPSecret: "encrypted"}
+		s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(user, nil)
+		s.expectPasswordCheck(testHashedPassword, "password123", true)
+		s.mockOneTime.EXPECT().
+			Create(s.ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, ott *domain.OneTimeToken) error {
+				assert.Equal(t, domain.TokenPurposeMFAChallenge, ott.Purpose)
+				assert.Equal(t, int64(1), ott.UserID)
+				assert.InDelta(t, time.Now().Add(5*time.Minute).Unix(), ott.ExpiresAt, 5)
+				return nil
+			})
+
+		out, err := s.mfaUseCase().Login(s.ctx, dto.LoginInput{Email: "john@example.com", Password: "password123"})
+		assert.NoError(t, err)
+		assert.True(t, out.MFARequired)
+		assert.NotEmpty(t, out.MFAToken)
+		assert.Empty(t, out.Token)
+	})
+
+	s.T().Run("should refuse MFA accounts when TOTP is not configured", func(t *testing.T) {
+		user := &domain.User{UserID: 1, Email: "john@example.com", Password: testHashedPassword, MFAEnabled: true}
+		s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(user, nil)
+		s.expectPasswordCheck(testHashedPassword, "password123", true)
+
+		out, err := s.useCase.Login(s.ctx, dto.LoginInput{Email: "john@example.com", Password: "password123"})
+		assert.Equal(t, usecase.ErrMFADisabled, err)
+		assert.Nil(t, out)
+	})
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_LoginMFA() {
+	secret := []byte("12345678901234567890")
+	const mfaToken = "mfa-token"
+	sum := sha256.Sum256([]byte(mfaToken))
+	mfaHash := hex.EncodeToString(sum[:])
+	challenge := &domain.OneTimeToken{TokenHash: mfaHash, Purpose: domain.TokenPurposeMFAChallenge, UserID: 1}
+	newUser := func() *domain.User {
+		return &domain.User{UserID: 1, Email: "john@example.com", MFAEnabled: true, TOTPSecret: "encrypted"}
+	}
+
+	tests := []struct {
+		name        string
+		input       func() dto.LoginMFAInput
+		setupMocks  func()
+		expectError error
+	}{
+		{
+			name: "should issue tokens for a valid code",
+			input: func() dto.LoginMFAInput {
+				return dto.LoginMFAInput{MFAToken: mfaToken, Code: usecase.CurrentTOTPCode(secret)}
+			},
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().Consume(s.ctx, mfaHash, domain.TokenPurposeMFAChallenge, gomock.Any()).Return(challenge, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(newUser(), nil)
+				s.mockCipher.EXPECT().Decrypt("encrypted").Return(secret, nil)
+				s.mockRepo.EXPECT().
+					UseTOTPStep(s.ctx, int64(1), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int64, step int64) (bool, error) {
+						assert.InDelta(s.T(), time.Now().Unix()/30, step, 1)
+						return true, nil
+					})
+				s.mockJWTSigner.EXPECT().Sign(gomock.Any()).Return("jwt-token", nil)
+			},
+		},
+		{
+			name:  "should reject a wrong code",
+			input: func() dto.LoginMFAInput { return dto.LoginMFAInput{MFAToken: mfaToken, Code: "000000"} },
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().Consume(s.ctx, mfaHash, domain.TokenPurposeMFAChallenge, gomock.Any()).Return(challenge, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(newUser(), nil)
+				s.mockCipher.EXPECT().Decrypt("encrypted").Return(secret, nil)
+			},
+			expectError: usecase.ErrInvalidMFACode,
+		},
+		{
+			name: "should reject a replayed code",
+			input: func() dto.LoginMFAInput {
+				return dto.LoginMFAInput{MFAToken: mfaToken, Code: usecase.CurrentTOTPCode(secret)}
+			},
+			setupMocks: func() {
+				used := newUser()
+				used.TOTPLastStep = time.Now().Unix()/30 + 1
+				s.mockOneTime.EXPECT().Consume(s.ctx, mfaHash, domain.TokenPurposeMFAChallenge, gomock.Any()).Return(challenge, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(used, nil)
+				s.mockCipher.EXPECT().Decrypt("encrypted").Return(secret, nil)
+			},
+			expectError: usecase.ErrInvalidMFACode,
+		},
+		{
+			name: "should reject a code a concurrent request used first",
+			input: func() dto.LoginMFAInput {
+				return dto.LoginMFAInput{MFAToken: mfaToken, Code: usecase.CurrentTOTPCode(secret)}
+			},
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().Consume(s.ctx, mfaHash, domain.TokenPurposeMFAChallenge, gomock.Any()).Return(challenge, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(newUser(), nil)
+				s.mockCipher.EXPECT().Decrypt("encrypted").Return(secret, nil)
+				s.mockRepo.EXPECT().UseTOTPStep(s.ctx, int64(1), gomock.Any()).Return(false, nil)
+			},
+			expectError: usecase.ErrInvalidMFACode,
+		},
+		{
+			name:  "should reject an unknown or expired challenge",
+			input: func() dto.LoginMFAInput { return dto.LoginMFAInput{MFAToken: mfaToken, Code: "123456"} },
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().Consume(s.ctx, mfaHash, domain.TokenPurposeMFAChallenge, gomock.Any()).Return(nil, nil)
+			},
+			expectError: usecase.ErrInvalidMFAToken,
+		},
+		{
+			name:        "should require both fields",
+			input:       func() dto.LoginMFAInput { return dto.LoginMFAInput{MFAToken: mfaToken} },
+			setupMocks:  func() {},
+			expectError: usecase.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			out, err := s.mfaUseCase().LoginMFA(s.ctx, tt.input())
+
+			// Assert
+			if tt.expectError != nil {
+				assert.Equal(t, tt.expectError, err)
+				assert.Nil(t, out)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "jwt-token", out.Token)
+			}
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_EnrollTOTP() {
+	s.T().Run("should store an encrypted secret without enabling MFA", func(t *testing.T) {
+		s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(&domain.User{UserID: 1, Email: "john@example.com"}, nil)
+		s.mockCipher.EXPECT().Encrypt(gomock.Any()).Return("encrypted", nil)
+		s.mockRepo.EXPECT().SetTOTPSecret(s.ctx, int64(1), "encrypted", gomock.Any()).Return(true, nil)
+
+		out, err := s.mfaUseCase().EnrollTOTP(s.ctx, 1)
+		assert.NoError(t, err)
+		assert.Len(t, out.Secret, 32)
+		assert.Contains(t, out.URI, "otpauth://totp/hackathon:john@example.com?")
+	})
+
+	s.T().Run("should refuse when MFA was enabled concurrently", func(t *testing.T) {
+		s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(&domain.User{UserID: 1, Email: "john@example.com"}, nil)
+		s.mockCipher.EXPECT().Encrypt(gomock.Any()).Return("encrypted", nil)
+		s.mockRepo.EXPECT().SetTOTPSecret(s.ctx, int64(1), "encrypted", gomock.Any()).Return(false, nil)
+
+		out, err := s.mfaUseCase().EnrollTOTP(s.ctx, 1)
+		assert.Equal(t, usecase.ErrMFAAlreadyEnabled, err)
+		assert.Nil(t, out)
+	})
+
+	s.T().Run("should refuse when MFA is already enabled", func(t *testing.T) {
+		s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(&domain.User{UserID: 1, MFAEnabled: true}, nil)
+
+		out, err := s.mfaUseCase().EnrollTOTP(s.ctx, 1)
+		assert.Equal(t, usecase.ErrMFAAlreadyEnabled, err)
+		assert.Nil(t, out)
+	})
+
+	s.T().Run("should refuse when TOTP is not configured", func(t *testing.T) {
+		out, err := s.useCase.EnrollTOTP(s.ctx, 1)
+		assert.Equal(t, usecase.ErrMFADisabled, err)
+		assert.Nil(t, out)
+	})
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_ConfirmTOTP() {
+	secret := []byte("12345678901234567890")
+
+	s.T().Run("should enable MFA for a valid code", func(t *testing.T) {
+		s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(&domain.User{UserID: 1, TOTPSecret: "encrypted"}, nil)
+		s.mockCipher.EXPECT().Decrypt("encrypted").Return(secret, nil)
+		s.mockRepo.EXPECT().UseTOTPStep(s.ctx, int64(1), gomock.Any()).Return(true, nil)
+		s.mockRepo.EXPECT().EnableMFA(s.ctx, int64(1), gomock.Any()).Return(nil)
+
+		err := s.mfaUseCase().ConfirmTOTP(s.ctx, dto.ConfirmTOTPInput{UserID: 1, Code: usecase.CurrentTOTPCode(secret)})
+		assert.NoError(t, err)
+	})
+
+	s.T().Run("should reject a wrong code", func(t *testing.T) {
+		s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(&domain.User{UserID: 1, TOTPSecret: "encrypted"}, nil)
+		s.mockCipher.EXPECT().Decrypt("encrypted").Return(secret, nil)
+
+		err := s.mfaUseCase().ConfirmTOTP(s.ctx, dto.ConfirmTOTPInput{UserID: 1, Code: "000000"})
+		assert.Equal(t, usecase.ErrInvalidMFACode, err)
+	})
+
+	s.T().Run("should require a pending enrollment", func(t *testing.T) {
+		s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(&domain.User{UserID: 1}, nil)
+
+		err := s.mfaUseCase().ConfirmTOTP(s.ctx, dto.ConfirmTOTPInput{UserID: 1, Code: "123456"})
+		assert.Equal(t, usecase.ErrMFANotEnrolled, err)
+	})
+}
+
+// passkeyUseCase builds a use case with passkeys enabled, sharing the suite mocks.
+func (s *UserUsecaseSuiteTest) passkeyUseCase() port.UserUseCase {
+	return usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+		usecase.WithPasskeys(s.mockWebAuthn, s.mockPasskeys, s.mockOneTime, "login.example.com", "Hackathon", 5*time.Minute),
+	)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_BeginPasskeyRegistration() {
+	s.T().Run("should issue a challenge and exclude registered passkeys", func(t *testing.T) {
+		s.mockRepo.EXPECT().GetByID(s.ctx, int64(42)).Return(&domain.User{UserID: 42, Email: "john@example.com"}, nil)
+		s.mockPasskeys.EXPECT().ListByUser(s.ctx, int64(42)).Return([]*domain.PasskeyCredential{{CredentialID: "cred-1"}}, nil)
+		s.mockOneTime.EXPECT().
+			Create(s.ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, ott *domain.OneTimeToken) error {
+				assert.Equal(t, domain.TokenPurposePasskeyRegistration, ott.Purpose)
+				assert.Equal(t, int64(42), ott.UserID)
+				return nil
+			})
+
+		out, err := s.passkeyUseCase().BeginPasskeyRegistration(s.ctx, 42)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, out.Challenge)
+		assert.Equal(t, "login.example.com", out.RPID)
+		assert.Equal(t, "NDI", out.UserHandle) // base64url("42")
+		assert.Equal(t, "john@example.com", out.UserName)
+		assert.Equal(t, []string{"cred-1"}, out.ExcludeCredentials)
+		assert.Equal(t, int64(300000), out.Timeout)
+		assert.Contains(t, out.Algorithms, domain.COSEAlgES256)
+	})
+
+	s.T().Run("should refuse when passkeys are not configured", func(t *testing.T) {
+		out, err := s.useCase.BeginPasskeyRegistration(s.ctx, 42)
+		assert.Equal(t, usecase.ErrPasskeysDisabled, err)
+		assert.Nil(t, out)
+	})
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_FinishPasskeyRegistration() {
+	const challenge = "reg-challenge"
+	sum := sha256.Sum256([]byte(challenge))
+	challengeHash := hex.EncodeToString(sum[:])
+	in := dto.FinishPasskeyRegistrationInput{UserID: 42, Name: "Laptop", ClientDataJSON: "cd", AttestationObject: "att"}
+
+	tests := []struct {
+		name        string
+		setupMocks  func()
+		expectError error
+	}{
+		{
+			name: "should store the new credential",
+			setupMocks: func() {
+				s.mockWebAuthn.EXPECT().VerifyRegistration(in).Return(challenge, &domain.PasskeyCredential{CredentialID: "cred-1", PublicKey: []byte{1}}, nil)
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, challengeHash, domain.TokenPurposePasskeyRegistration, gomock.Any()).
+					Return(&domain.OneTimeToken{UserID: 42}, nil)
+				s.mockPasskeys.EXPECT().
+					Create(s.ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, c *domain.PasskeyCredential) (bool, error) {
+						assert.Equal(s.T(), int64(42), c.UserID)
+						assert.Equal(s.T(), "Laptop", c.Name)
+						return true, nil
+					})
+			},
+		},
+		{
+			name: "should reject an invalid attestation",
+			setupMocks: func() {
+				s.mockWebAuthn.EXPECT().VerifyRegistration(in).Return("", nil, assert.AnError)
+			},
+			expectError: usecase.ErrInvalidPasskey,
+		},
+		{
+			name: "should reject a challenge issued to another user",
+			setupMocks: func() {
+				s.mockWebAuthn.EXPECT().VerifyRegistration(in).Return(challenge, &domain.PasskeyCredential{CredentialID: "cred-1"}, nil)
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, challengeHash, domain.TokenPurposePasskeyRegistration, gomock.Any()).
+					Return(&domain.OneTimeToken{UserID: 7}, nil)
+			},
+			expectError: usecase.ErrInvalidPasskey,
+		},
+		{
+			name: "should reject an unknown or expired challenge",
+			setupMocks: func() {
+				s.mockWebAuthn.EXPECT().VerifyRegistration(in).Return(challenge, &domain.PasskeyCredential{CredentialID: "cred-1"}, nil)
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, challengeHash, domain.TokenPurposePasskeyRegistration, gomock.Any()).
+					Return(nil, nil)
+			},
+			expectError: usecase.ErrInvalidPasskey,
+		},
+		{
+			name: "should refuse a credential registered before",
+			setupMocks: func() {
+				s.mockWebAuthn.EXPECT().VerifyRegistration(in).Return(challenge, &domain.PasskeyCredential{CredentialID: "cred-1"}, nil)
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, challengeHash, domain.TokenPurposePasskeyRegistration, gomock.Any()).
+					Return(&domain.OneTimeToken{UserID: 42}, nil)
+				s.mockPasskeys.EXPECT().Create(s.ctx, gomock.Any()).Return(false, nil)
+			},
+			expectError: usecase.ErrPasskeyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			out, err := s.passkeyUseCase().FinishPasskeyRegistration(s.ctx, in)
+
+			// Assert
+			if tt.expectError != nil {
+				assert.Equal(t, tt.expectError, err)
+				assert.Nil(t, out)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "cred-1", out.CredentialID)
+				assert.Equal(t, "Laptop", out.Name)
+			}
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_BeginPasskeyLogin() {
+	s.mockOneTime.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, ott *domain.OneTimeToken) error {
+			assert.Equal(s.T(), domain.TokenPurposePasskeyLogin, ott.Purpose)
+			return nil
+		})
+
+	out, err := s.passkeyUseCase().BeginPasskeyLogin(s.ctx)
+	s.NoError(err)
+	s.NotEmpty(out.Challenge)
+	s.Equal("login.example.com", out.RPID)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_FinishPasskeyLogin() {
+	const challenge = "login-challenge"
+	sum := sha256.Sum256([]byte(challenge))
+	challengeHash := hex.EncodeToString(sum[:])
+	in := dto.FinishPasskeyLoginInput{CredentialID: "cred-1", ClientDataJSON: "cd", AuthenticatorData: "ad", Signature: "sig"}
+	newCred := func(signCount uint32) *domain.PasskeyCredential {
+		return &domain.PasskeyCredential{CredentialID: "cred-1", UserID: 42, SignCount: signCount}
+	}
+
+	tests := []struct {
+		name        string
+		input       dto.FinishPasskeyLoginInput
+		setupMocks  func()
+		expectError error
+	}{
+		{
+			name:  "should issue tokens and store the new counter",
+			input: in,
+			setupMocks: func() {
+				cred := newCred(4)
+				s.mockPasskeys.EXPECT().GetByID(s.ctx, "cred-1").Return(cred, nil)
+				s.mockWebAuthn.EXPECT().VerifyAssertion(cred, in).Return(challenge, uint32(5), nil)
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, challengeHash, domain.TokenPurposePasskeyLogin, gomock.Any()).
+					Return(&domain.OneTimeToken{}, nil)
+				s.mockPasskeys.EXPECT().UpdateSignCount(s.ctx, "cred-1", uint32(5), gomock.Any()).Return(true, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(42)).Return(&domain.User{UserID: 42, MFAEnabled: true}, nil)
+				s.mockJWTSigner.EXPECT().Sign(gomock.Any()).Return("jwt-token", nil)
+			},
+		},
+		{
+			name:  "should accept authenticators without a counter",
+			input: in,
+			setupMocks: func() {
+				cred := newCred(0)
+				s.mockPasskeys.EXPECT().GetByID(s.ctx, "cred-1").Return(cred, nil)
+				s.mockWebAuthn.EXPECT().VerifyAssertion(cred, in).Return(challenge, uint32(0), nil)
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, challengeHash, domain.TokenPurposePasskeyLogin, gomock.Any()).
+					Return(&domain.OneTimeToken{}, nil)
+				s.mockPasskeys.EXPECT().UpdateSignCount(s.ctx, "cred-1", uint32(0), gomock.Any()).Return(true, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(42)).Return(&domain.User{UserID: 42}, nil)
+				s.mockJWTSigner.EXPECT().Sign(gomock.Any()).Return("jwt-token", nil)
+			},
+		},
+		{
+			name:  "should reject a counter that did not increase",
+			input: in,
+			setupMocks: func() {
+				cred := newCred(5)
+				s.mockPasskeys.EXPECT().GetByID(s.ctx, "cred-1").Return(cred, nil)
+				s.mockWebAuthn.EXPECT().VerifyAssertion(cred, in).Return(challenge, uint32(5), nil)
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, challengeHash, domain.TokenPurposePasskeyLogin, gomock.Any()).
+					Return(&domain.OneTimeToken{}, nil)
+			},
+			expectError: usecase.ErrPasskeyCloned,
+		},
+		{
+			name:  "should reject a counter a concurrent login used first",
+			input: in,
+			setupMocks: func() {
+				cred := newCred(4)
+				s.mockPasskeys.EXPECT().GetByID(s.ctx, "cred-1").Return(cred, nil)
+				s.mockWebAuthn.EXPECT().VerifyAssertion(cred, in).Return(challenge, uint32(5), nil)
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, challengeHash, domain.TokenPurposePasskeyLogin, gomock.Any()).
+					Return(&domain.OneTimeToken{}, nil)
+				s.mockPasskeys.EXPECT().UpdateSignCount(s.ctx, "cred-1", uint32(5), gomock.Any()).Return(false, nil)
+			},
+			expectError: usecase.ErrPasskeyCloned,
+		},
+		{
+			name:  "should refuse a locked account",
+			input: in,
+			setupMocks: func() {
+				cred := newCred(4)
+				s.mockPasskeys.EXPECT().GetByID(s.ctx, "cred-1").Return(cred, nil)
+				s.mockWebAuthn.EXPECT().VerifyAssertion(cred, in).Return(challenge, uint32(5), nil)
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, challengeHash, domain.TokenPurposePasskeyLogin, gomock.Any()).
+					Return(&domain.OneTimeToken{}, nil)
+				s.mockPasskeys.EXPECT().UpdateSignCount(s.ctx, "cred-1", uint32(5), gomock.Any()).Return(true, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(42)).
+					Return(&domain.User{UserID: 42, LockedUntil: time.Now().Add(time.Hour).Unix()}, nil)
+			},
+			expectError: usecase.ErrAccountLocked,
+		},
+		{
+			name:  "should reject an unknown credential",
+			input: in,
+			setupMocks: func() {
+				s.mockPasskeys.EXPECT().GetByID(s.ctx, "cred-1").Return(nil, nil)
+			},
+			expectError: usecase.ErrInvalidPasskey,
+		},
+		{
+			name:  "should reject a user handle of another account",
+			input: dto.FinishPasskeyLoginInput{CredentialID: "cred-1", ClientDataJSON: "cd", AuthenticatorData: "ad", Signature: "sig", UserHandle: "Nw"},
+			setupMocks: func() {
+				s.mockPasskeys.EXPECT().GetByID(s.ctx, "cred-1").Return(newCred(0), nil)
+			},
+			expectError: usecase.ErrInvalidPasskey,
+		},
+		{
+			name:  "should reject an invalid signature",
+			input: in,
+			setupMocks: func() {
+				cred := newCred(0)
+				s.mockPasskeys.EXPECT().GetByID(s.ctx, "cred-1").Return(cred, nil)
+				s.mockWebAuthn.EXPECT().VerifyAssertion(cred, in).Return("", uint32(0), assert.AnError)
+			},
+			expectError: usecase.ErrInvalidPasskey,
+		},
+		{
+			name:  "should reject a replayed challenge",
+			input: in,
+			setupMocks: func() {
+				cred := newCred(0)
+				s.mockPasskeys.EXPECT().GetByID(s.ctx, "cred-1").Return(cred, nil)
+				s.mockWebAuthn.EXPECT().VerifyAssertion(cred, in).Return(challenge, uint32(0), nil)
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, challengeHash, domain.TokenPurposePasskeyLogin, gomock.Any()).
+					Return(nil, nil)
+			},
+			expectError: usecase.ErrInvalidPasskey,
+		},
+		{
+			name:        "should require the assertion fields",
+			input:       dto.FinishPasskeyLoginInput{CredentialID: "cred-1"},
+			setupMocks:  func() {},
+			expectError: usecase.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			out, err := s.passkeyUseCase().FinishPasskeyLogin(s.ctx, tt.input)
+
+			// Assert
+			if tt.expectError != nil {
+				assert.Equal(t, tt.expectError, err)
+				assert.Nil(t, out)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "jwt-token", out.Token)
+			}
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_GetMe() {
+	tests := []struct {
+		name        string
+		userID      int64
+		setupMocks  func()
+		checkResult func(*testing.T, *dto.GetMeOutput, error)
+	}{
+		{
+			name:   "should get user successfully",
+			userID: 1,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().
+					GetByID(s.ctx, int64(1)).
+					Return(s.mockUsers[0], nil)
+			},
+			checkResult: func(t *testing.T, output *dto.GetMeOutput, err error) {
+				assert.NoError(t, err)
+				assert.NotNil(t, output)
+				assert.Equal(t, int64(1), output.UserID)
+				assert.Equal(t, "John Doe", output.Name)
+				assert.Equal(t, "john@example.com", output.Email)
+			},
+		},
+		{
+			name:   "should return error when userID is invalid",
+			userID: 0,
+			setupMocks: func() {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, output *dto.GetMeOutput, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, output)
+				assert.Equal(t, usecase.ErrInvalidUserID, err)
+			},
+		},
+		{
+			name:   "should return error when user not found",
+			userID: 999,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().
+					GetByID(s.ctx, int64(999)).
+					Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.GetMeOutput, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, output)
+				assert.Equal(t, usecase.ErrUserNotFound, err)
+			},
+		},
+		{
+			name:   "should return error when repository fails",
+			userID: 1,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().
+					GetByID(s.ctx, int64(1)).
+					Return(nil, assert.AnError)
+			},
+			checkResult: func(t *testing.T, output *dto.GetMeOutput, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, output)
+				assert.Equal(t, usecase.ErrUserNotFound, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			output, err := s.useCase.GetMe(s.ctx, tt.userID)
+
+			// Assert
+			tt.checkResult(t, output, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_GetUserByID() {
+	tests := []struct {
+		name        string
+		userID      int64
+		setupMocks  func()
+		checkResult func(*testing.T, *dto.GetUserByIDOutput, error)
+	}{
+		{
+			name:   "should get user by ID successfully",
+			userID: 1,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().
+					GetByID(s.ctx, int64(1)).
+					Return(s.mockUsers[0], nil)
+			},
+			checkResult: func(t *testing.T, output *dto.GetUserByIDOutput, err error) {
+				assert.NoError(t, err)
+				assert.NotNil(t, output)
+				assert.Equal(t, int64(1), output.UserID)
+				assert.Equal(t, "John Doe", output.Name)
+				assert.Equal(t, "john@example.com", output.Email)
+			},
+		},
+		{
+			name:   "should return error when userID is invalid",
+			userID: 0,
+			setupMocks: func() {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, output *dto.GetUserByIDOutput, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, output)
+				assert.Equal(t, usecase.ErrInvalidUserID, err)
+			},
+		},
+		{
+			name:   "should return error when user not found",
+			userID: 999,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().
+					GetByID(s.ctx, int64(999)).
+					Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, output *dto.GetUserByIDOutput, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, output)
+				assert.Equal(t, usecase.ErrUserNotFound, err)
+			},
+		},
+		{
+			name:   "should return error when repository fails",
+			userID: 1,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().
+					GetByID(s.ctx, int64(1)).
+					Return(nil, assert.AnError)
+			},
+			checkResult: func(t *testing.T, output *dto.GetUserByIDOutput, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, output)
+				assert.Equal(t, usecase.ErrUserNotFound, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			output, err := s.useCase.GetUserByID(s.ctx, tt.userID)
+
+			// Assert
+			tt.checkResult(t, output, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) sessionUseCase() port.UserUseCase {
+	return usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+		usecase.WithRefreshTokens(s.mockRefresh, 24*time.Hour),
+		usecase.WithSessions(s.mockSessions),
+	)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Login_StartsSession() {
+	// Arrange
+	uc := s.sessionUseCase()
+	user := &domain.User{UserID: 1, Email: "john@example.com", Password: testHashedPassword}
+	in := dto.LoginInput{
+		Email:    "john@example.com",
+		Password: "password123",
+		Client: dto.ClientInfo{
+			UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36",
+			IPAddress: "203.0.113.7",
+		},
+	}
+	var session *domain.Session
+	s.mockRepo.EXPECT().GetByEmail(s.ctx, in.Email).Return(user, nil)
+	s.expectPasswordCheck(testHashedPassword, "password123", true)
+	s.mockSessions.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, sess *domain.Session) error {
+		session = sess
+		return nil
+	})
+	s.mockJWTSigner.EXPECT().Sign(gomock.Any()).DoAndReturn(func(p domain.Principal) (string, error) {
+		assert.Equal(s.T(), session.SessionID, p.SessionID)
+		return "jwt-token", nil
+	})
+	s.mockRefresh.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, rt *domain.RefreshToken) error {
+		assert.Equal(s.T(), session.SessionID, rt.FamilyID)
+		return nil
+	})
+
+	// Act
+	out, err := uc.Login(s.ctx, in)
+
+	// Assert
+	s.NoError(err)
+	s.Equal("jwt-token", out.Token)
+	s.NotEmpty(session.SessionID)
+	s.Equal(int64(1), session.UserID)
+	s.Equal("Chrome on macOS", session.Device)
+	s.Equal(in.Client.UserAgent, session.UserAgent)
+	s.Equal("203.0.113.7", session.IPAddress)
+	s.Equal(session.CreatedAt, session.LastSeenAt)
+	s.Greater(session.ExpiresAt, session.CreatedAt)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Refresh_TouchesSession() {
+	// Arrange
+	uc := s.sessionUseCase()
+	const refreshToken = "refresh-token"
+	sum := sha256.Sum256([]byte(refreshToken))
+	refreshHash := hex.EncodeToString(sum[:])
+	s.mockRefresh.EXPECT().GetByHash(s.ctx, refreshHash).
+		Return(&domain.RefreshToken{TokenHash: refreshHash, FamilyID: "sess-1", UserID: 1, CreatedAt: time.Now().Unix(), ExpiresAt: time.Now().Add(time.Hour).Unix()}, nil)
+	s.mockRefresh.EXPECT().MarkUsed(s.ctx, refreshHash, gomock.Any()).Return(true, nil)
+	s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(s.mockUsers[0], nil)
+	s.mockSessions.EXPECT().Touch(s.ctx, "sess-1", gomock.Any(), gomock.Any()).Return(assert.AnError) // best effort
+	s.mockJWTSigner.EXPECT().
+		Sign(domain.Principal{UserID: 1, Email: "john@example.com", SessionID: "sess-1"}).
+		Return("new-jwt-token", nil)
+	s.mockRefresh.EXPECT().Create(s.ctx, gomock.Any()).Return(nil)
+
+	// Act
+	out, err := uc.Refresh(s.ctx, dto.RefreshInput{RefreshToken: refreshToken})
+
+	// Assert
+	s.NoError(err)
+	s.Equal("new-jwt-token", out.Token)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Logout_EndsSession() {
+	// Arrange
+	uc := s.sessionUseCase()
+	const refreshToken = "refresh-token"
+	sum := sha256.Sum256([]byte(refreshToken))
+	refreshHash := hex.EncodeToString(sum[:])
+	s.mockJWTSigner.EXPECT().Verify(s.ctx, "jwt-token").Return(int64(1), int64(0), nil)
+	s.mockJWTSigner.EXPECT().Revoke(s.ctx, "jwt-token").Return(nil)
+	s.mockRefresh.EXPECT().GetByHash(s.ctx, refreshHash).
+		Return(&domain.RefreshToken{TokenHash: refreshHash, FamilyID: "sess-1", UserID: 1}, nil)
+	s.mockRefresh.EXPECT().RevokeFamily(s.ctx, "sess-1").Return(nil)
+	s.mockJWTSigner.EXPECT().RevokeSession(s.ctx, "sess-1").Return(nil)
+	s.mockSessions.EXPECT().Delete(s.ctx, "sess-1").Return(nil)
+
+	// Act
+	err := uc.Logout(s.ctx, dto.LogoutInput{AccessToken: "jwt-token", RefreshToken: refreshToken})
+
+	// Assert
+	s.NoError(err)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_ListSessions() {
+	now := time.Now().Unix()
+	tests := []struct {
+		name        string
+		useCase     func() port.UserUseCase
+		input       dto.ListSessionsInput
+		setupMocks  func()
+		checkResult func(*testing.T, *dto.ListSessionsOutput, error)
+	}{
+		{
+			name:    "should list live sessions, most recent first, marking the current one",
+			useCase: s.sessionUseCase,
+			input:   dto.ListSessionsInput{UserID: 1, CurrentSessionID: "sess-2"},
+			setupMocks: func() {
+				s.mockSessions.EXPECT().ListByUser(s.ctx, int64(1)).Return([]*domain.Session{
+					{SessionID: "sess-1", UserID: 1, Device: "Firefox on Linux", LastSeenAt: now - 60, ExpiresAt: now + 3600},
+					{SessionID: "sess-2", UserID: 1, Device: "Safari on iOS", LastSeenAt: now, ExpiresAt: now + 3600},
+					{SessionID: "sess-3", UserID: 1, Device: "Chrome on Windows", LastSeenAt: now - 10, ExpiresAt: now - 1},
+				}, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.ListSessionsOutput, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, out.Sessions, 2)
+				assert.Equal(t, "sess-2", out.Sessions[0].SessionID)
+				assert.True(t, out.Sessions[0].Current)
+				assert.Equal(t, "sess-1", out.Sessions[1].SessionID)
+				assert.False(t, out.Sessions[1].Current)
+			},
+		},
+		{
+			name:    "should return an empty list when the user has no sessions",
+			useCase: s.sessionUseCase,
+			input:   dto.ListSessionsInput{UserID: 1},
+			setupMocks: func() {
+				s.mockSessions.EXPECT().ListByUser(s.ctx, int64(1)).Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.ListSessionsOutput, err error) {
+				assert.NoError(t, err)
+				assert.NotNil(t, out.Sessions)
+				assert.Empty(t, out.Sessions)
+			},
+		},
+		{
+			name:       "should return error when sessions are disabled",
+			useCase:    func() port.UserUseCase { return s.useCase },
+			input:      dto.ListSessionsInput{UserID: 1},
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, out *dto.ListSessionsOutput, err error) {
+				assert.Nil(t, out)
+				assert.Equal(t, usecase.ErrSessionsDisabled, err)
+			},
+		},
+		{
+			name:    "should return repository errors",
+			useCase: s.sessionUseCase,
+			input:   dto.ListSessionsInput{UserID: 1},
+			setupMocks: func() {
+				s.mockSessions.EXPECT().ListByUser(s.ctx, int64(1)).Return(nil, assert.AnError)
+			},
+			checkResult: func(t *testing.T, out *dto.ListSessionsOutput, err error) {
+				assert.Nil(t, out)
+				assert.Equal(t, assert.AnError, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			out, err := tt.useCase().ListSessions(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, out, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_RevokeSession() {
+	tests := []struct {
+		name        string
+		input       dto.RevokeSessionInput
+		setupMocks  func()
+		checkResult func(*testing.T, error)
+	}{
+		{
+			name:  "should revoke the session's refresh and access tokens and delete it",
+			input: dto.RevokeSessionInput{UserID: 1, SessionID: "sess-1"},
+			setupMocks: func() {
+				s.mockSessions.EXPECT().GetByID(s.ctx, "sess-1").Return(&domain.Session{SessionID: "sess-1", UserID: 1}, nil)
+				s.mockRefresh.EXPECT().RevokeFamily(s.ctx, "sess-1").Return(nil)
+				s.mockJWTSigner.EXPECT().RevokeSession(s.ctx, "sess-1").Return(nil)
+				s.mockSessions.EXPECT().Delete(s.ctx, "sess-1").Return(nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "should not reveal sessions of other users",
+			input: dto.RevokeSessionInput{UserID: 1, SessionID: "sess-2"},
+			setupMocks: func() {
+				s.mockSessions.EXPECT().GetByID(s.ctx, "sess-2").Return(&domain.Session{SessionID: "sess-2", UserID: 2}, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrSessionNotFound, err)
+			},
+		},
+		{
+			name:  "should return error when the session does not exist",
+			input: dto.RevokeSessionInput{UserID: 1, SessionID: "missing"},
+			setupMocks: func() {
+				s.mockSessions.EXPECT().GetByID(s.ctx, "missing").Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrSessionNotFound, err)
+			},
+		},
+		{
+			name:       "should return error when session ID is missing",
+			input:      dto.RevokeSessionInput{UserID: 1},
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+			},
+		},
+		{
+			name:  "should keep the session when its tokens cannot be revoked",
+			input: dto.RevokeSessionInput{UserID: 1, SessionID: "sess-1"},
+			setupMocks: func() {
+				s.mockSessions.EXPECT().GetByID(s.ctx, "sess-1").Return(&domain.Session{SessionID: "sess-1", UserID: 1}, nil)
+				s.mockRefresh.EXPECT().RevokeFamily(s.ctx, "sess-1").Return(nil)
+				s.mockJWTSigner.EXPECT().RevokeSession(s.ctx, "sess-1").Return(assert.AnError)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, assert.AnError, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			uc := s.sessionUseCase()
+			tt.setupMocks()
+
+			// Act
+			err := uc.RevokeSession(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_ChangePassword() {
+	newUser := func() *domain.User {
+		return &domain.User{UserID: 1, Name: "John Doe", Email: "john@example.com", Password: testHashedPassword, FailedLogins: 2}
+	}
+	valid := dto.ChangePasswordInput{UserID: 1, CurrentPassword: "password123", NewPassword: "correct-horse-battery"}
+
+	tests := []struct {
+		name        string
+		useCase     func() port.UserUseCase
+		input       dto.ChangePasswordInput
+		setupMocks  func()
+		checkResult func(*testing.T, error)
+	}{
+		{
+			name:    "should store the new hash and invalidate earlier tokens",
+			useCase: func() port.UserUseCase { return s.useCase },
+			input:   valid,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "password123").Return(true, nil)
+				s.mockHasher.EXPECT().Hash("correct-horse-battery").Return("new-hash", nil)
+				before := time.Now().Unix()
+				s.mockRepo.EXPECT().SetPassword(s.ctx, int64(1), "new-hash", gomock.Any()).DoAndReturn(func(_ context.Context, _ int64, _ string, now int64) error {
+					assert.GreaterOrEqual(s.T(), now, before)
+					return nil
+				})
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:    "should delete the user's sessions",
+			useCase: s.sessionUseCase,
+			input:   valid,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "password123").Return(true, nil)
+				s.mockHasher.EXPECT().Hash("correct-horse-battery").Return("new-hash", nil)
+				s.mockRepo.EXPECT().SetPassword(s.ctx, int64(1), "new-hash", gomock.Any()).Return(nil)
+				s.mockSessions.EXPECT().ListByUser(s.ctx, int64(1)).
+					Return([]*domain.Session{{SessionID: "sess-1", UserID: 1}, {SessionID: "sess-2", UserID: 1}}, nil)
+				s.mockSessions.EXPECT().Delete(s.ctx, "sess-1").Return(nil)
+				s.mockSessions.EXPECT().Delete(s.ctx, "sess-2").Return(nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:    "should delete the user's personal access tokens",
+			useCase: s.personalAccessTokenUseCase,
+			input:   valid,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "password123").Return(true, nil)
+				s.mockHasher.EXPECT().Hash("correct-horse-battery").Return("new-hash", nil)
+				s.mockRepo.EXPECT().SetPassword(s.ctx, int64(1), "new-hash", gomock.Any()).Return(nil)
+				s.mockPATs.EXPECT().ListByUser(s.ctx, int64(1)).
+					Return([]*domain.PersonalAccessToken{{TokenHash: "hash-1", TokenID: "pat-1", UserID: 1}}, nil)
+				s.mockPATs.EXPECT().Delete(s.ctx, "hash-1").Return(nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:    "should reject a wrong current password",
+			useCase: func() port.UserUseCase { return s.useCase },
+			input:   dto.ChangePasswordInput{UserID: 1, CurrentPassword: "wrong", NewPassword: "correct-horse-battery"},
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "wrong").Return(false, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidCredentials, err)
+			},
+		},
+		{
+			name:    "should count a wrong current password towards the lockout",
+			useCase: s.lockingUseCase,
+			input:   dto.ChangePasswordInput{UserID: 1, CurrentPassword: "wrong", NewPassword: "correct-horse-battery"},
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "wrong").Return(false, nil)
+				s.mockRepo.EXPECT().RecordLoginFailure(s.ctx, int64(1)).Return(3, nil)
+				s.mockRepo.EXPECT().LockUntil(s.ctx, int64(1), gomock.Any()).Return(nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrAccountLocked, err)
+			},
+		},
+		{
+			name:    "should refuse a locked account",
+			useCase: func() port.UserUseCase { return s.useCase },
+			input:   valid,
+			setupMocks: func() {
+				user := newUser()
+				user.LockedUntil = time.Now().Add(time.Minute).Unix()
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(user, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrAccountLocked, err)
+			},
+		},
+		{
+			name:    "should apply the password policy",
+			useCase: s.policyUseCase,
+			input:   dto.ChangePasswordInput{UserID: 1, CurrentPassword: "password123", NewPassword: "johndoe-2024"},
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "password123").Return(true, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, usecase.ErrWeakPassword)
+			},
+		},
+		{
+			name:    "should return error when the user does not exist",
+			useCase: func() port.UserUseCase { return s.useCase },
+			input:   valid,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrUserNotFound, err)
+			},
+		},
+		{
+			name:       "should return error when a password is missing",
+			useCase:    func() port.UserUseCase { return s.useCase },
+			input:      dto.ChangePasswordInput{UserID: 1, NewPassword: "correct-horse-battery"},
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			err := tt.useCase().ChangePassword(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Authenticate() {
+	user := &domain.User{UserID: 1, Email: "john@example.com", TokensValidAfter: 1000}
+
+	tests := []struct {
+		name        string
+		token       string
+		setupMocks  func()
+		checkResult func(*testing.T, *domain.Principal, error)
+	}{
+		{
+			name:  "should accept a token issued after the last password change",
+			token: "new-token",
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().VerifyPrincipal(s.ctx, "new-token").Return(&domain.Principal{UserID: 1, IssuedAt: 1001}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(user, nil)
+			},
+			checkResult: func(t *testing.T, p *domain.Principal, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), p.UserID)
+			},
+		},
+		{
+			name:  "should reject a token issued in the same second as the last password change",
+			token: "same-second-token",
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().VerifyPrincipal(s.ctx, "same-second-token").Return(&domain.Principal{UserID: 1, IssuedAt: 1000}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(user, nil)
+			},
+			checkResult: func(t *testing.T, p *domain.Principal, err error) {
+				assert.Nil(t, p)
+				assert.Equal(t, usecase.ErrInvalidToken, err)
+			},
+		},
+		{
+			name:  "should reject a token issued before the last password change",
+			token: "old-token",
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().VerifyPrincipal(s.ctx, "old-token").Return(&domain.Principal{UserID: 1, IssuedAt: 999}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(user, nil)
+			},
+			checkResult: func(t *testing.T, p *domain.Principal, err error) {
+				assert.Nil(t, p)
+				assert.Equal(t, usecase.ErrInvalidToken, err)
+			},
+		},
+		{
+			name:  "should reject a token of a deleted user",
+			token: "orphan-token",
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().VerifyPrincipal(s.ctx, "orphan-token").Return(&domain.Principal{UserID: 9, IssuedAt: 1000}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(9)).Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, p *domain.Principal, err error) {
+				assert.Nil(t, p)
+				assert.Equal(t, usecase.ErrInvalidToken, err)
+			},
+		},
+		{
+			name:  "should accept client tokens without a user lookup",
+			token: "client-token",
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().VerifyPrincipal(s.ctx, "client-token").
+					Return(&domain.Principal{SubjectType: domain.SubjectTypeClient, ClientID: "video-worker"}, nil)
+			},
+			checkResult: func(t *testing.T, p *domain.Principal, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "video-worker", p.ClientID)
+			},
+		},
+		{
+			name:  "should wrap signer failures as invalid token",
+			token: "bad-token",
+			setupMocks: func() {
+				s.mockJWTSigner.EXPECT().VerifyPrincipal(s.ctx, "bad-token").Return(nil, assert.AnError)
+			},
+			checkResult: func(t *testing.T, p *domain.Principal, err error) {
+				assert.Nil(t, p)
+				assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			p, err := s.useCase.Authenticate(s.ctx, tt.token)
+
+			// Assert
+			tt.checkResult(t, p, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Refresh_RejectsTokensFromBeforePasswordChange() {
+	// Arrange
+	const refreshToken = "refresh-token"
+	sum := sha256.Sum256([]byte(refreshToken))
+	refreshHash := hex.EncodeToString(sum[:])
+	now := time.Now().Unix()
+	s.mockRefresh.EXPECT().GetByHash(s.ctx, refreshHash).
+		Return(&domain.RefreshToken{TokenHash: refreshHash, FamilyID: "fam-1", UserID: 1, CreatedAt: now - 60, ExpiresAt: now + 3600}, nil)
+	s.mockRefresh.EXPECT().MarkUsed(s.ctx, refreshHash, gomock.Any()).Return(true, nil)
+	s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(&domain.User{UserID: 1, TokensValidAfter: now - 10}, nil)
+
+	// Act
+	out, err := s.useCase.Refresh(s.ctx, dto.RefreshInput{RefreshToken: refreshToken})
+
+	// Assert
+	s.Nil(out)
+	s.Equal(usecase.ErrInvalidRefreshToken, err)
+}
+
+func (s *UserUsecaseSuiteTest) emailChangeUseCase() port.UserUseCase {
+	return usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+		usecase.WithNotifier(s.mockNotifier),
+		usecase.WithEmailChange(s.mockOneTime, time.Hour),
+	)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_RequestEmailChange() {
+	newUser := func() *domain.User {
+		return &domain.User{UserID: 1, Name: "John Doe", Email: "john@example.com", Password: testHashedPassword}
+	}
+	valid := dto.ChangeEmailInput{UserID: 1, NewEmail: "john@new.example.com", Password: "password123"}
+
+	tests := []struct {
+		name        string
+		useCase     func() port.UserUseCase
+		input       dto.ChangeEmailInput
+		setupMocks  func()
+		checkResult func(*testing.T, error)
+	}{
+		{
+			name:    "should store the pending email and notify both addresses",
+			useCase: s.emailChangeUseCase,
+			input:   valid,
+			setupMocks: func() {
+				var tokenHash string
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "password123").Return(true, nil)
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@new.example.com").Return(nil, nil)
+				s.mockRepo.EXPECT().SetPendingEmail(s.ctx, int64(1), "john@example.com", "john@new.example.com", gomock.Any()).Return(true, nil)
+				s.mockOneTime.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, t *domain.OneTimeToken) error {
+					assert.Equal(s.T(), domain.TokenPurposeEmailChange, t.Purpose)
+					assert.Equal(s.T(), "john@new.example.com", t.Email)
+					tokenHash = t.TokenHash
+					return nil
+				})
+				gomock.InOrder(
+					s.mockNotifier.EXPECT().Notify(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n domain.Notification) error {
+						assert.Equal(s.T(), domain.NotificationEmailChange, n.Type)
+						assert.Equal(s.T(), "john@new.example.com", n.To)
+						sum := sha256.Sum256([]byte(n.Token))
+						assert.Equal(s.T(), tokenHash, hex.EncodeToString(sum[:]))
+						return nil
+					}),
+					s.mockNotifier.EXPECT().Notify(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n domain.Notification) error {
+						assert.Equal(s.T(), domain.NotificationEmailChangeNotice, n.Type)
+						assert.Equal(s.T(), "john@example.com", n.To)
+						assert.Empty(s.T(), n.Token)
+						return nil
+					}),
+				)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:    "should refuse when the email changed concurrently",
+			useCase: s.emailChangeUseCase,
+			input:   valid,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "password123").Return(true, nil)
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@new.example.com").Return(nil, nil)
+				s.mockRepo.EXPECT().SetPendingEmail(s.ctx, int64(1), "john@example.com", "john@new.example.com", gomock.Any()).Return(false, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrEmailChangeConflict, err)
+			},
+		},
+		{
+			name:    "should reject an email taken by another account",
+			useCase: s.emailChangeUseCase,
+			input:   valid,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "password123").Return(true, nil)
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@new.example.com").Return(&domain.User{UserID: 2}, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrEmailAlreadyExists, err)
+			},
+		},
+		{
+			name: "should not reveal a taken email in enumeration-safe mode",
+			useCase: func() port.UserUseCase {
+				return usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+					usecase.WithNotifier(s.mockNotifier),
+					usecase.WithEmailChange(s.mockOneTime, time.Hour),
+					usecase.WithEnumerationSafeRegistration(),
+				)
+			},
+			input: valid,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "password123").Return(true, nil)
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@new.example.com").Return(&domain.User{UserID: 2}, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:    "should reject a wrong password",
+			useCase: s.emailChangeUseCase,
+			input:   dto.ChangeEmailInput{UserID: 1, NewEmail: "john@new.example.com", Password: "wrong"},
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "wrong").Return(false, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidCredentials, err)
+			},
+		},
+		{
+			name:    "should reject the current email",
+			useCase: s.emailChangeUseCase,
+			input:   dto.ChangeEmailInput{UserID: 1, NewEmail: "john@example.com", Password: "password123"},
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(newUser(), nil)
+				s.mockHasher.EXPECT().Verify(testHashedPassword, "password123").Return(true, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+			},
+		},
+		{
+			name:       "should return error when email change is disabled",
+			useCase:    func() port.UserUseCase { return s.useCase },
+			input:      valid,
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrEmailChangeDisabled, err)
+			},
+		},
+		{
+			name:       "should return error when input is incomplete",
+			useCase:    s.emailChangeUseCase,
+			input:      dto.ChangeEmailInput{UserID: 1, NewEmail: "john@new.example.com"},
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			err := tt.useCase().RequestEmailChange(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_ConfirmEmailChange() {
+	const changeToken = "change-token"
+	sum := sha256.Sum256([]byte(changeToken))
+	changeHash := hex.EncodeToString(sum[:])
+	pending := func() *domain.User {
+		return &domain.User{UserID: 1, Email: "john@example.com", PendingEmail: "john@new.example.com"}
+	}
+	expectConsume := func() {
+		s.mockOneTime.EXPECT().
+			Consume(s.ctx, changeHash, domain.TokenPurposeEmailChange, gomock.Any()).
+			Return(&domain.OneTimeToken{TokenHash: changeHash, Purpose: domain.TokenPurposeEmailChange, UserID: 1, Email: "john@new.example.com"}, nil)
+	}
+
+	tests := []struct {
+		name        string
+		setupMocks  func()
+		checkResult func(*testing.T, error)
+	}{
+		{
+			name: "should swap the email",
+			setupMocks: func() {
+				expectConsume()
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(pending(), nil)
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@new.example.com").Return(nil, nil)
+				s.mockRepo.EXPECT().ChangeEmail(s.ctx, int64(1), "john@new.example.com", gomock.Any()).Return(true, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "should reject a token superseded by a newer request",
+			setupMocks: func() {
+				expectConsume()
+				user := pending()
+				user.PendingEmail = "john@other.example.com"
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(user, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidEmailChangeToken, err)
+			},
+		},
+		{
+			name: "should refuse an email another account registered meanwhile",
+			setupMocks: func() {
+				expectConsume()
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(pending(), nil)
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@new.example.com").Return(&domain.User{UserID: 2}, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrEmailAlreadyExists, err)
+			},
+		},
+		{
+			name: "should refuse when the email claim is taken",
+			setupMocks: func() {
+				expectConsume()
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(pending(), nil)
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@new.example.com").Return(nil, nil)
+				s.mockRepo.EXPECT().ChangeEmail(s.ctx, int64(1), "john@new.example.com", gomock.Any()).Return(false, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrEmailAlreadyExists, err)
+			},
+		},
+		{
+			name: "should reject an unknown, used or expired token",
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, changeHash, domain.TokenPurposeEmailChange, gomock.Any()).
+					Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidEmailChangeToken, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			err := s.emailChangeUseCase().ConfirmEmailChange(s.ctx, dto.ConfirmEmailChangeInput{Token: changeToken})
+
+			// Assert
+			tt.checkResult(t, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) magicLinkUseCase() port.UserUseCase {
+	return usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+		usecase.WithNotifier(s.mockNotifier),
+		usecase.WithMagicLinks(s.mockOneTime, 15*time.Minute, "https://app.example.com/login/magic?lang=en"),
+		usecase.WithTOTP(s.mockCipher, s.mockOneTime, "hackathon", 5*time.Minute),
+	)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_RequestMagicLink() {
+	tests := []struct {
+		name        string
+		input       dto.MagicLinkInput
+		setupMocks  func()
+		checkResult func(*testing.T, error)
+	}{
+		{
+			name:  "should store a hashed login token and send the token to the user",
+			input: dto.MagicLinkInput{Email: "john@example.com"},
+			setupMocks: func() {
+				var stored *domain.OneTimeToken
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(s.mockUsers[0], nil)
+				s.mockOneTime.EXPECT().
+					Create(s.ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, t *domain.OneTimeToken) error {
+						stored = t
+						return nil
+					})
+				s.mockNotifier.EXPECT().
+					Notify(s.ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, n domain.Notification) error {
+						assert.Equal(s.T(), domain.NotificationMagicLink, n.Type)
+						assert.Equal(s.T(), "john@example.com", n.To)
+						assert.Equal(s.T(), "https://app.example.com/login/magic?lang=en&token="+url.QueryEscape(n.Token), n.Link)
+						sum := sha256.Sum256([]byte(n.Token))
+						assert.Equal(s.T(), hex.EncodeToString(sum[:]), stored.TokenHash)
+						assert.Equal(s.T(), domain.TokenPurposeMagicLink, stored.Purpose)
+						assert.InDelta(s.T(), time.Now().Add(15*time.Minute).Unix(), stored.ExpiresAt, 5)
+						return nil
+					})
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "should succeed silently for an unknown email",
+			input: dto.MagicLinkInput{Email: "nobody@example.com"},
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "nobody@example.com").Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "should return error when email is missing",
+			input: dto.MagicLinkInput{},
+			setupMocks: func() {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			err := s.magicLinkUseCase().RequestMagicLink(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, err)
+		})
+	}
+
+	s.T().Run("should refuse when magic links are not enabled", func(t *testing.T) {
+		err := s.useCase.RequestMagicLink(s.ctx, dto.MagicLinkInput{Email: "john@example.com"})
+		assert.Equal(t, usecase.ErrMagicLinkDisabled, err)
+	})
+
+	s.T().Run("should refuse without a page for the links", func(t *testing.T) {
+		uc := usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+			usecase.WithNotifier(s.mockNotifier),
+			usecase.WithMagicLinks(s.mockOneTime, 15*time.Minute, ""),
+		)
+		err := uc.RequestMagicLink(s.ctx, dto.MagicLinkInput{Email: "john@example.com"})
+		assert.Equal(t, usecase.ErrMagicLinkDisabled, err)
+	})
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_ConsumeMagicLink() {
+	const linkToken = "link-token"
+	sum := sha256.Sum256([]byte(linkToken))
+	linkHash := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name        string
+		input       dto.ConsumeMagicLinkInput
+		setupMocks  func()
+		checkResult func(*testing.T, *dto.LoginOutput, error)
+	}{
+		{
+			name:  "should issue the same access token as a password login",
+			input: dto.ConsumeMagicLinkInput{Token: linkToken},
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, linkHash, domain.TokenPurposeMagicLink, gomock.Any()).
+					Return(&domain.OneTimeToken{UserID: 1}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).
+					Return(&domain.User{UserID: 1, Email: "john@example.com", EmailVerified: true}, nil)
+				s.mockJWTSigner.EXPECT().
+					Sign(domain.Principal{UserID: 1, Email: "john@example.com"}).
+					Return("jwt-token", nil)
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "jwt-token", out.Token)
+			},
+		},
+		{
+			name:  "should verify the email of an unverified account",
+			input: dto.ConsumeMagicLinkInput{Token: linkToken},
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, linkHash, domain.TokenPurposeMagicLink, gomock.Any()).
+					Return(&domain.OneTimeToken{UserID: 1}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).
+					Return(&domain.User{UserID: 1, Email: "john@example.com"}, nil)
+				s.mockRepo.EXPECT().MarkEmailVerified(s.ctx, int64(1), gomock.Any()).Return(nil)
+				s.mockJWTSigner.EXPECT().Sign(gomock.Any()).Return("jwt-token", nil)
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "jwt-token", out.Token)
+			},
+		},
+		{
+			name:  "should answer with an MFA challenge for accounts with MFA",
+			input: dto.ConsumeMagicLinkInput{Token: linkToken},
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, linkHash, domain.TokenPurposeMagicLink, gomock.Any()).
+					Return(&domain.OneTimeToken{UserID: 1}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).
+					Return(&domain.User{UserID: 1, EmailVerified: true, MFAEnabled: true, TOTPSecret: "encrypted"}, nil)
+				s.mockOneTime.EXPECT().
+					Create(s.ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, ott *domain.OneTimeToken) error {
+						assert.Equal(s.T(), domain.TokenPurposeMFAChallenge, ott.Purpose)
+						return nil
+					})
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.NoError(t, err)
+				assert.True(t, out.MFARequired)
+				assert.Empty(t, out.Token)
+			},
+		},
+		{
+			name:  "should refuse a locked account",
+			input: dto.ConsumeMagicLinkInput{Token: linkToken},
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, linkHash, domain.TokenPurposeMagicLink, gomock.Any()).
+					Return(&domain.OneTimeToken{UserID: 1}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).
+					Return(&domain.User{UserID: 1, LockedUntil: time.Now().Add(time.Hour).Unix()}, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.Equal(t, usecase.ErrAccountLocked, err)
+				assert.Nil(t, out)
+			},
+		},
+		{
+			name:  "should reject an unknown, used or expired token",
+			input: dto.ConsumeMagicLinkInput{Token: linkToken},
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, linkHash, domain.TokenPurposeMagicLink, gomock.Any()).
+					Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidMagicLink, err)
+				assert.Nil(t, out)
+			},
+		},
+		{
+			name:  "should return error when token is missing",
+			input: dto.ConsumeMagicLinkInput{},
+			setupMocks: func() {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+				assert.Nil(t, out)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			out, err := s.magicLinkUseCase().ConsumeMagicLink(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, out, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) federatedUseCase() port.UserUseCase {
+	return usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+		usecase.WithFederatedLogin(map[string]port.IdentityProvider{"google": s.mockIdP}, s.mockFederated, s.mockOneTime, 10*time.Minute),
+		usecase.WithTOTP(s.mockCipher, s.mockOneTime, "hackathon", 5*time.Minute),
+	)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_BeginFederatedLogin() {
+	s.T().Run("should store the login state and send the user to the provider", func(t *testing.T) {
+		// Arrange
+		var stored *domain.OneTimeToken
+		s.mockOneTime.EXPECT().
+			Create(s.ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, ott *domain.OneTimeToken) error {
+				stored = ott
+				return nil
+			})
+		s.mockIdP.EXPECT().
+			AuthorizationURL(s.ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, state, nonce, challenge string) (string, error) {
+				sum := sha256.Sum256([]byte(state))
+				assert.Equal(t, hex.EncodeToString(sum[:]), stored.TokenHash)
+				assert.Equal(t, stored.Nonce, nonce)
+				verifier := sha256.Sum256([]byte(stored.CodeVerifier))
+				assert.Equal(t, base64.RawURLEncoding.EncodeToString(verifier[:]), challenge)
+				return "https://idp.example.com/authorize?state=" + state, nil
+			})
+
+		// Act
+		out, err := s.federatedUseCase().BeginFederatedLogin(s.ctx, dto.BeginFederatedLoginInput{Provider: "google"})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "https://idp.example.com/authorize?state="+out.State, out.AuthorizationURL)
+		assert.Equal(t, domain.TokenPurposeFederatedLogin, stored.Purpose)
+		assert.Equal(t, "google", stored.Provider)
+		assert.NotEmpty(t, stored.Nonce)
+		assert.NotEqual(t, out.State, stored.CodeVerifier)
+		assert.InDelta(t, time.Now().Add(10*time.Minute).Unix(), stored.ExpiresAt, 5)
+	})
+
+	s.T().Run("should reject an unknown provider", func(t *testing.T) {
+		out, err := s.federatedUseCase().BeginFederatedLogin(s.ctx, dto.BeginFederatedLoginInput{Provider: "github"})
+		assert.Equal(t, usecase.ErrUnknownIdentityProvider, err)
+		assert.Nil(t, out)
+	})
+
+	s.T().Run("should refuse when federated login is not enabled", func(t *testing.T) {
+		out, err := s.useCase.BeginFederatedLogin(s.ctx, dto.BeginFederatedLoginInput{Provider: "google"})
+		assert.Equal(t, usecase.ErrFederatedLoginDisabled, err)
+		assert.Nil(t, out)
+	})
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_FinishFederatedLogin() {
+	const state = "login-state"
+	sum := sha256.Sum256([]byte(state))
+	stateHash := hex.EncodeToString(sum[:])
+	loginState := &domain.OneTimeToken{Provider: "google", Nonce: "nonce", CodeVerifier: "verifier"}
+	input := dto.FinishFederatedLoginInput{Provider: "google", Code: "code", State: state}
+	identity := func() *domain.ExternalIdentity {
+		return &domain.ExternalIdentity{Subject: "g-123", Email: "john@example.com", EmailVerified: true, Name: "John Doe"}
+	}
+
+	tests := []struct {
+		name        string
+		input       dto.FinishFederatedLoginInput
+		setupMocks  func()
+		checkResult func(*testing.T, *dto.LoginOutput, error)
+	}{
+		{
+			name:  "should sign in the user an identity is linked to",
+			input: input,
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, stateHash, domain.TokenPurposeFederatedLogin, gomock.Any()).
+					Return(loginState, nil)
+				s.mockIdP.EXPECT().Exchange(s.ctx, "code", "verifier", "nonce").Return(identity(), nil)
+				s.mockFederated.EXPECT().Get(s.ctx, "google", "g-123").
+					Return(&domain.FederatedIdentity{Provider: "google", Subject: "g-123", UserID: 2}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(2)).
+					Return(&domain.User{UserID: 2, Email: "jane@example.com", EmailVerified: true}, nil)
+				s.mockJWTSigner.EXPECT().
+					Sign(domain.Principal{UserID: 2, Email: "jane@example.com"}).
+					Return("jwt-token", nil)
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "jwt-token", out.Token)
+			},
+		},
+		{
+			name:  "should link a new identity to the account with the same verified email",
+			input: input,
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, stateHash, domain.TokenPurposeFederatedLogin, gomock.Any()).
+					Return(loginState, nil)
+				s.mockIdP.EXPECT().Exchange(s.ctx, "code", "verifier", "nonce").Return(identity(), nil)
+				s.mockFederated.EXPECT().Get(s.ctx, "google", "g-123").Return(nil, nil)
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").
+					Return(&domain.User{UserID: 1, Email: "john@example.com", EmailVerified: true}, nil)
+				s.mockFederated.EXPECT().
+					Create(s.ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, f *domain.FederatedIdentity) (bool, error) {
+						assert.Equal(s.T(), "google", f.Provider)
+						assert.Equal(s.T(), "g-123", f.Subject)
+						assert.Equal(s.T(), int64(1), f.UserID)
+						assert.Equal(s.T(), "john@example.com", f.Email)
+						return true, nil
+					})
+				s.mockJWTSigner.EXPECT().
+					Sign(domain.Principal{UserID: 1, Email: "john@example.com"}).
+					Return("jwt-token", nil)
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "jwt-token", out.Token)
+			},
+		},
+		{
+			name:  "should create a verified account without a password for a new email",
+			input: input,
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, stateHash, domain.TokenPurposeFederatedLogin, gomock.Any()).
+					Return(loginState, nil)
+				s.mockIdP.EXPECT().Exchange(s.ctx, "code", "verifier", "nonce").Return(identity(), nil)
+				s.mockFederated.EXPECT().Get(s.ctx, "google", "g-123").Return(nil, nil)
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").Return(nil, nil)
+				s.mockRepo.EXPECT().
+					Create(s.ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, u *domain.User) error {
+						assert.Equal(s.T(), "John Doe", u.Name)
+						assert.Equal(s.T(), "john@example.com", u.Email)
+						assert.True(s.T(), u.EmailVerified)
+						assert.Empty(s.T(), u.Password)
+						u.UserID = 7
+						return nil
+					})
+				s.mockFederated.EXPECT().
+					Create(s.ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, f *domain.FederatedIdentity) (bool, error) {
+						assert.Equal(s.T(), int64(7), f.UserID)
+						return true, nil
+					})
+				s.mockJWTSigner.EXPECT().
+					Sign(domain.Principal{UserID: 7, Email: "john@example.com"}).
+					Return("jwt-token", nil)
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "jwt-token", out.Token)
+			},
+		},
+		{
+			name:  "should not link to an account whose email was never verified",
+			input: input,
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, stateHash, domain.TokenPurposeFederatedLogin, gomock.Any()).
+					Return(loginState, nil)
+				s.mockIdP.EXPECT().Exchange(s.ctx, "code", "verifier", "nonce").Return(identity(), nil)
+				s.mockFederated.EXPECT().Get(s.ctx, "google", "g-123").Return(nil, nil)
+				s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").
+					Return(&domain.User{UserID: 1, Email: "john@example.com"}, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.Equal(t, usecase.ErrFederatedAccountConflict, err)
+				assert.Nil(t, out)
+			},
+		},
+		{
+			name:  "should not link by an email the provider did not verify",
+			input: input,
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, stateHash, domain.TokenPurposeFederatedLogin, gomock.Any()).
+					Return(loginState, nil)
+				unverified := identity()
+				unverified.EmailVerified = false
+				s.mockIdP.EXPECT().Exchange(s.ctx, "code", "verifier", "nonce").Return(unverified, nil)
+				s.mockFederated.EXPECT().Get(s.ctx, "google", "g-123").Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.Equal(t, usecase.ErrFederatedEmailNotVerified, err)
+				assert.Nil(t, out)
+			},
+		},
+		{
+			name:  "should answer with an MFA challenge for accounts with MFA",
+			input: input,
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, stateHash, domain.TokenPurposeFederatedLogin, gomock.Any()).
+					Return(loginState, nil)
+				s.mockIdP.EXPECT().Exchange(s.ctx, "code", "verifier", "nonce").Return(identity(), nil)
+				s.mockFederated.EXPECT().Get(s.ctx, "google", "g-123").
+					Return(&domain.FederatedIdentity{UserID: 1}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).
+					Return(&domain.User{UserID: 1, EmailVerified: true, MFAEnabled: true, TOTPSecret: "encrypted"}, nil)
+				s.mockOneTime.EXPECT().
+					Create(s.ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, ott *domain.OneTimeToken) error {
+						assert.Equal(s.T(), domain.TokenPurposeMFAChallenge, ott.Purpose)
+						return nil
+					})
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.NoError(t, err)
+				assert.True(t, out.MFARequired)
+				assert.Empty(t, out.Token)
+			},
+		},
+		{
+			name:  "should reject an ID token the provider check refused",
+			input: input,
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, stateHash, domain.TokenPurposeFederatedLogin, gomock.Any()).
+					Return(loginState, nil)
+				s.mockIdP.EXPECT().Exchange(s.ctx, "code", "verifier", "nonce").Return(nil, assert.AnError)
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.ErrorIs(t, err, usecase.ErrInvalidFederatedLogin)
+				assert.Nil(t, out)
+			},
+		},
+		{
+			name:  "should reject a state issued for another provider",
+			input: input,
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, stateHash, domain.TokenPurposeFederatedLogin, gomock.Any()).
+					Return(&domain.OneTimeToken{Provider: "corporate"}, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidFederatedLogin, err)
+				assert.Nil(t, out)
+			},
+		},
+		{
+			name:  "should reject an unknown, used or expired state",
+			input: input,
+			setupMocks: func() {
+				s.mockOneTime.EXPECT().
+					Consume(s.ctx, stateHash, domain.TokenPurposeFederatedLogin, gomock.Any()).
+					Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidFederatedLogin, err)
+				assert.Nil(t, out)
+			},
+		},
+		{
+			name:  "should return error when code or state is missing",
+			input: dto.FinishFederatedLoginInput{Provider: "google", State: state},
+			setupMocks: func() {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, out *dto.LoginOutput, err error) {
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+				assert.Nil(t, out)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			out, err := s.federatedUseCase().FinishFederatedLogin(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, out, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Login_AccountWithoutPassword() {
+	// Arrange
+	s.mockRepo.EXPECT().GetByEmail(s.ctx, "john@example.com").
+		Return(&domain.User{UserID: 1, Email: "john@example.com", EmailVerified: true}, nil)
+	s.mockHasher.EXPECT().Hash(gomock.Any()).Return("$2a$10$dummy", nil)
+	s.mockHasher.EXPECT().Verify("$2a$10$dummy", "password123").Return(false, nil)
+
+	// Act
+	out, err := s.useCase.Login(s.ctx, dto.LoginInput{Email: "john@example.com", Password: "password123"})
+
+	// Assert
+	assert.Equal(s.T(), usecase.ErrInvalidCredentials, err)
+	assert.Nil(s.T(), out)
+}
+
+func (s *UserUsecaseSuiteTest) personalAccessTokenUseCase() port.UserUseCase {
+	return usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+		usecase.WithPersonalAccessTokens(s.mockPATs, 0),
+	)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_CreatePersonalAccessToken() {
+	tests := []struct {
+		name        string
+		useCase     func() port.UserUseCase
+		input       dto.CreatePersonalAccessTokenInput
+		setupMocks  func()
+		checkResult func(*testing.T, *dto.CreatePersonalAccessTokenOutput, error)
+	}{
+		{
+			name:    "should store only the hash of a token with every scope",
+			useCase: s.personalAccessTokenUseCase,
+			input:   dto.CreatePersonalAccessTokenInput{UserID: 1, Name: "  laptop cli "},
+			setupMocks: func() {
+				s.mockPATs.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, t *domain.PersonalAccessToken) error {
+					assert.Equal(s.T(), int64(1), t.UserID)
+					assert.Equal(s.T(), "laptop cli", t.Name)
+					assert.Equal(s.T(), []string{domain.ScopeUsersRead, domain.ScopeUsersWrite}, t.Scopes)
+					assert.Zero(s.T(), t.ExpiresAt)
+					assert.NotEmpty(s.T(), t.TokenID)
+					assert.Len(s.T(), t.TokenHash, 64)
+					return nil
+				})
+			},
+			checkResult: func(t *testing.T, out *dto.CreatePersonalAccessTokenOutput, err error) {
+				assert.NoError(t, err)
+				assert.True(t, strings.HasPrefix(out.Token, "pat_"))
+				assert.Equal(t, "laptop cli", out.Name)
+				assert.NotEmpty(t, out.TokenID)
+			},
+		},
+		{
+			name:    "should keep the requested scopes in canonical order and set the expiry",
+			useCase: s.personalAccessTokenUseCase,
+			input:   dto.CreatePersonalAccessTokenInput{UserID: 1, Name: "reports", Scopes: []string{"users:read", "users:read"}, ExpiresInDays: 30},
+			setupMocks: func() {
+				now := time.Now()
+				s.mockPATs.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, t *domain.PersonalAccessToken) error {
+					assert.Equal(s.T(), []string{domain.ScopeUsersRead}, t.Scopes)
+					assert.InDelta(s.T(), now.AddDate(0, 0, 30).Unix(), t.ExpiresAt, 5)
+					return nil
+				})
+			},
+			checkResult: func(t *testing.T, out *dto.CreatePersonalAccessTokenOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []string{domain.ScopeUsersRead}, out.Scopes)
+			},
+		},
+		{
+			name:       "should reject unknown scopes",
+			useCase:    s.personalAccessTokenUseCase,
+			input:      dto.CreatePersonalAccessTokenInput{UserID: 1, Name: "ci", Scopes: []string{"admin"}},
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, out *dto.CreatePersonalAccessTokenOutput, err error) {
+				assert.Nil(t, out)
+				assert.Equal(t, usecase.ErrUnknownScope, err)
+			},
+		},
+		{
+			name: "should require an expiry within the maximum lifetime",
+			useCase: func() port.UserUseCase {
+				return usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+					usecase.WithPersonalAccessTokens(s.mockPATs, 90*24*time.Hour))
+			},
+			input:      dto.CreatePersonalAccessTokenInput{UserID: 1, Name: "ci"},
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, out *dto.CreatePersonalAccessTokenOutput, err error) {
+				assert.Nil(t, out)
+				assert.Equal(t, usecase.ErrTokenLifetimeTooLong, err)
+			},
+		},
+		{
+			name:       "should return error when the name is missing",
+			useCase:    s.personalAccessTokenUseCase,
+			input:      dto.CreatePersonalAccessTokenInput{UserID: 1, Name: "  "},
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, out *dto.CreatePersonalAccessTokenOutput, err error) {
+				assert.Nil(t, out)
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+			},
+		},
+		{
+			name:       "should return error when personal access tokens are disabled",
+			useCase:    func() port.UserUseCase { return s.useCase },
+			input:      dto.CreatePersonalAccessTokenInput{UserID: 1, Name: "ci"},
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, out *dto.CreatePersonalAccessTokenOutput, err error) {
+				assert.Nil(t, out)
+				assert.Equal(t, usecase.ErrPersonalAccessTokensDisabled, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			out, err := tt.useCase().CreatePersonalAccessToken(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, out, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_ListPersonalAccessTokens() {
+	// Arrange
+	now := time.Now().Unix()
+	s.mockPATs.EXPECT().ListByUser(s.ctx, int64(1)).Return([]*domain.PersonalAccessToken{
+		{TokenHash: "hash-1", TokenID: "pat-1", UserID: 1, Name: "old", CreatedAt: now - 600},
+		{TokenHash: "hash-2", TokenID: "pat-2", UserID: 1, Name: "new", CreatedAt: now - 60, ExpiresAt: now + 3600},
+		{TokenHash: "hash-3", TokenID: "pat-3", UserID: 1, Name: "expired", CreatedAt: now - 60, ExpiresAt: now - 1},
+	}, nil)
+
+	// Act
+	out, err := s.personalAccessTokenUseCase().ListPersonalAccessTokens(s.ctx, 1)
+
+	// Assert
+	s.NoError(err)
+	s.Len(out.Tokens, 2)
+	s.Equal("pat-2", out.Tokens[0].TokenID)
+	s.Equal("pat-1", out.Tokens[1].TokenID)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_RevokePersonalAccessToken() {
+	tokens := []*domain.PersonalAccessToken{{TokenHash: "hash-1", TokenID: "pat-1", UserID: 1}}
+
+	tests := []struct {
+		name        string
+		input       dto.RevokePersonalAccessTokenInput
+		setupMocks  func()
+		checkResult func(*testing.T, error)
+	}{
+		{
+			name:  "should delete the token",
+			input: dto.RevokePersonalAccessTokenInput{UserID: 1, TokenID: "pat-1"},
+			setupMocks: func() {
+				s.mockPATs.EXPECT().ListByUser(s.ctx, int64(1)).Return(tokens, nil)
+				s.mockPATs.EXPECT().Delete(s.ctx, "hash-1").Return(nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "should not find tokens of other users",
+			input: dto.RevokePersonalAccessTokenInput{UserID: 2, TokenID: "pat-1"},
+			setupMocks: func() {
+				s.mockPATs.EXPECT().ListByUser(s.ctx, int64(2)).Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrPersonalAccessTokenNotFound, err)
+			},
+		},
+		{
+			name:       "should return error when the token ID is missing",
+			input:      dto.RevokePersonalAccessTokenInput{UserID: 1},
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, err error) {
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			uc := s.personalAccessTokenUseCase()
+			tt.setupMocks()
+
+			// Act
+			err := uc.RevokePersonalAccessToken(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Authenticate_PersonalAccessToken() {
+	const token = "pat_secret"
+	sum := sha256.Sum256([]byte(token))
+	hash := hex.EncodeToString(sum[:])
+	now := time.Now().Unix()
+	user := &domain.User{UserID: 1, Email: "john@example.com", Roles: []string{"admin"}, TokensValidAfter: now - 3600}
+
+	tests := []struct {
+		name        string
+		useCase     func() port.UserUseCase
+		setupMocks  func()
+		checkResult func(*testing.T, *domain.Principal, error)
+	}{
+		{
+			name:    "should authenticate as the token's user with its scopes and record the use",
+			useCase: s.personalAccessTokenUseCase,
+			setupMocks: func() {
+				s.mockPATs.EXPECT().GetByHash(s.ctx, hash).Return(&domain.PersonalAccessToken{
+					TokenHash: hash, TokenID: "pat-1", UserID: 1, Scopes: []string{domain.ScopeUsersRead}, CreatedAt: now - 60,
+				}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(user, nil)
+				s.mockPATs.EXPECT().Touch(s.ctx, hash, gomock.Any()).Return(nil)
+			},
+			checkResult: func(t *testing.T, p *domain.Principal, err error) {
+				assert.NoError(t, err)
+				assert.True(t, p.IsPersonalAccessToken())
+				assert.Equal(t, int64(1), p.UserID)
+				assert.Equal(t, "pat-1", p.TokenID)
+				assert.Equal(t, []string{"admin"}, p.Roles)
+				assert.True(t, p.HasScope(domain.ScopeUsersRead))
+				assert.False(t, p.HasScope(domain.ScopeUsersWrite))
+			},
+		},
+		{
+			name:    "should not record a use made moments ago again",
+			useCase: s.personalAccessTokenUseCase,
+			setupMocks: func() {
+				s.mockPATs.EXPECT().GetByHash(s.ctx, hash).
+					Return(&domain.PersonalAccessToken{TokenHash: hash, UserID: 1, CreatedAt: now - 60, LastUsedAt: now}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(user, nil)
+			},
+			checkResult: func(t *testing.T, p *domain.Principal, err error) {
+				assert.NoError(t, err)
+				assert.NotNil(t, p)
+			},
+		},
+		{
+			name:    "should reject an expired token",
+			useCase: s.personalAccessTokenUseCase,
+			setupMocks: func() {
+				s.mockPATs.EXPECT().GetByHash(s.ctx, hash).
+					Return(&domain.PersonalAccessToken{TokenHash: hash, UserID: 1, CreatedAt: now - 60, ExpiresAt: now - 1}, nil)
+			},
+			checkResult: func(t *testing.T, p *domain.Principal, err error) {
+				assert.Nil(t, p)
+				assert.Equal(t, usecase.ErrInvalidToken, err)
+			},
+		},
+		{
+			name:    "should reject a token created before the last password change",
+			useCase: s.personalAccessTokenUseCase,
+			setupMocks: func() {
+				s.mockPATs.EXPECT().GetByHash(s.ctx, hash).
+					Return(&domain.PersonalAccessToken{TokenHash: hash, UserID: 1, CreatedAt: now - 7200}, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(user, nil)
+			},
+			checkResult: func(t *testing.T, p *domain.Principal, err error) {
+				assert.Nil(t, p)
+				assert.Equal(t, usecase.ErrInvalidToken, err)
+			},
+		},
+		{
+			name:    "should reject an unknown token",
+			useCase: s.personalAccessTokenUseCase,
+			setupMocks: func() {
+				s.mockPATs.EXPECT().GetByHash(s.ctx, hash).Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, p *domain.Principal, err error) {
+				assert.Nil(t, p)
+				assert.Equal(t, usecase.ErrInvalidToken, err)
+			},
+		},
+		{
+			name:       "should reject personal access tokens when they are disabled",
+			useCase:    func() port.UserUseCase { return s.useCase },
+			setupMocks: func() {},
+			checkResult: func(t *testing.T, p *domain.Principal, err error) {
+				assert.Nil(t, p)
+				assert.Equal(t, usecase.ErrInvalidToken, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			p, err := tt.useCase().Authenticate(s.ctx, token)
+
+			// Assert
+			tt.checkResult(t, p, err)
+		})
+	}
+}
+
+func (s *UserUsecaseSuiteTest) impersonationUseCase() port.UserUseCase {
+	return usecase.NewUserUseCase(s.mockRepo, s.mockHasher, s.mockJWTSigner,
+		usecase.WithImpersonation(s.mockAudit, 15*time.Minute),
+	)
+}
+
+func (s *UserUsecaseSuiteTest) TestUserUseCase_Impersonate() {
+	admin := &domain.User{UserID: 9, Name: "Admin", Email: "admin@example.com", Roles: []string{domain.RoleAdmin}}
+	otherAdmin := &domain.User{UserID: 8, Name: "Other Admin", Email: "other@example.com", Roles: []string{domain.RoleAdmin}}
+	input := dto.ImpersonateInput{AdminID: 9, UserID: 1, Reason: " ticket #42 "}
+
+	tests := []struct {
+		name        string
+		useCase     func() port.UserUseCase
+		input       dto.ImpersonateInput
+		setupMocks  func()
+		checkResult func(*testing.T, *dto.ImpersonateOutput, error)
+	}{
+		{
+			name:    "should audit and issue a token acting as the user",
+			useCase: s.impersonationUseCase,
+			input:   input,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(9)).Return(admin, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(s.mockUsers[0], nil)
+				gomock.InOrder(
+					s.mockAudit.EXPECT().
+						Record(s.ctx, gomock.Any()).
+						DoAndReturn(func(_ context.Context, e domain.AuditEvent) error {
+							assert.Equal(s.T(), domain.AuditImpersonationStarted, e.Type)
+							assert.Equal(s.T(), int64(9), e.ActorUserID)
+							assert.Equal(s.T(), int64(1), e.UserID)
+							assert.Equal(s.T(), "ticket #42", e.Reason)
+							assert.NotZero(s.T(), e.At)
+							return nil
+						}),
+					s.mockJWTSigner.EXPECT().
+						Sign(gomock.Any()).
+						DoAndReturn(func(p domain.Principal) (string, error) {
+							assert.Equal(s.T(), int64(1), p.UserID)
+							assert.Equal(s.T(), int64(9), p.ActorUserID)
+							assert.Equal(s.T(), "admin@example.com", p.ActorEmail)
+							assert.InDelta(s.T(), time.Now().Add(15*time.Minute).Unix(), p.ExpiresAt, 5)
+							return "impersonation-token", nil
+						}),
+				)
+			},
+			checkResult: func(t *testing.T, out *dto.ImpersonateOutput, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "impersonation-token", out.Token)
+				assert.Equal(t, int64(900), out.ExpiresIn)
+				assert.Equal(t, int64(1), out.UserID)
+			},
+		},
+		{
+			name:    "should reject callers without the admin role",
+			useCase: s.impersonationUseCase,
+			input:   dto.ImpersonateInput{AdminID: 2, UserID: 1, Reason: "ticket #42"},
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(2)).Return(s.mockUsers[1], nil)
+			},
+			checkResult: func(t *testing.T, out *dto.ImpersonateOutput, err error) {
+				assert.Nil(t, out)
+				assert.Equal(t, usecase.ErrAdminRequired, err)
+			},
+		},
+		{
+			name:    "should refuse to impersonate another admin",
+			useCase: s.impersonationUseCase,
+			input:   dto.ImpersonateInput{AdminID: 9, UserID: 8, Reason: "ticket #42"},
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(9)).Return(admin, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(8)).Return(otherAdmin, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.ImpersonateOutput, err error) {
+				assert.Nil(t, out)
+				assert.Equal(t, usecase.ErrCannotImpersonate, err)
+			},
+		},
+		{
+			name:    "should return error when the user does not exist",
+			useCase: s.impersonationUseCase,
+			input:   dto.ImpersonateInput{AdminID: 9, UserID: 99, Reason: "ticket #42"},
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(9)).Return(admin, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(99)).Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, out *dto.ImpersonateOutput, err error) {
+				assert.Nil(t, out)
+				assert.Equal(t, usecase.ErrUserNotFound, err)
+			},
+		},
+		{
+			name:    "should not issue a token when the audit log fails",
+			useCase: s.impersonationUseCase,
+			input:   input,
+			setupMocks: func() {
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(9)).Return(admin, nil)
+				s.mockRepo.EXPECT().GetByID(s.ctx, int64(1)).Return(s.mockUsers[0], nil)
+				s.mockAudit.EXPECT().Record(s.ctx, gomock.Any()).Return(assert.AnError)
+			},
+			checkResult: func(t *testing.T, out *dto.ImpersonateOutput, err error) {
+				assert.Nil(t, out)
+				assert.Equal(t, assert.AnError, err)
+			},
+		},
+		{
+			name:    "should require a reason",
+			useCase: s.impersonationUseCase,
+			input:   dto.ImpersonateInput{AdminID: 9, UserID: 1, Reason: "  "},
+			setupMocks: func() {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, out *dto.ImpersonateOutput, err error) {
+				assert.Nil(t, out)
+				assert.Equal(t, usecase.ErrInvalidInput, err)
+			},
+		},
+		{
+			name:    "should return error when impersonation is disabled",
+			useCase: func() port.UserUseCase { return s.useCase },
+			input:   input,
+			setupMocks: func() {
+				// No mock calls expected
+			},
+			checkResult: func(t *testing.T, out *dto.ImpersonateOutput, err error) {
+				assert.Nil(t, out)
+				assert.Equal(t, usecase.ErrImpersonationDisabled, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tt.setupMocks()
+
+			// Act
+			out, err := tt.useCase().Impersonate(s.ctx, tt.input)
+
+			// Assert
+			tt.checkResult(t, out, err)
 		})
 	}
 }
@@ -0,0 +1,37 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/logger"
+)
+
+type logAuditLog struct {
+	log *logger.Logger
+}
+
+// NewLogAuditLog writes audit events to the log as "audit" entries, which CloudWatch keeps
+// and can filter on.
+func NewLogAuditLog(log *logger.Logger) port.AuditLog {
+	return &logAuditLog{log: log}
+}
+
+func (a *logAuditLog) Record(ctx context.Context, e domain.AuditEvent) error {
+	attrs := []any{
+		"type", e.Type,
+		"actorUserId", e.ActorUserID,
+		"userId", e.UserID,
+		"at", time.Unix(e.At, 0).UTC().Format(time.RFC3339),
+	}
+	if e.Reason != "" {
+		attrs = append(attrs, "reason", e.Reason)
+	}
+	if e.Method != "" {
+		attrs = append(attrs, "method", e.Method, "path", e.Path)
+	}
+	a.log.InfoContext(ctx, "audit", attrs...)
+	return nil
+}
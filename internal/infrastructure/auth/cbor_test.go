@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Vectors from RFC 8949 Appendix A.
+func TestDecodeCBOR(t *testing.T) {
+	tests := []struct {
+		hex  string
+		want any
+	}{
+		{"00", int64(0)},
+		{"17", int64(23)},
+		{"1818", int64(24)},
+		{"1903e8", int64(1000)},
+		{"1b000000e8d4a51000", int64(1000000000000)},
+		{"20", int64(-1)},
+		{"3903e7", int64(-1000)},
+		{"f4", false},
+		{"f5", true},
+		{"f6", nil},
+		{"4401020304", []byte{1, 2, 3, 4}},
+		{"6449455446", "IETF"},
+		{"83010203", []any{int64(1), int64(2), int64(3)}},
+		{"a201020304", map[any]any{int64(1): int64(2), int64(3): int64(4)}},
+		{"a26161016162820203", map[any]any{"a": int64(1), "b": []any{int64(2), int64(3)}}},
+		{"c11a514b67b0", int64(1363896240)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hex, func(t *testing.T) {
+			raw, _ := hex.DecodeString(tt.hex)
+			got, rest, err := decodeCBOR(raw)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Empty(t, rest)
+		})
+	}
+}
+
+func TestDecodeCBOR_ReturnsRemainder(t *testing.T) {
+	got, rest, err := decodeCBOR([]byte{0x01, 0x02})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), got)
+	assert.Equal(t, []byte{0x02}, rest)
+}
+
+func TestDecodeCBOR_Errors(t *testing.T) {
+	for name, h := range map[string]string{
+		"empty":               "",
+		"truncated integer":   "1903",
+		"truncated bytes":     "4401",
+		"huge array":          "9bffffffffffffffff",
+		"indefinite length":   "5f",
+		"float":               "f93c00",
+		"duplicate map key":   "a201020103",
+		"unsupported map key": "a1f401",
+		"integer overflow":    "1bffffffffffffffff",
+	} {
+		t.Run(name, func(t *testing.T) {
+			raw, _ := hex.DecodeString(h)
+			_, _, err := decodeCBOR(raw)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDecodeCBOR_DepthLimit(t *testing.T) {
+	raw := make([]byte, 0, 40)
+	for range 40 {
+		raw = append(raw, 0x81) // array of one element
+	}
+	raw = append(raw, 0x00)
+	_, _, err := decodeCBOR(raw)
+	assert.Error(t, err)
+}
+
+// FuzzDecodeCBOR checks that arbitrary input never panics and that a decoded item always
+// consumes a prefix of the input.
+func FuzzDecodeCBOR(f *testing.F) {
+	for _, h := range []string{
+		"00", "1b000000e8d4a51000", "3903e7", "f5", "4401020304", "6449455446", "83010203",
+		"a26161016162820203", "c11a514b67b0", "9bffffffffffffffff", "bb0000000000000001", "8181818100",
+	} {
+		raw, _ := hex.DecodeString(h)
+		f.Add(raw)
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		_, rest, err := decodeCBOR(b)
+		if err != nil {
+			return
+		}
+		if len(rest) >= len(b) || !bytes.HasSuffix(b, rest) {
+			t.Fatalf("decodeCBOR(%x) left %x, which is not a proper suffix", b, rest)
+		}
+	})
+}
@@ -0,0 +1,410 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+)
+
+// jwksRefreshInterval limits how often an unknown kid makes us re-read a provider's keys,
+// so tokens with made-up kids cannot be used to hammer the provider.
+const jwksRefreshInterval = time.Minute
+
+// identityProviderDocument is one entry of the identity provider configuration, e.g.
+//
+//	[{"name": "google", "issuer": "https://accounts.google.com",
+//	  "client_id": "1234.apps.googleusercontent.com", "client_secret": "...",
+//	  "redirect_uri": "https://app.example.com/login/google/callback"}]
+type identityProviderDocument struct {
+	Name         string   `json:"name"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret,omitempty"` // empty for public clients
+	RedirectURI  string   `json:"redirect_uri"`
+	Scopes       []string `json:"scopes,omitempty"` // openid, email and profile by default
+}
+
+// providerMetadata is the part of an OpenID Connect discovery document we rely on.
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcProvider struct {
+	name         string
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURI  string
+	scopes       []string
+	http         *http.Client
+
+	mu            sync.Mutex
+	metadata      *providerMetadata
+	keys          map[string]*signingKey
+	keysFetchedAt time.Time
+}
+
+// externalIDTokenClaims are the ID token claims of an external provider. Some providers
+// send email_verified as a string, hence the custom type.
+type externalIDTokenClaims struct {
+	Nonce           string       `json:"nonce"`
+	AuthorizedParty string       `json:"azp,omitempty"`
+	Email           string       `json:"email,omitempty"`
+	EmailVerified   flexibleBool `json:"email_verified,omitempty"`
+	Name            string       `json:"name,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch t := v.(type) {
+	case bool:
+		*b = flexibleBool(t)
+	case string:
+		*b = flexibleBool(t == "true")
+	}
+	return nil
+}
+
+// NewIdentityProviders builds the external OpenID Connect providers of the JSON
+// configuration raw, keyed by name. Endpoints and keys are discovered from each issuer on
+// first use rather than here, so an unreachable provider does not keep the service from
+// starting. client makes the calls to the providers; nil uses a client with a 10s timeout.
+func NewIdentityProviders(raw string, client *http.Client) (map[string]port.IdentityProvider, error) {
+	var docs []identityProviderDocument
+	if err := json.Unmarshal([]byte(raw), &docs); err != nil {
+		return nil, fmt.Errorf("parse identity providers: %w", err)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	providers := make(map[string]port.IdentityProvider, len(docs))
+	for _, d := range docs {
+		if d.Name == "" || d.Issuer == "" || d.ClientID == "" || d.RedirectURI == "" {
+			return nil, errors.New("identity providers need a name, issuer, client_id and redirect_uri")
+		}
+		if _, dup := providers[d.Name]; dup {
+			return nil, fmt.Errorf("duplicate identity provider %q", d.Name)
+		}
+		scopes := d.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{"openid", "email", "profile"}
+		} else if !slices.Contains(scopes, "openid") {
+			scopes = append([]string{"openid"}, scopes...)
+		}
+		providers[d.Name] = &oidcProvider{
+			name:         d.Name,
+			issuer:       d.Issuer,
+			clientID:     d.ClientID,
+			clientSecret: d.ClientSecret,
+			redirectURI:  d.RedirectURI,
+			scopes:       scopes,
+			http:         client,
+		}
+	}
+	return providers, nil
+}
+
+// ensure implementation
+var _ port.IdentityProvider = (*oidcProvider)(nil)
+
+func (o *oidcProvider) AuthorizationURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	meta, err := o.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(meta.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", o.clientID)
+	q.Set("redirect_uri", o.redirectURI)
+	q.Set("scope", strings.Join(o.scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+func (o *oidcProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.ExternalIdentity, error) {
+	meta, err := o.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {o.redirectURI},
+		"code_verifier": {codeVerifier},
+	}
+	if o.clientSecret == "" {
+		form.Set("client_id", o.clientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if o.clientSecret != "" {
+		// RFC 6749 section 2.3.1: both parts are form-encoded before going into Basic auth
+		req.SetBasicAuth(url.QueryEscape(o.clientID), url.QueryEscape(o.clientSecret))
+	}
+	var tokens struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := o.doJSON(req, &tokens)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint answered %d: %s %s", status, tokens.Error, tokens.ErrorDescription)
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+	return o.verifyIDToken(ctx, tokens.IDToken, nonce)
+}
+
+// verifyIDToken validates an ID token as OpenID Connect Core section 3.1.3.7 asks of
+// relying parties and returns the identity it asserts.
+func (o *oidcProvider) verifyIDToken(ctx context.Context, raw, nonce string) (*domain.ExternalIdentity, error) {
+	claims := &externalIDTokenClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := o.key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if t.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("invalid signing method")
+		}
+		return key.verifyKey, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}),
+		jwt.WithIssuer(o.issuer),
+		jwt.WithAudience(o.clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(30*time.Second),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("id token has no subject")
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("id token nonce does not match")
+	}
+	if (len(claims.Audience) > 1 || claims.AuthorizedParty != "") && claims.AuthorizedParty != o.clientID {
+		return nil, errors.New("id token was issued to another party")
+	}
+	return &domain.ExternalIdentity{
+		Provider:      o.name,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// discover fetches the provider's discovery document once. A failure is not cached, so
+// the next sign-in tries again.
+func (o *oidcProvider) discover(ctx context.Context) (*providerMetadata, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.metadata != nil {
+		return o.metadata, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(o.issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var meta providerMetadata
+	status, err := o.doJSON(req, &meta)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("discovery of %s answered %d", o.issuer, status)
+	}
+	// OpenID Connect Discovery section 4.3: the document must be for the issuer we asked
+	if meta.Issuer != o.issuer {
+		return nil, fmt.Errorf("discovery document is for issuer %q, not %q", meta.Issuer, o.issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, errors.New("discovery document lacks authorization, token or jwks endpoints")
+	}
+	o.metadata = &meta
+	return o.metadata, nil
+}
+
+// key returns the provider's signing key kid, re-reading the JWKS when the kid is unknown
+// (the provider rotated its keys) and the last read is old enough. A token without kid is
+// accepted when the provider publishes a single key.
+func (o *oidcProvider) key(ctx context.Context, kid string) (*signingKey, error) {
+	meta, err := o.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if k, ok := o.lookupKey(kid); ok {
+		return k, nil
+	}
+	if time.Since(o.keysFetchedAt) < jwksRefreshInterval {
+		return nil, errors.New("unknown signing key")
+	}
+	o.keysFetchedAt = time.Now()
+	keys, err := o.fetchKeys(ctx, meta.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	o.keys = keys
+	if k, ok := o.lookupKey(kid); ok {
+		return k, nil
+	}
+	return nil, errors.New("unknown signing key")
+}
+
+func (o *oidcProvider) lookupKey(kid string) (*signingKey, bool) {
+	if kid == "" && len(o.keys) == 1 {
+		for _, k := range o.keys {
+			return k, true
+		}
+	}
+	k, ok := o.keys[kid]
+	return k, ok
+}
+
+// fetchKeys reads a JWKS, keeping the RS256 and ES256 signature keys and skipping the
+// others.
+func (o *oidcProvider) fetchKeys(ctx context.Context, jwksURI string) (map[string]*signingKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	status, err := o.doJSON(req, &set)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("jwks endpoint answered %d", status)
+	}
+	keys := make(map[string]*signingKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if k, err := jwk.signingKey(); err == nil {
+			keys[k.id] = k
+		}
+	}
+	return keys, nil
+}
+
+func (o *oidcProvider) doJSON(req *http.Request, v any) (int, error) {
+	res, err := o.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(body, v); err != nil && res.StatusCode == http.StatusOK {
+		return 0, fmt.Errorf("decode %s: %w", req.URL, err)
+	}
+	return res.StatusCode, nil
+}
+
+// jsonWebKey is a public JWK (RFC 7517) as published by a provider.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// signingKey turns the JWK into a verification-only key. Keys without alg get the one
+// their type implies.
+func (k jsonWebKey) signingKey() (*signingKey, error) {
+	if k.Use != "" && k.Use != "sig" {
+		return nil, errors.New("not a signature key")
+	}
+	switch {
+	case k.Kty == "RSA" && (k.Alg == "" || k.Alg == jwt.SigningMethodRS256.Alg()):
+		n, err := decodeBase64URL(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBase64URL(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if pub.N.BitLen() < 2048 {
+			return nil, errors.New("RSA key must be at least 2048 bits")
+		}
+		return &signingKey{id: k.Kid, method: jwt.SigningMethodRS256, verifyKey: pub}, nil
+	case k.Kty == "EC" && k.Crv == "P-256" && (k.Alg == "" || k.Alg == jwt.SigningMethodES256.Alg()):
+		x, err := decodeBase64URL(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBase64URL(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid P-256 coordinates")
+		}
+		pub, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), append(append([]byte{4}, x...), y...))
+		if err != nil {
+			return nil, err
+		}
+		return &signingKey{id: k.Kid, method: jwt.SigningMethodES256, verifyKey: pub}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q with algorithm %q", k.Kty, k.Alg)
+}
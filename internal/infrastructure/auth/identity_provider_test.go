@@ -0,0 +1,296 @@
+package auth
+
+import (
+	"context"
+	"crypto/elliptic"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIdPClientID     = "our-client"
+	testIdPClientSecret = "our secret"
+	testIdPRedirectURI  = "https://app.example.com/login/fake/callback"
+	testIdPCode         = "good-code"
+)
+
+// fakeIdP is an in-process OpenID Connect provider: it serves discovery, a JWKS and a token
+// endpoint that answers testIdPCode with an ID token built from claims.
+type fakeIdP struct {
+	t         *testing.T
+	server    *httptest.Server
+	signer    *signingKey   // signs ID tokens
+	published []*signingKey // served from the JWKS
+	claims    func(issuer string) jwt.MapClaims
+
+	issuerOverride string
+	jwksRequests   int
+	tokenForm      url.Values
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	t.Helper()
+	f := &fakeIdP{t: t}
+	f.signer = f.newKey()
+	f.published = []*signingKey{f.signer}
+	f.claims = func(issuer string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":            issuer,
+			"sub":            "fake-user-1",
+			"aud":            testIdPClientID,
+			"exp":            time.Now().Add(time.Hour).Unix(),
+			"iat":            time.Now().Unix(),
+			"nonce":          "expected-nonce",
+			"email":          "john@example.com",
+			"email_verified": true,
+			"name":           "John Doe",
+		}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		issuer := f.server.URL
+		if f.issuerOverride != "" {
+			issuer = f.issuerOverride
+		}
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                 issuer,
+			"authorization_endpoint": f.server.URL + "/authorize?prompt=select_account",
+			"token_endpoint":         f.server.URL + "/token",
+			"jwks_uri":               f.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		f.jwksRequests++
+		keys := []jsonWebKey{}
+		for _, k := range f.published {
+			jwk, _ := k.publicJWK()
+			keys = append(keys, jsonWebKey{Kty: jwk.Kty, Use: jwk.Use, Alg: jwk.Alg, Kid: jwk.Kid, Crv: jwk.Crv, X: jwk.X, Y: jwk.Y})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"keys": keys})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		f.tokenForm = r.PostForm
+		id, secret, _ := r.BasicAuth()
+		id, _ = url.QueryUnescape(id)
+		secret, _ = url.QueryUnescape(secret)
+		if id != testIdPClientID || secret != testIdPClientSecret {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+			return
+		}
+		if r.PostForm.Get("code") != testIdPCode {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{
+			"access_token": "idp-access-token",
+			"token_type":   "Bearer",
+			"id_token":     f.sign(f.claims(f.server.URL)),
+		})
+	})
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeIdP) newKey() *signingKey {
+	key, err := newSigningKey("", "ES256", "", ecPrivateKeyPEM(f.t, elliptic.P256()))
+	require.NoError(f.t, err)
+	return key
+}
+
+func (f *fakeIdP) sign(claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(f.signer.method, claims)
+	token.Header["kid"] = f.signer.id
+	s, err := token.SignedString(f.signer.signKey)
+	require.NoError(f.t, err)
+	return s
+}
+
+// provider returns our relying party for the fake provider.
+func (f *fakeIdP) provider() *oidcProvider {
+	raw := fmt.Sprintf(`[{"name": "fake", "issuer": %q, "client_id": %q, "client_secret": %q, "redirect_uri": %q}]`,
+		f.server.URL, testIdPClientID, testIdPClientSecret, testIdPRedirectURI)
+	providers, err := NewIdentityProviders(raw, f.server.Client())
+	require.NoError(f.t, err)
+	return providers["fake"].(*oidcProvider)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestOIDCProvider_SignIn(t *testing.T) {
+	idp := newFakeIdP(t)
+	p := idp.provider()
+	ctx := context.Background()
+
+	authURL, err := p.AuthorizationURL(ctx, "the-state", "expected-nonce", "the-challenge")
+	require.NoError(t, err)
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, idp.server.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	q := u.Query()
+	assert.Equal(t, "select_account", q.Get("prompt"))
+	assert.Equal(t, "code", q.Get("response_type"))
+	assert.Equal(t, testIdPClientID, q.Get("client_id"))
+	assert.Equal(t, testIdPRedirectURI, q.Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", q.Get("scope"))
+	assert.Equal(t, "the-state", q.Get("state"))
+	assert.Equal(t, "expected-nonce", q.Get("nonce"))
+	assert.Equal(t, "the-challenge", q.Get("code_challenge"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+
+	identity, err := p.Exchange(ctx, testIdPCode, "the-verifier", "expected-nonce")
+	require.NoError(t, err)
+	assert.Equal(t, "fake", identity.Provider)
+	assert.Equal(t, "fake-user-1", identity.Subject)
+	assert.Equal(t, "john@example.com", identity.Email)
+	assert.True(t, identity.EmailVerified)
+	assert.Equal(t, "John Doe", identity.Name)
+	assert.Equal(t, "authorization_code", idp.tokenForm.Get("grant_type"))
+	assert.Equal(t, testIdPRedirectURI, idp.tokenForm.Get("redirect_uri"))
+	assert.Equal(t, "the-verifier", idp.tokenForm.Get("code_verifier"))
+	assert.Empty(t, idp.tokenForm.Get("client_secret"), "the secret goes in the Authorization header only")
+}
+
+func TestOIDCProvider_Exchange_RejectsInvalidIDTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(c jwt.MapClaims)
+	}{
+		{"wrong nonce", func(c jwt.MapClaims) { c["nonce"] = "replayed-nonce" }},
+		{"missing nonce", func(c jwt.MapClaims) { delete(c, "nonce") }},
+		{"other audience", func(c jwt.MapClaims) { c["aud"] = "someone-else" }},
+		{"other issuer", func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{"expired", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{"no expiry", func(c jwt.MapClaims) { delete(c, "exp") }},
+		{"no subject", func(c jwt.MapClaims) { delete(c, "sub") }},
+		{"issued to another party", func(c jwt.MapClaims) {
+			c["aud"] = []string{testIdPClientID, "someone-else"}
+			c["azp"] = "someone-else"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newFakeIdP(t)
+			base := idp.claims
+			idp.claims = func(issuer string) jwt.MapClaims {
+				c := base(issuer)
+				tt.mutate(c)
+				return c
+			}
+
+			identity, err := idp.provider().Exchange(context.Background(), testIdPCode, "the-verifier", "expected-nonce")
+			assert.Error(t, err)
+			assert.Nil(t, identity)
+		})
+	}
+
+	t.Run("signed with a key the provider does not publish", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		idp.signer = idp.newKey()
+
+		identity, err := idp.provider().Exchange(context.Background(), testIdPCode, "the-verifier", "expected-nonce")
+		assert.Error(t, err)
+		assert.Nil(t, identity)
+	})
+
+	t.Run("HS256 token keyed with the provider's public key", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		jwk, _ := idp.signer.publicJWK()
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, idp.claims(idp.server.URL))
+		token.Header["kid"] = jwk.Kid
+		forged, err := token.SignedString([]byte(jwk.X))
+		require.NoError(t, err)
+
+		identity, err := idp.provider().verifyIDToken(context.Background(), forged, "expected-nonce")
+		assert.Error(t, err)
+		assert.Nil(t, identity)
+	})
+
+	t.Run("code refused by the provider", func(t *testing.T) {
+		idp := newFakeIdP(t)
+
+		identity, err := idp.provider().Exchange(context.Background(), "stolen-code", "the-verifier", "expected-nonce")
+		assert.ErrorContains(t, err, "invalid_grant")
+		assert.Nil(t, identity)
+	})
+}
+
+func TestOIDCProvider_EmailVerifiedAsString(t *testing.T) {
+	idp := newFakeIdP(t)
+	base := idp.claims
+	idp.claims = func(issuer string) jwt.MapClaims {
+		c := base(issuer)
+		c["email_verified"] = "true"
+		return c
+	}
+
+	identity, err := idp.provider().Exchange(context.Background(), testIdPCode, "the-verifier", "expected-nonce")
+	require.NoError(t, err)
+	assert.True(t, identity.EmailVerified)
+}
+
+func TestOIDCProvider_KeyRotation(t *testing.T) {
+	idp := newFakeIdP(t)
+	p := idp.provider()
+	ctx := context.Background()
+
+	_, err := p.Exchange(ctx, testIdPCode, "the-verifier", "expected-nonce")
+	require.NoError(t, err)
+	assert.Equal(t, 1, idp.jwksRequests)
+
+	// the provider rotates to a new key: it is fetched, but not more than once a minute
+	idp.signer = idp.newKey()
+	idp.published = append(idp.published, idp.signer)
+	_, err = p.Exchange(ctx, testIdPCode, "the-verifier", "expected-nonce")
+	assert.Error(t, err)
+	assert.Equal(t, 1, idp.jwksRequests)
+
+	p.keysFetchedAt = time.Now().Add(-jwksRefreshInterval)
+	_, err = p.Exchange(ctx, testIdPCode, "the-verifier", "expected-nonce")
+	require.NoError(t, err)
+	assert.Equal(t, 2, idp.jwksRequests)
+}
+
+func TestOIDCProvider_DiscoveryForAnotherIssuer(t *testing.T) {
+	idp := newFakeIdP(t)
+	idp.issuerOverride = "https://evil.example.com"
+
+	_, err := idp.provider().AuthorizationURL(context.Background(), "state", "nonce", "challenge")
+	assert.ErrorContains(t, err, "evil.example.com")
+}
+
+func TestNewIdentityProviders(t *testing.T) {
+	providers, err := NewIdentityProviders(`[
+		{"name": "google", "issuer": "https://accounts.google.com", "client_id": "g", "client_secret": "s", "redirect_uri": "https://app.example.com/cb"},
+		{"name": "corp", "issuer": "https://sso.example.com", "client_id": "c", "redirect_uri": "https://app.example.com/cb", "scopes": ["email"]}
+	]`, nil)
+	require.NoError(t, err)
+	assert.Len(t, providers, 2)
+	assert.Equal(t, []string{"openid", "email"}, providers["corp"].(*oidcProvider).scopes)
+
+	for name, raw := range map[string]string{
+		"malformed":         `{`,
+		"missing client id": `[{"name": "x", "issuer": "https://x", "redirect_uri": "https://app/cb"}]`,
+		"duplicate name": `[{"name": "x", "issuer": "https://x", "client_id": "a", "redirect_uri": "https://app/cb"},
+			{"name": "x", "issuer": "https://y", "client_id": "b", "redirect_uri": "https://app/cb"}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewIdentityProviders(raw, nil)
+			assert.Error(t, err)
+		})
+	}
+}
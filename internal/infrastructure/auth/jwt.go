@@ -1,58 +1,430 @@
 package auth
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"log"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/paramstore"
 )
 
+var (
+	ErrTokenRevoked = errors.New("token revoked")
+	ErrNotUserToken = errors.New("token was not issued to a user")
+	ErrIDToken      = errors.New("id tokens are not access tokens")
+)
+
+// tokenUseID marks ID tokens, which share keys, issuer and subject with access tokens.
+const tokenUseID = "id"
+
+// Claims are the access token claims. The registered sub claim holds the user ID, or the
+// client ID for tokens issued through the client credentials grant; sub_type tells them apart.
 type Claims struct {
-	UserID string `json:"user_id"`
+	SubjectType string   `json:"sub_type,omitempty"`
+	ClientID    string   `json:"client_id,omitempty"` // RFC 9068
+	Email       string   `json:"email,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Scope       string   `json:"scope,omitempty"` // space-delimited, as in RFC 8693
+	SessionID   string   `json:"sid,omitempty"`   // as in OpenID Connect
+	Actor       *actor   `json:"act,omitempty"`   // RFC 8693; set on impersonation tokens
+	// UserID is only read, so tokens issued before sub was introduced keep verifying.
+	UserID string `json:"user_id,omitempty"`
+	// TokenUse is only read, to refuse ID tokens presented as access tokens.
+	TokenUse string `json:"token_use,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// actor is the act claim of an impersonation token: the admin acting as the subject.
+type actor struct {
+	Subject string `json:"sub"`
+	Email   string `json:"email,omitempty"`
+}
+
+// idTokenClaims are the OpenID Connect ID token claims. The audience is the client the
+// token was issued to rather than the configured access token audience.
+type idTokenClaims struct {
+	TokenUse      string           `json:"token_use"`
+	Nonce         string           `json:"nonce,omitempty"`
+	AuthTime      *jwt.NumericDate `json:"auth_time,omitempty"`
+	Email         string           `json:"email,omitempty"`
+	EmailVerified *bool            `json:"email_verified,omitempty"`
+	Name          string           `json:"name,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type jwtSigner struct {
-	secret []byte
-	exp    time.Duration
+	exp         time.Duration
+	issuer      string
+	audience    []string
+	revocations port.TokenRevocationStore
+
+	mu          sync.RWMutex
+	ring        *keyRing
+	loadedAt    time.Time
+	reloading   bool
+	reload      func(ctx context.Context) (*keyRing, error) // nil for static keys
+	reloadEvery time.Duration
 }
 
-func NewJWTSigner(cfg *config.Config) port.JWTSigner {
-	return &jwtSigner{secret: []byte(cfg.JWTSecret), exp: cfg.JWTExpiration}
+// keyRingReloadTimeout bounds a Parameter Store read of the key ring.
+const keyRingReloadTimeout = 3 * time.Second
+
+// NewJWTSigner builds a signer whose tokens are checked against the given revocation store
+// on every Verify; a nil store disables the denylist check. Keys come from cfg.JWTKeyRing
+// when set, re-read from Parameter Store every cfg.JWTKeyRingRefresh so rotations reach warm
+// Lambda containers. Otherwise a single key is built from cfg.JWTAlgorithm (HS256 with
+// cfg.JWTSecret, or RS256/ES256 with cfg.JWTPrivateKey).
+func NewJWTSigner(cfg *config.Config, revocations port.TokenRevocationStore) (port.JWTSigner, error) {
+	j := &jwtSigner{
+		exp:         cfg.JWTExpiration,
+		issuer:      cfg.JWTIssuer,
+		audience:    cfg.JWTAudience,
+		revocations: revocations,
+		loadedAt:    time.Now(),
+	}
+	if cfg.JWTKeyRing == "" {
+		key, err := newSigningKey("", cfg.JWTAlgorithm, cfg.JWTSecret, cfg.JWTPrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		j.ring = singleKeyRing(key)
+		return j, nil
+	}
+	ring, err := parseKeyRing(cfg.JWTKeyRing, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	j.ring = ring
+	if name := cfg.JWTKeyRingParameterName; name != "" && cfg.JWTKeyRingRefresh > 0 {
+		j.reloadEvery = cfg.JWTKeyRingRefresh
+		j.reload = func(ctx context.Context) (*keyRing, error) {
+			raw, err := paramstore.GetParameter(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			return parseKeyRing(raw, time.Now())
+		}
+	}
+	return j, nil
 }
 
 // ensure implementation
 var _ port.JWTSigner = (*jwtSigner)(nil)
 
-func (j *jwtSigner) Sign(userID int64) (string, error) {
+func (j *jwtSigner) Sign(p domain.Principal) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	expiresAt := now.Add(j.exp)
+	if p.ExpiresAt > 0 {
+		expiresAt = time.Unix(p.ExpiresAt, 0)
+	}
 	claims := Claims{
-		UserID: strconv.FormatInt(userID, 10),
+		SubjectType: domain.SubjectTypeUser,
+		Email:       p.Email,
+		Roles:       p.Roles,
+		Scope:       strings.Join(p.Scopes, " "),
+		SessionID:   p.SessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.exp)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        jti,
+			Subject:   strconv.FormatInt(p.UserID, 10),
+			Issuer:    j.issuer,
+			Audience:  j.audience,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secret)
+	if p.ClientID != "" {
+		claims.ClientID = p.ClientID
+	}
+	if p.IsImpersonated() {
+		claims.Actor = &actor{Subject: strconv.FormatInt(p.ActorUserID, 10), Email: p.ActorEmail}
+	}
+	if p.IsClient() {
+		claims.SubjectType = domain.SubjectTypeClient
+		claims.Subject = p.ClientID
+	}
+	return j.signClaims(claims)
 }
 
-func (j *jwtSigner) Verify(tokenStr string) (int64, error) {
+// SignIDToken signs an OpenID Connect ID token with the current access token key, so
+// relying parties verify it through the same JWKS. It carries token_use "id", which
+// VerifyPrincipal refuses.
+func (j *jwtSigner) SignIDToken(t domain.IDToken) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	expiresAt := now.Add(j.exp)
+	if t.ExpiresAt > 0 {
+		expiresAt = time.Unix(t.ExpiresAt, 0)
+	}
+	claims := idTokenClaims{
+		TokenUse: tokenUseID,
+		Nonce:    t.Nonce,
+		Name:     t.Name,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   strconv.FormatInt(t.UserID, 10),
+			Issuer:    j.issuer,
+			Audience:  jwt.ClaimStrings{t.Audience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+	if t.AuthTime > 0 {
+		claims.AuthTime = jwt.NewNumericDate(time.Unix(t.AuthTime, 0))
+	}
+	if t.Email != "" {
+		verified := t.EmailVerified
+		claims.Email = t.Email
+		claims.EmailVerified = &verified
+	}
+	return j.signClaims(claims)
+}
+
+func (j *jwtSigner) signClaims(claims jwt.Claims) (string, error) {
+	key := j.keyRing().active
+	token := jwt.NewWithClaims(key.method, claims)
+	if key.id != "" {
+		token.Header["kid"] = key.id
+	}
+	return token.SignedString(key.signKey)
+}
+
+// Verify is VerifyPrincipal for user tokens. Besides the user it returns the admin acting
+// as them, for impersonation tokens, and 0 otherwise.
+func (j *jwtSigner) Verify(ctx context.Context, tokenStr string) (userID, actorUserID int64, err error) {
+	p, err := j.VerifyPrincipal(ctx, tokenStr)
+	if err != nil {
+		return 0, 0, err
+	}
+	if p.IsClient() {
+		return 0, 0, ErrNotUserToken
+	}
+	return p.UserID, p.ActorUserID, nil
+}
+
+// VerifyPrincipal validates signature, expiry, issuer, audience and revocation status and
+// returns the identity carried by the token.
+func (j *jwtSigner) VerifyPrincipal(ctx context.Context, tokenStr string) (*domain.Principal, error) {
+	claims, err := j.parse(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenUse == tokenUseID {
+		return nil, ErrIDToken
+	}
+	if j.revocations != nil {
+		for _, id := range []string{claims.ID, sessionRevocationID(claims.SessionID)} {
+			if id == "" {
+				continue
+			}
+			revoked, err := j.revocations.IsRevoked(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if revoked {
+				return nil, ErrTokenRevoked
+			}
+		}
+	}
+	p := &domain.Principal{
+		SubjectType: domain.SubjectTypeUser,
+		Email:       claims.Email,
+		Roles:       claims.Roles,
+		Scopes:      strings.Fields(claims.Scope),
+		TokenID:     claims.ID,
+		SessionID:   claims.SessionID,
+	}
+	switch claims.SubjectType {
+	case domain.SubjectTypeClient:
+		if claims.Subject == "" {
+			return nil, errors.New("invalid subject in token")
+		}
+		p.SubjectType = domain.SubjectTypeClient
+		p.ClientID = claims.Subject
+	case "", domain.SubjectTypeUser:
+		sub := claims.Subject
+		if sub == "" {
+			sub = claims.UserID
+		}
+		userID, err := strconv.ParseInt(sub, 10, 64)
+		if err != nil || userID <= 0 {
+			return nil, errors.New("invalid subject in token")
+		}
+		p.UserID = userID
+		p.ClientID = claims.ClientID
+		if claims.Actor != nil {
+			actorID, err := strconv.ParseInt(claims.Actor.Subject, 10, 64)
+			if err != nil || actorID <= 0 {
+				return nil, errors.New("invalid actor in token")
+			}
+			p.ActorUserID = actorID
+			p.ActorEmail = claims.Actor.Email
+		}
+	default:
+		return nil, errors.New("unknown subject type in token")
+	}
+	if claims.IssuedAt != nil {
+		p.IssuedAt = claims.IssuedAt.Unix()
+	}
+	if claims.ExpiresAt != nil {
+		p.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	return p, nil
+}
+
+// Revoke denylists the token's jti until the token would have expired on its own.
+func (j *jwtSigner) Revoke(ctx context.Context, tokenStr string) error {
+	claims, err := j.parse(tokenStr)
+	if err != nil {
+		return err
+	}
+	if claims.ID == "" {
+		return errors.New("token has no jti")
+	}
+	if j.revocations == nil {
+		return errors.New("token revocation is not configured")
+	}
+	return j.revocations.Revoke(ctx, claims.ID, claims.ExpiresAt.Time)
+}
+
+// RevokeSession denylists a session ID until every access token issued so far has expired
+// on its own, which rejects all of the session's tokens at once.
+func (j *jwtSigner) RevokeSession(ctx context.Context, sessionID string) error {
+	if sessionID == "" {
+		return errors.New("missing session id")
+	}
+	if j.revocations == nil {
+		return errors.New("token revocation is not configured")
+	}
+	return j.revocations.Revoke(ctx, sessionRevocationID(sessionID), time.Now().Add(j.exp))
+}
+
+// sessionRevocationID keys session IDs apart from token IDs in the revocation store.
+func sessionRevocationID(sessionID string) string {
+	if sessionID == "" {
+		return ""
+	}
+	return "sid:" + sessionID
+}
+
+func (j *jwtSigner) parse(tokenStr string) (*Claims, error) {
+	ring := j.keyRing()
 	claims := &Claims{}
 	token, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := ring.lookup(kid, time.Now())
+		if !ok {
+			return nil, errors.New("unknown or retired signing key")
+		}
+		// pinning the algorithm prevents e.g. an HS256 token "signed" with the RSA public key
+		if t.Method.Alg() != key.method.Alg() {
 			return nil, errors.New("invalid signing method")
 		}
-		return j.secret, nil
-	})
+		return key.verifyKey, nil
+	}, j.parserOptions()...)
 	if err != nil || !token.Valid {
-		return 0, errors.New("invalid token")
+		return nil, errors.New("invalid token")
 	}
-	if userID, err := strconv.ParseInt(claims.UserID, 10, 64); err == nil {
-		return userID, nil
+	return claims, nil
+}
+
+func (j *jwtSigner) parserOptions() []jwt.ParserOption {
+	opts := []jwt.ParserOption{jwt.WithExpirationRequired()}
+	if j.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(j.issuer))
+	}
+	if len(j.audience) > 0 {
+		opts = append(opts, jwt.WithAudience(j.audience...))
+	}
+	return opts
+}
+
+// JWKS returns the public keys other services can use to verify our tokens: every
+// asymmetric key of the ring that is not retired yet, active key first.
+func (j *jwtSigner) JWKS() dto.JWKSOutput {
+	ring := j.keyRing()
+	now := time.Now()
+	out := dto.JWKSOutput{Keys: []dto.JWK{}}
+	if jwk, ok := ring.active.publicJWK(); ok {
+		out.Keys = append(out.Keys, jwk)
+	}
+	kids := make([]string, 0, len(ring.keys))
+	for kid := range ring.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+	for _, kid := range kids {
+		k := ring.keys[kid]
+		if k.signingKey == ring.active || k.retired(now) {
+			continue
+		}
+		if jwk, ok := k.publicJWK(); ok {
+			out.Keys = append(out.Keys, jwk)
+		}
+	}
+	return out
+}
+
+// keyRing returns the current ring, re-reading it first when the refresh interval has
+// elapsed. Only one caller reloads at a time, without holding the lock, while the others
+// keep using the current ring. A failed reload keeps the previous ring and is retried after
+// another interval.
+func (j *jwtSigner) keyRing() *keyRing {
+	j.mu.RLock()
+	ring, stale := j.ring, j.reload != nil && !j.reloading && time.Since(j.loadedAt) >= j.reloadEvery
+	j.mu.RUnlock()
+	if !stale {
+		return ring
+	}
+
+	j.mu.Lock()
+	if j.reloading || time.Since(j.loadedAt) < j.reloadEvery {
+		ring = j.ring // another goroutine is reloading or just did
+		j.mu.Unlock()
+		return ring
+	}
+	j.reloading = true
+	j.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), keyRingReloadTimeout)
+	fresh, err := j.reload(ctx)
+	cancel()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.reloading = false
+	j.loadedAt = time.Now()
+	if err != nil {
+		log.Printf("Warning: failed to reload JWT key ring, keeping current keys: %v", err)
+		return j.ring
+	}
+	j.ring = fresh
+	return fresh
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
-	return 0, errors.New("invalid user_id in token")
+	return hex.EncodeToString(b), nil
 }
@@ -1,28 +1,38 @@
 package auth
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
 	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/datasource"
 )
 
+func newHS256Signer(secret string, exp time.Duration) *jwtSigner {
+	key, _ := newSigningKey("", "HS256", secret, "")
+	return &jwtSigner{ring: singleKeyRing(key), exp: exp}
+}
+
 func TestNewJWTSigner(t *testing.T) {
 	cfg := &config.Config{
 		JWTSecret:     "test-secret",
 		JWTExpiration: time.Hour,
 	}
 
-	signer := NewJWTSigner(cfg)
+	signer, err := NewJWTSigner(cfg, nil)
+	assert.NoError(t, err)
 	assert.NotNil(t, signer)
 
 	// Test that it implements the interface
 	jwtSignerImpl, ok := signer.(*jwtSigner)
 	assert.True(t, ok)
-	assert.Equal(t, []byte("test-secret"), jwtSignerImpl.secret)
+	assert.Equal(t, jwt.SigningMethodHS256, jwtSignerImpl.ring.active.method)
+	assert.Equal(t, []byte("test-secret"), jwtSignerImpl.ring.active.signKey)
 	assert.Equal(t, time.Hour, jwtSignerImpl.exp)
 }
 
@@ -31,7 +41,7 @@ func TestJWTSigner_Sign(t *testing.T) {
 		JWTSecret:     "test-secret",
 		JWTExpiration: time.Hour,
 	}
-	signer := NewJWTSigner(cfg)
+	signer, _ := NewJWTSigner(cfg, nil)
 
 	tests := []struct {
 		name   string
@@ -49,12 +59,12 @@ func TestJWTSigner_Sign(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			token, err := signer.Sign(tt.userID)
+			token, err := signer.Sign(domain.Principal{UserID: tt.userID})
 			assert.NoError(t, err)
 			assert.NotEmpty(t, token)
 
 			// Verify the token can be parsed back
-			userID, err := signer.Verify(token)
+			userID, _, err := signer.Verify(context.Background(), token)
 			assert.NoError(t, err)
 			assert.Equal(t, tt.userID, userID)
 		})
@@ -66,7 +76,7 @@ func TestJWTSigner_Verify(t *testing.T) {
 		JWTSecret:     "test-secret",
 		JWTExpiration: time.Hour,
 	}
-	signer := NewJWTSigner(cfg)
+	signer, _ := NewJWTSigner(cfg, nil)
 
 	tests := []struct {
 		name        string
@@ -77,7 +87,7 @@ func TestJWTSigner_Verify(t *testing.T) {
 		{
 			name: "should verify valid token successfully",
 			setupToken: func() string {
-				token, _ := signer.Sign(123)
+				token, _ := signer.Sign(domain.Principal{UserID: 123})
 				return token
 			},
 			expectedID:  123,
@@ -120,11 +130,8 @@ func TestJWTSigner_Verify(t *testing.T) {
 		{
 			name: "should return error for token with wrong secret",
 			setupToken: func() string {
-				wrongSigner := &jwtSigner{
-					secret: []byte("wrong-secret"),
-					exp:    time.Hour,
-				}
-				token, _ := wrongSigner.Sign(123)
+				wrongSigner := newHS256Signer("wrong-secret", time.Hour)
+				token, _ := wrongSigner.Sign(domain.Principal{UserID: 123})
 				return token
 			},
 			expectedID:  0,
@@ -133,11 +140,8 @@ func TestJWTSigner_Verify(t *testing.T) {
 		{
 			name: "should return error for expired token",
 			setupToken: func() string {
-				expiredSigner := &jwtSigner{
-					secret: []byte("test-secret"),
-					exp:    -time.Hour, // Expired
-				}
-				token, _ := expiredSigner.Sign(123)
+				expiredSigner := newHS256Signer("test-secret", -time.Hour) // Expired
+				token, _ := expiredSigner.Sign(domain.Principal{UserID: 123})
 				return token
 			},
 			expectedID:  0,
@@ -165,7 +169,7 @@ func TestJWTSigner_Verify(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			token := tt.setupToken()
-			userID, err := signer.Verify(token)
+			userID, _, err := signer.Verify(context.Background(), token)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -177,3 +181,326 @@ func TestJWTSigner_Verify(t *testing.T) {
 		})
 	}
 }
+
+func TestJWTSigner_Sign_SetsUniqueTokenID(t *testing.T) {
+	signer := newHS256Signer("test-secret", time.Hour)
+
+	first, err := signer.Sign(domain.Principal{UserID: 123})
+	assert.NoError(t, err)
+	second, err := signer.Sign(domain.Principal{UserID: 123})
+	assert.NoError(t, err)
+
+	firstClaims, err := signer.parse(first)
+	assert.NoError(t, err)
+	secondClaims, err := signer.parse(second)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, firstClaims.ID)
+	assert.NotEqual(t, firstClaims.ID, secondClaims.ID)
+}
+
+func TestJWTSigner_Revoke(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		JWTSecret:     "test-secret",
+		JWTExpiration: time.Hour,
+	}
+
+	t.Run("should reject revoked token and keep others valid", func(t *testing.T) {
+		signer, _ := NewJWTSigner(cfg, datasource.NewMemoryTokenRevocationStore())
+		revoked, _ := signer.Sign(domain.Principal{UserID: 123})
+		other, _ := signer.Sign(domain.Principal{UserID: 123})
+
+		assert.NoError(t, signer.Revoke(ctx, revoked))
+
+		_, _, err := signer.Verify(ctx, revoked)
+		assert.ErrorIs(t, err, ErrTokenRevoked)
+		userID, _, err := signer.Verify(ctx, other)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(123), userID)
+	})
+
+	t.Run("should return error when revoking an invalid token", func(t *testing.T) {
+		signer, _ := NewJWTSigner(cfg, datasource.NewMemoryTokenRevocationStore())
+		assert.Error(t, signer.Revoke(ctx, "invalid.token.here"))
+	})
+
+	t.Run("should return error when no revocation store is configured", func(t *testing.T) {
+		signer, _ := NewJWTSigner(cfg, nil)
+		token, _ := signer.Sign(domain.Principal{UserID: 123})
+		assert.Error(t, signer.Revoke(ctx, token))
+	})
+}
+
+func TestJWTSigner_RevokeSession(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		JWTSecret:     "test-secret",
+		JWTExpiration: time.Hour,
+	}
+
+	t.Run("should reject every token of the session and keep others valid", func(t *testing.T) {
+		signer, _ := NewJWTSigner(cfg, datasource.NewMemoryTokenRevocationStore())
+		first, _ := signer.Sign(domain.Principal{UserID: 123, SessionID: "sess-1"})
+		second, _ := signer.Sign(domain.Principal{UserID: 123, SessionID: "sess-1"})
+		other, _ := signer.Sign(domain.Principal{UserID: 123, SessionID: "sess-2"})
+
+		assert.NoError(t, signer.RevokeSession(ctx, "sess-1"))
+
+		_, err := signer.VerifyPrincipal(ctx, first)
+		assert.ErrorIs(t, err, ErrTokenRevoked)
+		_, err = signer.VerifyPrincipal(ctx, second)
+		assert.ErrorIs(t, err, ErrTokenRevoked)
+		p, err := signer.VerifyPrincipal(ctx, other)
+		assert.NoError(t, err)
+		assert.Equal(t, "sess-2", p.SessionID)
+	})
+
+	t.Run("should return error when no revocation store is configured", func(t *testing.T) {
+		signer, _ := NewJWTSigner(cfg, nil)
+		assert.Error(t, signer.RevokeSession(ctx, "sess-1"))
+	})
+}
+
+func TestJWTSigner_VerifyPrincipal(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		JWTSecret:     "test-secret",
+		JWTExpiration: time.Hour,
+		JWTIssuer:     "https://users.example.com",
+		JWTAudience:   []string{"video-api", "admin-api"},
+	}
+	signer, err := NewJWTSigner(cfg, nil)
+	assert.NoError(t, err)
+
+	t.Run("should carry subject, email and roles", func(t *testing.T) {
+		token, err := signer.Sign(domain.Principal{UserID: 9, Email: "a@a.com", Roles: []string{"admin"}})
+		assert.NoError(t, err)
+
+		p, err := signer.VerifyPrincipal(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(9), p.UserID)
+		assert.False(t, p.IsClient())
+		assert.Equal(t, "a@a.com", p.Email)
+		assert.Equal(t, []string{"admin"}, p.Roles)
+		assert.True(t, p.HasRole("admin"))
+		assert.NotEmpty(t, p.TokenID)
+		assert.Greater(t, p.ExpiresAt, p.IssuedAt)
+
+		claims, err := signer.(*jwtSigner).parse(token)
+		assert.NoError(t, err)
+		assert.Equal(t, "9", claims.Subject)
+		assert.Equal(t, "https://users.example.com", claims.Issuer)
+		assert.Equal(t, jwt.ClaimStrings{"video-api", "admin-api"}, claims.Audience)
+		assert.Empty(t, claims.UserID)
+	})
+
+	t.Run("should omit optional claims when absent", func(t *testing.T) {
+		token, err := signer.Sign(domain.Principal{UserID: 9})
+		assert.NoError(t, err)
+		p, err := signer.VerifyPrincipal(ctx, token)
+		assert.NoError(t, err)
+		assert.Empty(t, p.Email)
+		assert.Empty(t, p.Roles)
+	})
+
+	t.Run("should round-trip scopes as a space-delimited claim", func(t *testing.T) {
+		token, err := signer.Sign(domain.Principal{UserID: 9, Scopes: []string{"videos:read", "videos:write"}})
+		assert.NoError(t, err)
+		p, err := signer.VerifyPrincipal(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"videos:read", "videos:write"}, p.Scopes)
+
+		claims, err := signer.(*jwtSigner).parse(token)
+		assert.NoError(t, err)
+		assert.Equal(t, "videos:read videos:write", claims.Scope)
+	})
+
+	foreignClaims := func(iss string, aud ...string) string {
+		claims := Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "9",
+				Issuer:    iss,
+				Audience:  aud,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}
+		tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+		return tokenString
+	}
+
+	tests := []struct {
+		name        string
+		token       string
+		expectError bool
+	}{
+		{name: "should accept token for one of the audiences", token: foreignClaims("https://users.example.com", "admin-api")},
+		{name: "should reject token from another issuer", token: foreignClaims("https://evil.example.com", "video-api"), expectError: true},
+		{name: "should reject token for another audience", token: foreignClaims("https://users.example.com", "billing-api"), expectError: true},
+		{name: "should reject token without audience", token: foreignClaims("https://users.example.com"), expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signer.VerifyPrincipal(ctx, tt.token)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestJWTSigner_ClientTokens(t *testing.T) {
+	ctx := context.Background()
+	signer := newHS256Signer("test-secret", time.Hour)
+
+	expiresAt := time.Now().Add(5 * time.Minute).Unix()
+	token, err := signer.Sign(domain.Principal{
+		SubjectType: domain.SubjectTypeClient,
+		ClientID:    "video-worker",
+		Scopes:      []string{"users:read"},
+		ExpiresAt:   expiresAt,
+	})
+	assert.NoError(t, err)
+
+	claims, err := signer.parse(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "video-worker", claims.Subject)
+	assert.Equal(t, "video-worker", claims.ClientID)
+	assert.Equal(t, "client", claims.SubjectType)
+	assert.Equal(t, expiresAt, claims.ExpiresAt.Unix())
+
+	p, err := signer.VerifyPrincipal(ctx, token)
+	assert.NoError(t, err)
+	assert.True(t, p.IsClient())
+	assert.Equal(t, "video-worker", p.ClientID)
+	assert.Zero(t, p.UserID)
+	assert.True(t, p.HasScope("users:read"))
+
+	_, _, err = signer.Verify(ctx, token)
+	assert.ErrorIs(t, err, ErrNotUserToken)
+}
+
+func TestJWTSigner_VerifyLegacyUserIDClaim(t *testing.T) {
+	signer := newHS256Signer("test-secret", time.Hour)
+	claims := Claims{
+		UserID: "123",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+
+	userID, _, err := signer.Verify(context.Background(), tokenString)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(123), userID)
+}
+
+func TestJWTSigner_DelegatedUserTokens(t *testing.T) {
+	signer := newHS256Signer("test-secret", time.Hour)
+
+	token, err := signer.Sign(domain.Principal{UserID: 7, ClientID: "partner-app", Scopes: []string{"openid", "email"}})
+	assert.NoError(t, err)
+
+	p, err := signer.VerifyPrincipal(context.Background(), token)
+	assert.NoError(t, err)
+	assert.False(t, p.IsClient())
+	assert.True(t, p.IsDelegated())
+	assert.Equal(t, int64(7), p.UserID)
+	assert.Equal(t, "partner-app", p.ClientID)
+}
+
+func TestJWTSigner_ImpersonationTokens(t *testing.T) {
+	signer := newHS256Signer("test-secret", time.Hour)
+	ctx := context.Background()
+
+	token, err := signer.Sign(domain.Principal{UserID: 7, ActorUserID: 1, ActorEmail: "admin@example.com"})
+	assert.NoError(t, err)
+
+	claims := &Claims{}
+	_, _, err = jwt.NewParser().ParseUnverified(token, claims)
+	assert.NoError(t, err)
+	assert.Equal(t, "7", claims.Subject)
+	assert.Equal(t, &actor{Subject: "1", Email: "admin@example.com"}, claims.Actor)
+
+	p, err := signer.VerifyPrincipal(ctx, token)
+	assert.NoError(t, err)
+	assert.True(t, p.IsImpersonated())
+	assert.Equal(t, int64(7), p.UserID)
+	assert.Equal(t, int64(1), p.ActorUserID)
+	assert.Equal(t, "admin@example.com", p.ActorEmail)
+
+	userID, actorUserID, err := signer.Verify(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), userID)
+	assert.Equal(t, int64(1), actorUserID)
+
+	// regular tokens carry no actor
+	token, _ = signer.Sign(domain.Principal{UserID: 7})
+	_, actorUserID, err = signer.Verify(ctx, token)
+	assert.NoError(t, err)
+	assert.Zero(t, actorUserID)
+
+	// a malformed actor is rejected
+	bad := Claims{
+		SubjectType: "user",
+		Actor:       &actor{Subject: "admin"},
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "7",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, _ = jwt.NewWithClaims(jwt.SigningMethodHS256, bad).SignedString([]byte("test-secret"))
+	_, err = signer.VerifyPrincipal(ctx, token)
+	assert.Error(t, err)
+}
+
+func TestJWTSigner_SignIDToken(t *testing.T) {
+	signer := newHS256Signer("test-secret", time.Hour)
+	signer.issuer = "https://auth.example.com"
+	signer.audience = []string{"hackathon-api"}
+
+	token, err := signer.SignIDToken(domain.IDToken{
+		UserID:        7,
+		Audience:      "partner-app",
+		Nonce:         "n-0S6_WzA2Mj",
+		AuthTime:      1700000000,
+		Email:         "john@example.com",
+		EmailVerified: false,
+		Name:          "John Doe",
+	})
+	assert.NoError(t, err)
+
+	claims := &idTokenClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return []byte("test-secret"), nil })
+	assert.NoError(t, err)
+	assert.Equal(t, "7", claims.Subject)
+	assert.Equal(t, "https://auth.example.com", claims.Issuer)
+	assert.Equal(t, jwt.ClaimStrings{"partner-app"}, claims.Audience)
+	assert.Equal(t, "n-0S6_WzA2Mj", claims.Nonce)
+	assert.Equal(t, int64(1700000000), claims.AuthTime.Unix())
+	assert.Equal(t, "john@example.com", claims.Email)
+	if assert.NotNil(t, claims.EmailVerified) {
+		assert.False(t, *claims.EmailVerified)
+	}
+	assert.Equal(t, "John Doe", claims.Name)
+
+	// an ID token must never pass as an access token, even with a matching audience
+	signer.audience = []string{"partner-app"}
+	_, err = signer.VerifyPrincipal(context.Background(), token)
+	assert.ErrorIs(t, err, ErrIDToken)
+}
+
+func TestJWTSigner_SignIDToken_WithoutEmailScope(t *testing.T) {
+	signer := newHS256Signer("test-secret", time.Hour)
+
+	token, err := signer.SignIDToken(domain.IDToken{UserID: 7, Audience: "partner-app"})
+	assert.NoError(t, err)
+
+	claims := &idTokenClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return []byte("test-secret"), nil })
+	assert.NoError(t, err)
+	assert.Empty(t, claims.Email)
+	assert.Nil(t, claims.EmailVerified)
+	assert.Nil(t, claims.AuthTime)
+}
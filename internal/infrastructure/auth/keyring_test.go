@@ -0,0 +1,237 @@
+package auth
+
+import (
+	"context"
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+)
+
+type testRingKey struct {
+	Kid        string     `json:"kid"`
+	Alg        string     `json:"alg"`
+	Secret     string     `json:"secret,omitempty"`
+	PrivateKey string     `json:"private_key,omitempty"`
+	RetireAt   *time.Time `json:"retire_at,omitempty"`
+}
+
+func keyRingJSON(t *testing.T, active string, keys ...testRingKey) string {
+	t.Helper()
+	b, err := json.Marshal(map[string]any{"active": active, "keys": keys})
+	require.NoError(t, err)
+	return string(b)
+}
+
+func tokenKid(t *testing.T, token string) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+	require.NoError(t, err)
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestJWTSigner_KeyRingRotation(t *testing.T) {
+	ctx := context.Background()
+	oldPEM := ecPrivateKeyPEM(t, elliptic.P256())
+	newPEM := ecPrivateKeyPEM(t, elliptic.P256())
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Minute)
+
+	oldSigner, err := NewJWTSigner(&config.Config{
+		JWTKeyRing:    keyRingJSON(t, "k1", testRingKey{Kid: "k1", Alg: "ES256", PrivateKey: oldPEM}),
+		JWTExpiration: time.Hour,
+	}, nil)
+	require.NoError(t, err)
+	oldToken, err := oldSigner.Sign(domain.Principal{UserID: 7})
+	require.NoError(t, err)
+	assert.Equal(t, "k1", tokenKid(t, oldToken))
+
+	t.Run("should sign with active key and accept keys within retirement window", func(t *testing.T) {
+		signer, err := NewJWTSigner(&config.Config{
+			JWTKeyRing: keyRingJSON(t, "k2",
+				testRingKey{Kid: "k1", Alg: "ES256", PrivateKey: oldPEM, RetireAt: &future},
+				testRingKey{Kid: "k2", Alg: "ES256", PrivateKey: newPEM},
+			),
+			JWTExpiration: time.Hour,
+		}, nil)
+		require.NoError(t, err)
+
+		newToken, err := signer.Sign(domain.Principal{UserID: 7})
+		require.NoError(t, err)
+		assert.Equal(t, "k2", tokenKid(t, newToken))
+
+		for _, tok := range []string{oldToken, newToken} {
+			userID, _, err := signer.Verify(ctx, tok)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(7), userID)
+		}
+
+		jwks := signer.JWKS()
+		require.Len(t, jwks.Keys, 2)
+		assert.Equal(t, "k2", jwks.Keys[0].Kid)
+		assert.Equal(t, "k1", jwks.Keys[1].Kid)
+	})
+
+	t.Run("should reject tokens signed with a retired key", func(t *testing.T) {
+		signer, err := NewJWTSigner(&config.Config{
+			JWTKeyRing: keyRingJSON(t, "k2",
+				testRingKey{Kid: "k1", Alg: "ES256", PrivateKey: oldPEM, RetireAt: &past},
+				testRingKey{Kid: "k2", Alg: "ES256", PrivateKey: newPEM},
+			),
+			JWTExpiration: time.Hour,
+		}, nil)
+		require.NoError(t, err)
+
+		_, _, err = signer.Verify(ctx, oldToken)
+		assert.Error(t, err)
+		require.Len(t, signer.JWKS().Keys, 1)
+	})
+
+	t.Run("should reject tokens with an unknown kid", func(t *testing.T) {
+		signer, err := NewJWTSigner(&config.Config{
+			JWTKeyRing:    keyRingJSON(t, "k2", testRingKey{Kid: "k2", Alg: "ES256", PrivateKey: newPEM}),
+			JWTExpiration: time.Hour,
+		}, nil)
+		require.NoError(t, err)
+
+		_, _, err = signer.Verify(ctx, oldToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("should not let a kid borrow another key's algorithm", func(t *testing.T) {
+		signer, err := NewJWTSigner(&config.Config{
+			JWTKeyRing: keyRingJSON(t, "hs",
+				testRingKey{Kid: "hs", Alg: "HS256", Secret: "ring-secret"},
+				testRingKey{Kid: "k1", Alg: "ES256", PrivateKey: oldPEM},
+			),
+			JWTExpiration: time.Hour,
+		}, nil)
+		require.NoError(t, err)
+
+		forged := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+			UserID:           "7",
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(future)},
+		})
+		forged.Header["kid"] = "k1"
+		tokenStr, err := forged.SignedString([]byte("ring-secret"))
+		require.NoError(t, err)
+
+		_, _, err = signer.Verify(ctx, tokenStr)
+		assert.Error(t, err)
+	})
+}
+
+func TestJWTSigner_KeyRingReload(t *testing.T) {
+	ctx := context.Background()
+	first, err := parseKeyRing(keyRingJSON(t, "a", testRingKey{Kid: "a", Alg: "HS256", Secret: "secret-a"}), time.Now())
+	require.NoError(t, err)
+	second, err := parseKeyRing(keyRingJSON(t, "b",
+		testRingKey{Kid: "a", Alg: "HS256", Secret: "secret-a"},
+		testRingKey{Kid: "b", Alg: "HS256", Secret: "secret-b"},
+	), time.Now())
+	require.NoError(t, err)
+
+	var next *keyRing
+	var reloadErr error
+	calls := 0
+	signer := &jwtSigner{
+		exp:         time.Hour,
+		ring:        first,
+		loadedAt:    time.Now(),
+		reloadEvery: time.Hour,
+		reload: func(context.Context) (*keyRing, error) {
+			calls++
+			return next, reloadErr
+		},
+	}
+
+	tokenA, err := signer.Sign(domain.Principal{UserID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "a", tokenKid(t, tokenA))
+	assert.Equal(t, 0, calls, "ring is fresh, no reload expected")
+
+	// a failing reload keeps the previous ring
+	signer.loadedAt = time.Now().Add(-2 * time.Hour)
+	reloadErr = errors.New("ssm unavailable")
+	tokenA2, err := signer.Sign(domain.Principal{UserID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "a", tokenKid(t, tokenA2))
+	assert.Equal(t, 1, calls)
+
+	// once the interval elapses again the new ring is picked up
+	signer.loadedAt = time.Now().Add(-2 * time.Hour)
+	next, reloadErr = second, nil
+	tokenB, err := signer.Sign(domain.Principal{UserID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "b", tokenKid(t, tokenB))
+	assert.Equal(t, 2, calls)
+
+	_, _, err = signer.Verify(ctx, tokenA)
+	assert.NoError(t, err)
+}
+
+func TestJWTSigner_KeyRingReloadDoesNotBlockOthers(t *testing.T) {
+	first, err := parseKeyRing(keyRingJSON(t, "a", testRingKey{Kid: "a", Alg: "HS256", Secret: "secret-a"}), time.Now())
+	require.NoError(t, err)
+	second, err := parseKeyRing(keyRingJSON(t, "b", testRingKey{Kid: "b", Alg: "HS256", Secret: "secret-b"}), time.Now())
+	require.NoError(t, err)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	signer := &jwtSigner{
+		exp:         time.Hour,
+		ring:        first,
+		loadedAt:    time.Now().Add(-2 * time.Hour),
+		reloadEvery: time.Hour,
+		reload: func(ctx context.Context) (*keyRing, error) {
+			_, bounded := ctx.Deadline()
+			assert.True(t, bounded, "reloads must have a timeout")
+			close(started)
+			<-release
+			return second, nil
+		},
+	}
+
+	reloaded := make(chan *keyRing)
+	go func() { reloaded <- signer.keyRing() }()
+	<-started
+
+	// while the reload hangs, other callers keep signing with the current ring
+	token, err := signer.Sign(domain.Principal{UserID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "a", tokenKid(t, token))
+
+	close(release)
+	assert.Same(t, second, <-reloaded)
+	assert.Same(t, second, signer.keyRing())
+}
+
+func TestParseKeyRing_Errors(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{name: "invalid JSON", raw: "{"},
+		{name: "missing kid", raw: keyRingJSON(t, "", testRingKey{Alg: "HS256", Secret: "s"})},
+		{name: "duplicate kid", raw: keyRingJSON(t, "a", testRingKey{Kid: "a", Alg: "HS256", Secret: "s"}, testRingKey{Kid: "a", Alg: "HS256", Secret: "t"})},
+		{name: "unknown active kid", raw: keyRingJSON(t, "b", testRingKey{Kid: "a", Alg: "HS256", Secret: "s"})},
+		{name: "retired active key", raw: keyRingJSON(t, "a", testRingKey{Kid: "a", Alg: "HS256", Secret: "s", RetireAt: &past})},
+		{name: "invalid key material", raw: keyRingJSON(t, "a", testRingKey{Kid: "a", Alg: "RS256", PrivateKey: "nope"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseKeyRing(tt.raw, time.Now())
+			assert.Error(t, err)
+		})
+	}
+}
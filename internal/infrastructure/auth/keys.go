@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+)
+
+// signingKey pairs a JWT signing method with the key material used to sign and verify.
+// For HS256 both sides are the shared secret; for RS256/ES256 only the public half is
+// ever published through the JWKS.
+type signingKey struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   any
+	verifyKey any
+}
+
+// newSigningKey builds a key for the given algorithm. HS256 uses secret; RS256 and ES256
+// expect a PEM-encoded private key (PKCS#1, SEC 1 or PKCS#8). When id is empty the RFC 7638
+// thumbprint of the public key is used, so asymmetric keys always carry a stable kid.
+func newSigningKey(id, alg, secret, privateKeyPEM string) (*signingKey, error) {
+	switch alg {
+	case "", jwt.SigningMethodHS256.Alg():
+		if secret == "" {
+			return nil, errors.New("HS256 requires a non-empty secret")
+		}
+		return &signingKey{id: id, method: jwt.SigningMethodHS256, signKey: []byte(secret), verifyKey: []byte(secret)}, nil
+	case jwt.SigningMethodRS256.Alg():
+		priv, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(privateKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("parse RS256 private key: %w", err)
+		}
+		return withThumbprint(&signingKey{id: id, method: jwt.SigningMethodRS256, signKey: priv, verifyKey: &priv.PublicKey})
+	case jwt.SigningMethodES256.Alg():
+		priv, err := jwt.ParseECPrivateKeyFromPEM([]byte(privateKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("parse ES256 private key: %w", err)
+		}
+		if priv.Curve != elliptic.P256() {
+			return nil, errors.New("ES256 requires a P-256 key")
+		}
+		return withThumbprint(&signingKey{id: id, method: jwt.SigningMethodES256, signKey: priv, verifyKey: &priv.PublicKey})
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", alg)
+	}
+}
+
+func withThumbprint(k *signingKey) (*signingKey, error) {
+	if k.id != "" {
+		return k, nil
+	}
+	jwk, ok := k.publicJWK()
+	if !ok {
+		return k, nil
+	}
+	// RFC 7638: required members only, lexicographic order, no whitespace
+	var members any
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	}
+	b, err := json.Marshal(members)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(b)
+	k.id = base64.RawURLEncoding.EncodeToString(sum[:])
+	return k, nil
+}
+
+// publicJWK returns the public half of the key as a JWK; symmetric keys have none.
+func (k *signingKey) publicJWK() (dto.JWK, bool) {
+	switch pub := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		return dto.JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: k.method.Alg(),
+			Kid: k.id,
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, true
+	case *ecdsa.PublicKey:
+		ecdhPub, err := pub.ECDH()
+		if err != nil {
+			return dto.JWK{}, false
+		}
+		// uncompressed point: 0x04 || X || Y
+		raw := ecdhPub.Bytes()[1:]
+		size := len(raw) / 2
+		return dto.JWK{
+			Kty: "EC",
+			Use: "sig",
+			Alg: k.method.Alg(),
+			Kid: k.id,
+			Crv: pub.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(raw[:size]),
+			Y:   base64.RawURLEncoding.EncodeToString(raw[size:]),
+		}, true
+	default:
+		return dto.JWK{}, false
+	}
+}
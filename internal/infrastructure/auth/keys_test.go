@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+)
+
+func rsaPrivateKeyPEM(t *testing.T) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+func ecPrivateKeyPEM(t *testing.T, curve elliptic.Curve) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+// publicKeyFromJWK rebuilds a verification key from its JWK, the way a downstream service would.
+func publicKeyFromJWK(t *testing.T, k dto.JWK) any {
+	t.Helper()
+	decode := func(s string) *big.Int {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		require.NoError(t, err)
+		return new(big.Int).SetBytes(b)
+	}
+	switch k.Kty {
+	case "RSA":
+		return &rsa.PublicKey{N: decode(k.N), E: int(decode(k.E).Int64())}
+	case "EC":
+		uncompressed := append([]byte{4}, decode(k.X).FillBytes(make([]byte, 32))...)
+		uncompressed = append(uncompressed, decode(k.Y).FillBytes(make([]byte, 32))...)
+		pub, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), uncompressed)
+		require.NoError(t, err)
+		return pub
+	}
+	t.Fatalf("unexpected kty %q", k.Kty)
+	return nil
+}
+
+func TestJWTSigner_AsymmetricAlgorithms(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		alg  string
+		kty  string
+		pem  string
+	}{
+		{name: "RS256", alg: "RS256", kty: "RSA", pem: rsaPrivateKeyPEM(t)},
+		{name: "ES256", alg: "ES256", kty: "EC", pem: ecPrivateKeyPEM(t, elliptic.P256())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := NewJWTSigner(&config.Config{JWTAlgorithm: tt.alg, JWTPrivateKey: tt.pem, JWTExpiration: time.Hour}, nil)
+			require.NoError(t, err)
+
+			token, err := signer.Sign(domain.Principal{UserID: 42})
+			require.NoError(t, err)
+			userID, _, err := signer.Verify(ctx, token)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(42), userID)
+
+			jwks := signer.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			jwk := jwks.Keys[0]
+			assert.Equal(t, tt.kty, jwk.Kty)
+			assert.Equal(t, tt.alg, jwk.Alg)
+			assert.Equal(t, "sig", jwk.Use)
+			assert.NotEmpty(t, jwk.Kid)
+
+			// a third party holding only the JWKS can verify the token
+			parsed, err := jwt.ParseWithClaims(token, &Claims{}, func(tok *jwt.Token) (interface{}, error) {
+				assert.Equal(t, jwk.Kid, tok.Header["kid"])
+				return publicKeyFromJWK(t, jwk), nil
+			}, jwt.WithValidMethods([]string{tt.alg}))
+			require.NoError(t, err)
+			assert.Equal(t, "42", parsed.Claims.(*Claims).Subject)
+		})
+	}
+}
+
+func TestJWTSigner_RejectsAlgorithmConfusion(t *testing.T) {
+	pemKey := rsaPrivateKeyPEM(t)
+	signer, err := NewJWTSigner(&config.Config{JWTAlgorithm: "RS256", JWTPrivateKey: pemKey, JWTExpiration: time.Hour}, nil)
+	require.NoError(t, err)
+
+	// forge an HS256 token using the (public) RSA key bytes as the HMAC secret
+	pub := signer.(*jwtSigner).ring.active.verifyKey.(*rsa.PublicKey)
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		UserID: "1",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	tokenStr, err := forged.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+	require.NoError(t, err)
+
+	_, _, err = signer.Verify(context.Background(), tokenStr)
+	assert.Error(t, err)
+}
+
+func TestJWTSigner_JWKS_EmptyForHS256(t *testing.T) {
+	signer := newHS256Signer("test-secret", time.Hour)
+	assert.Empty(t, signer.JWKS().Keys)
+}
+
+func TestNewSigningKey_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		alg    string
+		secret string
+		pem    string
+	}{
+		{name: "HS256 without secret", alg: "HS256"},
+		{name: "RS256 with invalid PEM", alg: "RS256", pem: "not a key"},
+		{name: "ES256 with RSA key", alg: "ES256", pem: rsaPrivateKeyPEM(t)},
+		{name: "ES256 with P-384 key", alg: "ES256", pem: ecPrivateKeyPEM(t, elliptic.P384())},
+		{name: "unsupported algorithm", alg: "PS512", secret: "s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newSigningKey("", tt.alg, tt.secret, tt.pem)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+)
+
+const (
+	argon2idPrefix  = "$argon2id$"
+	argon2SaltBytes = 16
+	argon2KeyBytes  = 32
+)
+
+var errUnknownPasswordHash = errors.New("unrecognized password hash format")
+
+// Both hashers verify hashes of either algorithm, so switching algorithms keeps existing
+// passwords working until they are rehashed.
+
+type bcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher returns a hasher producing bcrypt hashes ("$2a$<cost>$...") of the given cost.
+func NewBcryptHasher(cost int) (port.PasswordHasher, error) {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	return &bcryptHasher{cost: cost}, nil
+}
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	return string(hash), err
+}
+
+func (h *bcryptHasher) Verify(hash, password string) (bool, error) {
+	return verifyPassword(hash, password)
+}
+
+func (h *bcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.cost
+}
+
+// argon2Params are the argon2id settings encoded in a PHC-style hash:
+// $argon2id$v=19$m=<memory KiB>,t=<iterations>,p=<parallelism>$<salt>$<key>.
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+type argon2idHasher struct {
+	params argon2Params
+}
+
+// NewArgon2idHasher returns a hasher producing argon2id hashes that use memoryKiB of memory,
+// iterations passes and parallelism lanes.
+func NewArgon2idHasher(memoryKiB, iterations uint32, parallelism uint8) (port.PasswordHasher, error) {
+	if memoryKiB < 8*uint32(parallelism) || iterations == 0 || parallelism == 0 {
+		return nil, errors.New("argon2id needs at least one iteration, one lane and 8 KiB of memory per lane")
+	}
+	return &argon2idHasher{params: argon2Params{memory: memoryKiB, iterations: iterations, parallelism: parallelism}}, nil
+}
+
+func (h *argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	p := h.params
+	key := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, argon2KeyBytes)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, p.memory, p.iterations, p.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h *argon2idHasher) Verify(hash, password string) (bool, error) {
+	return verifyPassword(hash, password)
+}
+
+func (h *argon2idHasher) NeedsRehash(hash string) bool {
+	p, _, key, err := parseArgon2id(hash)
+	return err != nil || p != h.params || len(key) != argon2KeyBytes
+}
+
+// verifyPassword checks password against a bcrypt or argon2id hash.
+func verifyPassword(hash, password string) (bool, error) {
+	if strings.HasPrefix(hash, argon2idPrefix) {
+		p, salt, key, err := parseArgon2id(hash)
+		if err != nil {
+			return false, err
+		}
+		got := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(got, key) == 1, nil
+	}
+	if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+		return false, errUnknownPasswordHash
+	}
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func parseArgon2id(hash string) (argon2Params, []byte, []byte, error) {
+	var p argon2Params
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, errUnknownPasswordHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, fmt.Errorf("unsupported argon2id version %q", parts[2])
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return p, nil, nil, fmt.Errorf("parse argon2id parameters: %w", err)
+	}
+	if p.iterations == 0 || p.parallelism == 0 {
+		return p, nil, nil, errors.New("invalid argon2id parameters")
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("decode argon2id salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, errors.New("decode argon2id key")
+	}
+	return p, salt, key, nil
+}
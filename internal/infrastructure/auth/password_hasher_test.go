@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestArgon2idHasher_RoundTrip(t *testing.T) {
+	h, err := NewArgon2idHasher(64, 1, 1)
+	require.NoError(t, err)
+
+	hash, err := h.Hash("correct horse")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"), hash)
+
+	other, err := h.Hash("correct horse")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other, "every hash uses a fresh salt")
+
+	ok, err := h.Verify(hash, "correct horse")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = h.Verify(hash, "wrong horse")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.False(t, h.NeedsRehash(hash))
+}
+
+func TestBcryptHasher_RoundTrip(t *testing.T) {
+	h, err := NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+
+	hash, err := h.Hash("correct horse")
+	require.NoError(t, err)
+
+	ok, err := h.Verify(hash, "correct horse")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = h.Verify(hash, "wrong horse")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.False(t, h.NeedsRehash(hash))
+}
+
+func TestPasswordHashers_VerifyEachOthersHashes(t *testing.T) {
+	bc, err := NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+	a2, err := NewArgon2idHasher(64, 1, 1)
+	require.NoError(t, err)
+
+	bcHash, err := bc.Hash("correct horse")
+	require.NoError(t, err)
+	a2Hash, err := a2.Hash("correct horse")
+	require.NoError(t, err)
+
+	ok, err := a2.Verify(bcHash, "correct horse")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, a2.NeedsRehash(bcHash), "another algorithm needs a rehash")
+
+	ok, err = bc.Verify(a2Hash, "correct horse")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, bc.NeedsRehash(a2Hash), "another algorithm needs a rehash")
+}
+
+func TestPasswordHashers_NeedsRehashOnParameterChange(t *testing.T) {
+	oldBcrypt, _ := NewBcryptHasher(bcrypt.MinCost)
+	newBcrypt, _ := NewBcryptHasher(bcrypt.MinCost + 1)
+	hash, err := oldBcrypt.Hash("correct horse")
+	require.NoError(t, err)
+	assert.True(t, newBcrypt.NeedsRehash(hash))
+
+	oldArgon, _ := NewArgon2idHasher(64, 1, 1)
+	newArgon, _ := NewArgon2idHasher(64, 2, 1)
+	hash, err = oldArgon.Hash("correct horse")
+	require.NoError(t, err)
+	assert.True(t, newArgon.NeedsRehash(hash))
+}
+
+func TestPasswordHashers_RejectUnknownHashes(t *testing.T) {
+	h, _ := NewArgon2idHasher(64, 1, 1)
+	for _, hash := range []string{"", "plaintext", "$argon2i$v=19$m=64,t=1,p=1$c2FsdA$a2V5", "$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5"} {
+		_, err := h.Verify(hash, "correct horse")
+		assert.Error(t, err, hash)
+		assert.True(t, h.NeedsRehash(hash), hash)
+	}
+}
+
+func TestPasswordHasherConstructors_ValidateParameters(t *testing.T) {
+	_, err := NewBcryptHasher(bcrypt.MaxCost + 1)
+	assert.Error(t, err)
+	_, err = NewArgon2idHasher(64, 0, 1)
+	assert.Error(t, err)
+	_, err = NewArgon2idHasher(4, 1, 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretCipher_RoundTrip(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+	c, err := NewSecretCipher(key)
+	require.NoError(t, err)
+
+	a, err := c.Encrypt([]byte("totp seed"))
+	require.NoError(t, err)
+	b, err := c.Encrypt([]byte("totp seed"))
+	require.NoError(t, err)
+	assert.NotEqual(t, a, b, "every encryption uses a fresh nonce")
+
+	plain, err := c.Decrypt(a)
+	assert.NoError(t, err)
+	assert.Equal(t, "totp seed", string(plain))
+}
+
+func TestSecretCipher_RejectsTampering(t *testing.T) {
+	c, err := NewSecretCipher(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+	require.NoError(t, err)
+	other, err := NewSecretCipher(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("o", 32))))
+	require.NoError(t, err)
+
+	ct, err := c.Encrypt([]byte("totp seed"))
+	require.NoError(t, err)
+	raw, _ := base64.StdEncoding.DecodeString(ct)
+	raw[len(raw)-1] ^= 1
+
+	_, err = c.Decrypt(base64.StdEncoding.EncodeToString(raw))
+	assert.Error(t, err)
+	_, err = other.Decrypt(ct)
+	assert.Error(t, err)
+	_, err = c.Decrypt("AAAA")
+	assert.Error(t, err)
+}
+
+func TestNewSecretCipher_Errors(t *testing.T) {
+	_, err := NewSecretCipher("not base64!")
+	assert.Error(t, err)
+	_, err = NewSecretCipher(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+}
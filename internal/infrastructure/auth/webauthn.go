@@ -0,0 +1,269 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+)
+
+// Authenticator data flags (WebAuthn §6.1).
+const (
+	flagUserPresent      = 0x01
+	flagUserVerified     = 0x04
+	flagAttestedCredData = 0x40
+	flagExtensionData    = 0x80
+)
+
+type webAuthn struct {
+	rpIDHash [32]byte
+	origins  map[string]bool
+}
+
+// NewWebAuthn verifies passkey ceremonies for the relying party rpID. Client data must come
+// from one of origins, which defaults to https://<rpID>. Attestation statements are not
+// checked: registration options ask for "none", so authenticators are trusted on first use.
+func NewWebAuthn(rpID string, origins []string) port.WebAuthn {
+	w := &webAuthn{rpIDHash: sha256.Sum256([]byte(rpID)), origins: map[string]bool{}}
+	if len(origins) == 0 {
+		origins = []string{"https://" + rpID}
+	}
+	for _, o := range origins {
+		w.origins[strings.TrimRight(o, "/")] = true
+	}
+	return w
+}
+
+// ensure implementation
+var _ port.WebAuthn = (*webAuthn)(nil)
+
+func (w *webAuthn) VerifyRegistration(in dto.FinishPasskeyRegistrationInput) (string, *domain.PasskeyCredential, error) {
+	clientData, err := decodeBase64URL(in.ClientDataJSON)
+	if err != nil {
+		return "", nil, fmt.Errorf("decode client data: %w", err)
+	}
+	challenge, err := w.verifyClientData(clientData, "webauthn.create")
+	if err != nil {
+		return "", nil, err
+	}
+	rawAtt, err := decodeBase64URL(in.AttestationObject)
+	if err != nil {
+		return "", nil, fmt.Errorf("decode attestation object: %w", err)
+	}
+	v, rest, err := decodeCBOR(rawAtt)
+	if err != nil {
+		return "", nil, err
+	}
+	att, ok := v.(map[any]any)
+	if !ok || len(rest) != 0 {
+		return "", nil, errors.New("malformed attestation object")
+	}
+	rawAuthData, ok := att["authData"].([]byte)
+	if !ok {
+		return "", nil, errors.New("attestation object has no authenticator data")
+	}
+	ad, err := w.verifyAuthenticatorData(rawAuthData)
+	if err != nil {
+		return "", nil, err
+	}
+	if ad.flags&flagAttestedCredData == 0 {
+		return "", nil, errors.New("attestation carries no credential")
+	}
+	if _, _, err := parseCOSEKey(ad.publicKey); err != nil {
+		return "", nil, err
+	}
+	return challenge, &domain.PasskeyCredential{
+		CredentialID: base64.RawURLEncoding.EncodeToString(ad.credentialID),
+		PublicKey:    ad.publicKey,
+		SignCount:    ad.signCount,
+	}, nil
+}
+
+func (w *webAuthn) VerifyAssertion(cred *domain.PasskeyCredential, in dto.FinishPasskeyLoginInput) (string, uint32, error) {
+	clientData, err := decodeBase64URL(in.ClientDataJSON)
+	if err != nil {
+		return "", 0, fmt.Errorf("decode client data: %w", err)
+	}
+	rawAuthData, err := decodeBase64URL(in.AuthenticatorData)
+	if err != nil {
+		return "", 0, fmt.Errorf("decode authenticator data: %w", err)
+	}
+	sig, err := decodeBase64URL(in.Signature)
+	if err != nil {
+		return "", 0, fmt.Errorf("decode signature: %w", err)
+	}
+	challenge, err := w.verifyClientData(clientData, "webauthn.get")
+	if err != nil {
+		return "", 0, err
+	}
+	ad, err := w.verifyAuthenticatorData(rawAuthData)
+	if err != nil {
+		return "", 0, err
+	}
+	alg, key, err := parseCOSEKey(cred.PublicKey)
+	if err != nil {
+		return "", 0, err
+	}
+	clientDataHash := sha256.Sum256(clientData)
+	signed := append(append([]byte{}, rawAuthData...), clientDataHash[:]...)
+	if !verifyCOSESignature(alg, key, signed, sig) {
+		return "", 0, errors.New("invalid assertion signature")
+	}
+	return challenge, ad.signCount, nil
+}
+
+// verifyClientData checks the ceremony type and origin of CollectedClientData and returns
+// its challenge.
+func (w *webAuthn) verifyClientData(raw []byte, ceremony string) (string, error) {
+	var cd struct {
+		Type        string `json:"type"`
+		Challenge   string `json:"challenge"`
+		Origin      string `json:"origin"`
+		CrossOrigin bool   `json:"crossOrigin"`
+	}
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return "", fmt.Errorf("parse client data: %w", err)
+	}
+	if cd.Type != ceremony {
+		return "", fmt.Errorf("unexpected client data type %q", cd.Type)
+	}
+	if !w.origins[cd.Origin] {
+		return "", fmt.Errorf("origin %q is not allowed", cd.Origin)
+	}
+	if cd.CrossOrigin {
+		return "", errors.New("cross-origin ceremonies are not allowed")
+	}
+	if cd.Challenge == "" {
+		return "", errors.New("client data has no challenge")
+	}
+	return cd.Challenge, nil
+}
+
+type authenticatorData struct {
+	flags        byte
+	signCount    uint32
+	credentialID []byte
+	publicKey    []byte // COSE_Key; only present with attested credential data
+}
+
+// verifyAuthenticatorData parses authenticator data (WebAuthn §6.1) and checks that it was
+// produced for our relying party with the user present and verified.
+func (w *webAuthn) verifyAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	if len(raw) < 37 {
+		return nil, errors.New("authenticator data too short")
+	}
+	if !bytes.Equal(raw[:32], w.rpIDHash[:]) {
+		return nil, errors.New("authenticator data is for another relying party")
+	}
+	ad := &authenticatorData{flags: raw[32], signCount: binary.BigEndian.Uint32(raw[33:37])}
+	if ad.flags&flagUserPresent == 0 || ad.flags&flagUserVerified == 0 {
+		return nil, errors.New("user was not present and verified")
+	}
+	rest := raw[37:]
+	if ad.flags&flagAttestedCredData != 0 {
+		if len(rest) < 18 { // AAGUID and credential ID length
+			return nil, errors.New("attested credential data too short")
+		}
+		n := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if n == 0 || n > 1023 || len(rest) < n {
+			return nil, errors.New("invalid credential ID")
+		}
+		ad.credentialID, rest = rest[:n], rest[n:]
+		_, after, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, fmt.Errorf("parse credential public key: %w", err)
+		}
+		ad.publicKey, rest = rest[:len(rest)-len(after)], after
+	}
+	if ad.flags&flagExtensionData != 0 {
+		var err error
+		if _, rest, err = decodeCBOR(rest); err != nil {
+			return nil, fmt.Errorf("parse extensions: %w", err)
+		}
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing bytes in authenticator data")
+	}
+	return ad, nil
+}
+
+// parseCOSEKey decodes a COSE_Key (RFC 9052 §7, RFC 9053) for one of the algorithms in
+// domain.COSEAlg*.
+func parseCOSEKey(raw []byte) (int64, crypto.PublicKey, error) {
+	v, rest, err := decodeCBOR(raw)
+	if err != nil {
+		return 0, nil, err
+	}
+	m, ok := v.(map[any]any)
+	if !ok || len(rest) != 0 {
+		return 0, nil, errors.New("malformed COSE key")
+	}
+	kty, _ := m[int64(1)].(int64)
+	alg, _ := m[int64(3)].(int64)
+	switch {
+	case kty == 2 && alg == domain.COSEAlgES256:
+		crv, _ := m[int64(-1)].(int64)
+		x, _ := m[int64(-2)].([]byte)
+		y, _ := m[int64(-3)].([]byte)
+		if crv != 1 || len(x) != 32 || len(y) != 32 {
+			return 0, nil, errors.New("ES256 key must be on P-256")
+		}
+		key, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), append(append([]byte{4}, x...), y...))
+		if err != nil {
+			return 0, nil, err
+		}
+		return alg, key, nil
+	case kty == 1 && alg == domain.COSEAlgEdDSA:
+		crv, _ := m[int64(-1)].(int64)
+		x, _ := m[int64(-2)].([]byte)
+		if crv != 6 || len(x) != ed25519.PublicKeySize {
+			return 0, nil, errors.New("EdDSA key must be Ed25519")
+		}
+		return alg, ed25519.PublicKey(x), nil
+	case kty == 3 && alg == domain.COSEAlgRS256:
+		n, _ := m[int64(-1)].([]byte)
+		e, _ := m[int64(-2)].([]byte)
+		if len(e) == 0 || len(e) > 4 {
+			return 0, nil, errors.New("invalid RSA exponent")
+		}
+		key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if key.N.BitLen() < 2048 {
+			return 0, nil, errors.New("RSA key must be at least 2048 bits")
+		}
+		return alg, key, nil
+	}
+	return 0, nil, fmt.Errorf("unsupported COSE key type %d with algorithm %d", kty, alg)
+}
+
+func verifyCOSESignature(alg int64, key crypto.PublicKey, msg, sig []byte) bool {
+	digest := sha256.Sum256(msg)
+	switch alg {
+	case domain.COSEAlgES256:
+		return ecdsa.VerifyASN1(key.(*ecdsa.PublicKey), digest[:], sig)
+	case domain.COSEAlgEdDSA:
+		return ed25519.Verify(key.(ed25519.PublicKey), msg, sig)
+	case domain.COSEAlgRS256:
+		return rsa.VerifyPKCS1v15(key.(*rsa.PublicKey), crypto.SHA256, digest[:], sig) == nil
+	}
+	return false
+}
+
+// decodeBase64URL accepts base64url with or without padding, as browsers differ.
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,417 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/dto"
+)
+
+const (
+	testRPID   = "login.example.com"
+	testOrigin = "https://login.example.com"
+)
+
+// cborPair keeps map entries in the order they are encoded, as CTAP2 canonical CBOR requires.
+type cborPair struct {
+	k, v any
+}
+
+func encodeCBOR(v any) []byte {
+	head := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n <= 0xff:
+			return []byte{major<<5 | 24, byte(n)}
+		case n <= 0xffff:
+			return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(n))
+		default:
+			return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(n))
+		}
+	}
+	switch t := v.(type) {
+	case int:
+		if t < 0 {
+			return head(1, uint64(-1-t))
+		}
+		return head(0, uint64(t))
+	case int64:
+		return encodeCBOR(int(t))
+	case []byte:
+		return append(head(2, uint64(len(t))), t...)
+	case string:
+		return append(head(3, uint64(len(t))), t...)
+	case []cborPair:
+		out := head(5, uint64(len(t)))
+		for _, p := range t {
+			out = append(out, encodeCBOR(p.k)...)
+			out = append(out, encodeCBOR(p.v)...)
+		}
+		return out
+	}
+	panic("unsupported type")
+}
+
+// softAuthenticator plays the authenticator and browser roles of the WebAuthn ceremonies.
+type softAuthenticator struct {
+	alg       int64
+	signer    crypto.Signer
+	credID    []byte
+	counter   uint32
+	rpID      string
+	origin    string
+	flags     byte
+	clientTyp string // overrides the client data type when set
+}
+
+func newSoftAuthenticator(t *testing.T, alg int64) *softAuthenticator {
+	t.Helper()
+	var signer crypto.Signer
+	var err error
+	switch alg {
+	case domain.COSEAlgES256:
+		signer, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case domain.COSEAlgEdDSA:
+		_, signer, err = ed25519.GenerateKey(rand.Reader)
+	case domain.COSEAlgRS256:
+		signer, err = rsa.GenerateKey(rand.Reader, 2048)
+	}
+	require.NoError(t, err)
+	credID := make([]byte, 16)
+	_, _ = rand.Read(credID)
+	return &softAuthenticator{
+		alg:    alg,
+		signer: signer,
+		credID: credID,
+		rpID:   testRPID,
+		origin: testOrigin,
+		flags:  flagUserPresent | flagUserVerified,
+	}
+}
+
+func (a *softAuthenticator) coseKey() []byte {
+	switch pub := a.signer.Public().(type) {
+	case *ecdsa.PublicKey:
+		raw, _ := pub.Bytes()
+		return encodeCBOR([]cborPair{{1, 2}, {3, a.alg}, {-1, 1}, {-2, raw[1:33]}, {-3, raw[33:]}})
+	case ed25519.PublicKey:
+		return encodeCBOR([]cborPair{{1, 1}, {3, a.alg}, {-1, 6}, {-2, []byte(pub)}})
+	case *rsa.PublicKey:
+		return encodeCBOR([]cborPair{{1, 3}, {3, a.alg}, {-1, pub.N.Bytes()}, {-2, big.NewInt(int64(pub.E)).Bytes()}})
+	}
+	panic("unsupported key")
+}
+
+func (a *softAuthenticator) authData(flags byte, attested []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	out := append(rpIDHash[:], flags)
+	out = binary.BigEndian.AppendUint32(out, a.counter)
+	return append(out, attested...)
+}
+
+func (a *softAuthenticator) clientData(typ, challenge string) []byte {
+	if a.clientTyp != "" {
+		typ = a.clientTyp
+	}
+	b, _ := json.Marshal(map[string]any{"type": typ, "challenge": challenge, "origin": a.origin, "crossOrigin": false})
+	return b
+}
+
+func (a *softAuthenticator) create(challenge string) dto.FinishPasskeyRegistrationInput {
+	attested := make([]byte, 16) // zero AAGUID
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.credID)))
+	attested = append(attested, a.credID...)
+	attested = append(attested, a.coseKey()...)
+	att := encodeCBOR([]cborPair{
+		{"fmt", "none"},
+		{"attStmt", []cborPair{}},
+		{"authData", a.authData(a.flags|flagAttestedCredData, attested)},
+	})
+	return dto.FinishPasskeyRegistrationInput{
+		ClientDataJSON:    base64.RawURLEncoding.EncodeToString(a.clientData("webauthn.create", challenge)),
+		AttestationObject: base64.RawURLEncoding.EncodeToString(att),
+	}
+}
+
+func (a *softAuthenticator) get(t *testing.T, challenge string) dto.FinishPasskeyLoginInput {
+	t.Helper()
+	a.counter++
+	authData := a.authData(a.flags, nil)
+	clientData := a.clientData("webauthn.get", challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	msg := append(append([]byte{}, authData...), clientDataHash[:]...)
+	var sig []byte
+	var err error
+	if a.alg == domain.COSEAlgEdDSA {
+		sig, err = a.signer.Sign(rand.Reader, msg, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(msg)
+		sig, err = a.signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	require.NoError(t, err)
+	return dto.FinishPasskeyLoginInput{
+		CredentialID:      base64.RawURLEncoding.EncodeToString(a.credID),
+		ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientData),
+		AuthenticatorData: base64.RawURLEncoding.EncodeToString(authData),
+		Signature:         base64.RawURLEncoding.EncodeToString(sig),
+	}
+}
+
+func TestWebAuthn_RegisterAndLogin(t *testing.T) {
+	for name, alg := range map[string]int64{"ES256": domain.COSEAlgES256, "EdDSA": domain.COSEAlgEdDSA, "RS256": domain.COSEAlgRS256} {
+		t.Run(name, func(t *testing.T) {
+			w := NewWebAuthn(testRPID, nil)
+			a := newSoftAuthenticator(t, alg)
+
+			challenge, cred, err := w.VerifyRegistration(a.create("reg-challenge"))
+			require.NoError(t, err)
+			assert.Equal(t, "reg-challenge", challenge)
+			assert.Equal(t, base64.RawURLEncoding.EncodeToString(a.credID), cred.CredentialID)
+			assert.Equal(t, uint32(0), cred.SignCount)
+
+			challenge, counter, err := w.VerifyAssertion(cred, a.get(t, "login-challenge"))
+			require.NoError(t, err)
+			assert.Equal(t, "login-challenge", challenge)
+			assert.Equal(t, uint32(1), counter)
+		})
+	}
+}
+
+func TestWebAuthn_VerifyRegistration_Rejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(a *softAuthenticator)
+	}{
+		{"wrong origin", func(a *softAuthenticator) { a.origin = "https://evil.example.com" }},
+		{"wrong relying party", func(a *softAuthenticator) { a.rpID = "evil.example.com" }},
+		{"assertion client data", func(a *softAuthenticator) { a.clientTyp = "webauthn.get" }},
+		{"user not verified", func(a *softAuthenticator) { a.flags = flagUserPresent }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWebAuthn(testRPID, []string{testOrigin})
+			a := newSoftAuthenticator(t, domain.COSEAlgES256)
+			tt.mutate(a)
+
+			_, _, err := w.VerifyRegistration(a.create("reg-challenge"))
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("malformed attestation object", func(t *testing.T) {
+		w := NewWebAuthn(testRPID, nil)
+		in := newSoftAuthenticator(t, domain.COSEAlgES256).create("reg-challenge")
+		in.AttestationObject = base64.RawURLEncoding.EncodeToString([]byte{0xa1, 0x01})
+
+		_, _, err := w.VerifyRegistration(in)
+		assert.Error(t, err)
+	})
+}
+
+func TestWebAuthn_VerifyAssertion_Rejects(t *testing.T) {
+	w := NewWebAuthn(testRPID, nil)
+	a := newSoftAuthenticator(t, domain.COSEAlgES256)
+	_, cred, err := w.VerifyRegistration(a.create("reg-challenge"))
+	require.NoError(t, err)
+
+	t.Run("signature from another key", func(t *testing.T) {
+		other := newSoftAuthenticator(t, domain.COSEAlgES256)
+		_, _, err := w.VerifyAssertion(cred, other.get(t, "login-challenge"))
+		assert.Error(t, err)
+	})
+
+	t.Run("tampered client data", func(t *testing.T) {
+		in := a.get(t, "login-challenge")
+		in.ClientDataJSON = base64.RawURLEncoding.EncodeToString(a.clientData("webauthn.get", "other-challenge"))
+		_, _, err := w.VerifyAssertion(cred, in)
+		assert.Error(t, err)
+	})
+
+	t.Run("registration client data", func(t *testing.T) {
+		a.clientTyp = "webauthn.create"
+		defer func() { a.clientTyp = "" }()
+		_, _, err := w.VerifyAssertion(cred, a.get(t, "login-challenge"))
+		assert.Error(t, err)
+	})
+
+	t.Run("user not present", func(t *testing.T) {
+		a.flags = flagUserVerified
+		defer func() { a.flags = flagUserPresent | flagUserVerified }()
+		_, _, err := w.VerifyAssertion(cred, a.get(t, "login-challenge"))
+		assert.Error(t, err)
+	})
+}
+
+// webAuthnVectors were produced outside this package with the openssl command line tool: keys
+// from genpkey, signatures from dgst -sha256 -sign (ES256, RS256) and pkeyutl -sign -rawin
+// (EdDSA) over authenticator data for login.example.com with the UP and UV flags and counter 42.
+var webAuthnVectors = []struct {
+	name    string
+	alg     int64
+	coseKey string // hex
+	credID  string
+	create  dto.FinishPasskeyRegistrationInput
+	get     dto.FinishPasskeyLoginInput
+}{
+	{
+		name:    "ES256",
+		alg:     domain.COSEAlgES256,
+		coseKey: "a50102032620012158202a551b39f49e9d57d7049215482f9c5ab43e82e5fe8b735630645e7552353c3f22582016a19357a5d90c42ce8f8960eaf85e43a0f4c169d9632fcc9b731185d51c41c8",
+		credID:  "GUDDt1dwVI7R37FdhYMyJg",
+		create: dto.FinishPasskeyRegistrationInput{
+			ClientDataJSON:    "eyJ0eXBlIjoid2ViYXV0aG4uY3JlYXRlIiwiY2hhbGxlbmdlIjoia2F0LWVzMjU2Iiwib3JpZ2luIjoiaHR0cHM6Ly9sb2dpbi5leGFtcGxlLmNvbSIsImNyb3NzT3JpZ2luIjpmYWxzZX0",
+			AttestationObject: "o2NmbXRkbm9uZWdhdHRTdG10oGhhdXRoRGF0YViUDGygg5w6VoNVeDP2GKJVZmXfKgiJZHh9U4ULStTTvtxFAAAAAAAAAAAAAAAAAAAAAAAAAAAAEBlAw7dXcFSO0d-xXYWDMialAQIDJiABIVggKlUbOfSenVfXBJIVSC-cWrQ-guX-i3NWMGRedVI1PD8iWCAWoZNXpdkMQs6PiWDq-F5DoPTBadljL8ybcxGF1RxByA",
+		},
+		get: dto.FinishPasskeyLoginInput{
+			CredentialID:      "GUDDt1dwVI7R37FdhYMyJg",
+			ClientDataJSON:    "eyJ0eXBlIjoid2ViYXV0aG4uZ2V0IiwiY2hhbGxlbmdlIjoia2F0LWVzMjU2Iiwib3JpZ2luIjoiaHR0cHM6Ly9sb2dpbi5leGFtcGxlLmNvbSIsImNyb3NzT3JpZ2luIjpmYWxzZX0",
+			AuthenticatorData: "DGygg5w6VoNVeDP2GKJVZmXfKgiJZHh9U4ULStTTvtwFAAAAKg",
+			Signature:         "MEUCIDYpEXra5GC044U36SpGaHSwzJKkFmrLY4UQpvLp0ginAiEA0vFY7_FuXt_zC7P_JfW3mEvORi-hdUSB-BpLs4IeXP4",
+		},
+	},
+	{
+		name:    "EdDSA",
+		alg:     domain.COSEAlgEdDSA,
+		coseKey: "a4010103272006215820cce5efa5476553feb883eb2fec9027e04c58f17621cd24a3ef153f9a76a26f74",
+		credID:  "PC-c9xWD0x1CPA1rvTSU9Q",
+		create: dto.FinishPasskeyRegistrationInput{
+			ClientDataJSON:    "eyJ0eXBlIjoid2ViYXV0aG4uY3JlYXRlIiwiY2hhbGxlbmdlIjoia2F0LWVkZHNhIiwib3JpZ2luIjoiaHR0cHM6Ly9sb2dpbi5leGFtcGxlLmNvbSIsImNyb3NzT3JpZ2luIjpmYWxzZX0",
+			AttestationObject: "o2NmbXRkbm9uZWdhdHRTdG10oGhhdXRoRGF0YVhxDGygg5w6VoNVeDP2GKJVZmXfKgiJZHh9U4ULStTTvtxFAAAAAAAAAAAAAAAAAAAAAAAAAAAAEDwvnPcVg9MdQjwNa700lPWkAQEDJyAGIVggzOXvpUdlU_64g-sv7JAn4ExY8XYhzSSj7xU_mnaib3Q",
+		},
+		get: dto.FinishPasskeyLoginInput{
+			CredentialID:      "PC-c9xWD0x1CPA1rvTSU9Q",
+			ClientDataJSON:    "eyJ0eXBlIjoid2ViYXV0aG4uZ2V0IiwiY2hhbGxlbmdlIjoia2F0LWVkZHNhIiwib3JpZ2luIjoiaHR0cHM6Ly9sb2dpbi5leGFtcGxlLmNvbSIsImNyb3NzT3JpZ2luIjpmYWxzZX0",
+			AuthenticatorData: "DGygg5w6VoNVeDP2GKJVZmXfKgiJZHh9U4ULStTTvtwFAAAAKg",
+			Signature:         "SxGlSBl_Rap4tTqgXc1zsgB_ltrl43FQ6r6ZHMok3KaPhSTHDpg_7xltwwq26sq_vDoCBREm-M25i1_QYVbOCg",
+		},
+	},
+	{
+		name:    "RS256",
+		alg:     domain.COSEAlgRS256,
+		coseKey: "a401030339010020590100bf0bdda72febe6f5c9ddf1c280b5870c9c121d321709df0b7e32ea7b85041f68f867f0c8c6af9fc16b1e7eb98bae2cc675f2cac25e2716095d48fd393685b0c6a5f81808f7dcbbcb0ee93134f73b9e98dfeec9247aa4fe1a873f9762ea4ca1c4885959c1565654e9f5decab653db2719d426e7b75f1c76fc0af46a5d52769fde4357b751749a1134bf9442a9f2e3b53d51852ff7e332fca452110097a3f1a020e76e0485f77e9028940ebfa881be60885856d17272722e914798a1336f01adda207a31878fd3802105973aa45e58a9239f0997c7ddd70c3b247dec01db315405d1c96c5d2ba6c641118967b4278c50f6f62e1ea6fc6c2cd40e36e7a6bd787e4d2143010001",
+		credID:  "mRCcNV8hQeoi1kP5GmbbJg",
+		create: dto.FinishPasskeyRegistrationInput{
+			ClientDataJSON:    "eyJ0eXBlIjoid2ViYXV0aG4uY3JlYXRlIiwiY2hhbGxlbmdlIjoia2F0LXJzMjU2Iiwib3JpZ2luIjoiaHR0cHM6Ly9sb2dpbi5leGFtcGxlLmNvbSIsImNyb3NzT3JpZ2luIjpmYWxzZX0",
+			AttestationObject: "o2NmbXRkbm9uZWdhdHRTdG10oGhhdXRoRGF0YVkBVwxsoIOcOlaDVXgz9hiiVWZl3yoIiWR4fVOFC0rU077cRQAAAAAAAAAAAAAAAAAAAAAAAAAAABCZEJw1XyFB6iLWQ_kaZtsmpAEDAzkBACBZAQC_C92nL-vm9cnd8cKAtYcMnBIdMhcJ3wt-Mup7hQQfaPhn8MjGr5_Bax5-uYuuLMZ18srCXicWCV1I_Tk2hbDGpfgYCPfcu8sO6TE09zuemN_uySR6pP4ahz-XYupMocSIWVnBVlZU6fXeyrZT2ycZ1Cbnt18cdvwK9GpdUnaf3kNXt1F0mhE0v5RCqfLjtT1RhS_34zL8pFIRAJej8aAg524Ehfd-kCiUDr-ogb5giFhW0XJyci6RR5ihM28BrdogejGHj9OAIQWXOqReWKkjnwmXx93XDDskfewB2zFUBdHJbF0rpsZBEYlntCeMUPb2Lh6m_Gws1A4256a9eH5NIUMBAAE",
+		},
+		get: dto.FinishPasskeyLoginInput{
+			CredentialID:      "mRCcNV8hQeoi1kP5GmbbJg",
+			ClientDataJSON:    "eyJ0eXBlIjoid2ViYXV0aG4uZ2V0IiwiY2hhbGxlbmdlIjoia2F0LXJzMjU2Iiwib3JpZ2luIjoiaHR0cHM6Ly9sb2dpbi5leGFtcGxlLmNvbSIsImNyb3NzT3JpZ2luIjpmYWxzZX0",
+			AuthenticatorData: "DGygg5w6VoNVeDP2GKJVZmXfKgiJZHh9U4ULStTTvtwFAAAAKg",
+			Signature:         "hiONCzqFmeDwObDKmHEBXqrdu6UvkEZqBHDhbLO4FKtQ5TKOzuYi0U7C_9MVoFDSZqbKdu_-eYZWBbhgiBI-CPFHlakKyHFpCUzsWlRTKxGhagSB8qGQYW_17Pcc9NRAxIV6O7Rqkj_uCQEqS8PGfvhoSXPDPKuazOsZz_AGAC4P11z7FeaEJw8OtUSMelQwMKyIhS7ylIctMR9KxnvAfflOy2HATTHJM7ZRQafLlIKMH1TK1e3i8VmoHYTIXUvphA6TFoSn4PridqdsHySxI1aaqXkwFMLmOqzgWjcOG8OJuBKgJXodXe_S5ADwCOI0yD83cIa4e7Onz0bCgZRR3g",
+		},
+	},
+}
+
+func TestWebAuthn_KnownAnswers(t *testing.T) {
+	for _, v := range webAuthnVectors {
+		t.Run(v.name, func(t *testing.T) {
+			w := NewWebAuthn(testRPID, nil)
+			coseKey, _ := hex.DecodeString(v.coseKey)
+
+			alg, _, err := parseCOSEKey(coseKey)
+			require.NoError(t, err)
+			assert.Equal(t, v.alg, alg)
+
+			challenge, cred, err := w.VerifyRegistration(v.create)
+			require.NoError(t, err)
+			assert.Equal(t, "kat-"+strings.ToLower(v.name), challenge)
+			assert.Equal(t, v.credID, cred.CredentialID)
+			assert.Equal(t, coseKey, cred.PublicKey)
+			assert.Equal(t, uint32(0), cred.SignCount)
+
+			challenge, counter, err := w.VerifyAssertion(cred, v.get)
+			require.NoError(t, err)
+			assert.Equal(t, "kat-"+strings.ToLower(v.name), challenge)
+			assert.Equal(t, uint32(42), counter)
+
+			sig, _ := base64.RawURLEncoding.DecodeString(v.get.Signature)
+			sig[len(sig)-1] ^= 0x01
+			tampered := v.get
+			tampered.Signature = base64.RawURLEncoding.EncodeToString(sig)
+			_, _, err = w.VerifyAssertion(cred, tampered)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// FuzzParseCOSEKey checks that arbitrary COSE keys never panic, and that an accepted key
+// never panics signature verification either.
+func FuzzParseCOSEKey(f *testing.F) {
+	for _, v := range webAuthnVectors {
+		raw, _ := hex.DecodeString(v.coseKey)
+		f.Add(raw)
+	}
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		alg, key, err := parseCOSEKey(raw)
+		if err != nil {
+			return
+		}
+		verifyCOSESignature(alg, key, []byte("message"), []byte("signature"))
+	})
+}
+
+// FuzzVerifyRegistration feeds arbitrary attestation objects through registration.
+func FuzzVerifyRegistration(f *testing.F) {
+	for _, v := range webAuthnVectors {
+		raw, _ := base64.RawURLEncoding.DecodeString(v.create.AttestationObject)
+		f.Add(raw)
+	}
+	clientData := webAuthnVectors[0].create.ClientDataJSON
+	w := NewWebAuthn(testRPID, nil)
+	f.Fuzz(func(t *testing.T, att []byte) {
+		_, cred, err := w.VerifyRegistration(dto.FinishPasskeyRegistrationInput{
+			ClientDataJSON:    clientData,
+			AttestationObject: base64.RawURLEncoding.EncodeToString(att),
+		})
+		if err != nil {
+			return
+		}
+		if _, _, err := parseCOSEKey(cred.PublicKey); err != nil {
+			t.Fatalf("registration accepted an unusable public key: %v", err)
+		}
+	})
+}
+
+// FuzzVerifyAssertion feeds arbitrary authenticator data and signatures through sign-in
+// against the known-answer credentials.
+func FuzzVerifyAssertion(f *testing.F) {
+	for _, v := range webAuthnVectors {
+		authData, _ := base64.RawURLEncoding.DecodeString(v.get.AuthenticatorData)
+		sig, _ := base64.RawURLEncoding.DecodeString(v.get.Signature)
+		f.Add(authData, sig)
+	}
+	w := NewWebAuthn(testRPID, nil)
+	creds := make([]*domain.PasskeyCredential, len(webAuthnVectors))
+	for i, v := range webAuthnVectors {
+		raw, _ := hex.DecodeString(v.coseKey)
+		creds[i] = &domain.PasskeyCredential{CredentialID: v.credID, PublicKey: raw}
+	}
+	f.Fuzz(func(t *testing.T, authData, sig []byte) {
+		for i, v := range webAuthnVectors {
+			_, _, _ = w.VerifyAssertion(creds[i], dto.FinishPasskeyLoginInput{
+				CredentialID:      v.credID,
+				ClientDataJSON:    v.get.ClientDataJSON,
+				AuthenticatorData: base64.RawURLEncoding.EncodeToString(authData),
+				Signature:         base64.RawURLEncoding.EncodeToString(sig),
+			})
+		}
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -15,13 +17,120 @@ type Config struct {
 	Environment string
 
 	// DynamoDB
-	AWSRegion      string
-	UsersTableName string
-	IdsTableName   string
+	AWSRegion                     string
+	UsersTableName                string
+	IdsTableName                  string
+	RefreshTokensTableName        string
+	RevokedTokensTableName        string
+	OAuthClientsTableName         string
+	OneTimeTokensTableName        string
+	PasskeyCredentialsTableName   string
+	RateLimitsTableName           string
+	SessionsTableName             string
+	EmailsTableName               string
+	AuthorizationCodesTableName   string
+	FederatedIdentitiesTableName  string
+	PersonalAccessTokensTableName string
 
 	// JWT
-	JWTSecret     string
+	JWTAlgorithm  string // HS256, RS256 or ES256
+	JWTSecret     string // HS256 only
+	JWTPrivateKey string // PEM, RS256/ES256 only
 	JWTExpiration time.Duration
+	JWTIssuer     string   // iss claim; validated when set
+	JWTAudience   []string // aud claim; a token must carry at least one of them when set
+
+	// JWT key ring (JSON); takes precedence over the single key settings above
+	JWTKeyRing              string
+	JWTKeyRingParameterName string
+	JWTKeyRingRefresh       time.Duration
+
+	// Refresh tokens
+	RefreshTokenExpiration time.Duration
+
+	// Delivery of notifications: ses, or log, which is for development only and never
+	// prints one-time tokens. Empty disables the flows that email one-time tokens.
+	Notifier  string
+	EmailFrom string // sender address, a verified SES identity
+
+	// Password reset
+	PasswordResetExpiration time.Duration
+
+	// Magic-link login; 0 or an empty URL disables it
+	MagicLinkExpiration time.Duration
+	MagicLinkURL        string // frontend page the emailed links point at
+
+	// Email verification
+	EmailVerificationExpiration time.Duration
+	RequireEmailVerification    bool // Login refuses accounts whose email is not verified
+	EmailChangeExpiration       time.Duration
+	EnumerationSafeRegistration bool // Register answers taken emails like new ones and notifies the owner
+
+	// Password hashing; hashes of the other algorithm or other parameters are upgraded on login
+	PasswordHashAlgorithm string // argon2id or bcrypt
+	BcryptCost            int
+	Argon2Memory          int // KiB
+	Argon2Iterations      int
+	Argon2Parallelism     int
+
+	// Password policy applied on registration and password reset
+	PasswordMinLength      int
+	PasswordMaxLength      int // bytes; bcrypt ignores anything past 72
+	PasswordRequireLower   bool
+	PasswordRequireUpper   bool
+	PasswordRequireDigit   bool
+	PasswordRequireSymbol  bool
+	PasswordRejectPersonal bool // refuse passwords containing the user's email or name
+	PasswordRejectCommon   bool // refuse passwords from the built-in common password list
+
+	// Rate limiting of the login, register and forgot password endpoints
+	RateLimitStore         string // dynamodb, memory or off
+	IPRateLimitBurst       int
+	IPRateLimitInterval    time.Duration // one request is refilled per interval
+	EmailRateLimitBurst    int
+	EmailRateLimitInterval time.Duration
+
+	// Account lockout after repeated failed logins; disabled when LockoutThreshold is 0
+	LockoutThreshold   int
+	LockoutDuration    time.Duration // first lock; doubled for every further failure
+	LockoutMaxDuration time.Duration
+
+	// TOTP multi-factor authentication; disabled when MFAEncryptionKey is empty
+	MFAEncryptionKey       string // base64, 32 bytes (AES-256)
+	MFAIssuer              string // label shown in authenticator apps
+	MFAChallengeExpiration time.Duration
+
+	// Passkeys (WebAuthn); disabled when WebAuthnRPID is empty
+	WebAuthnRPID                string   // relying party ID, the site's registrable domain
+	WebAuthnRPName              string   // shown by authenticators
+	WebAuthnOrigins             []string // allowed client origins; https://<rp id> when empty
+	WebAuthnChallengeExpiration time.Duration
+
+	// Lifetime of access tokens issued through the client credentials grant
+	ClientTokenExpiration time.Duration
+
+	// OpenID Connect provider; the issuer is JWTIssuer, which must be the API's base URL
+	OIDCEnabled                 bool
+	OIDCLoginURL                string // frontend page that signs the user in and asks for consent
+	AuthorizationCodeExpiration time.Duration
+
+	// External OpenID Connect identity providers users can sign in with (JSON, see
+	// auth.NewIdentityProviders); federated login is disabled when empty
+	IdentityProviders        string
+	FederatedLoginExpiration time.Duration
+
+	// Personal access tokens; when the maximum lifetime is set, every token must expire
+	// within it
+	PersonalAccessTokensEnabled    bool
+	PersonalAccessTokenMaxLifetime time.Duration
+
+	// Admin impersonation of users
+	ImpersonationEnabled         bool
+	ImpersonationTokenExpiration time.Duration
+
+	// Service credential allowed to call the token introspection endpoint
+	IntrospectionClientID     string
+	IntrospectionClientSecret string
 }
 
 func Load(ctx context.Context) *Config {
@@ -32,19 +141,44 @@ func Load(ctx context.Context) *Config {
 
 	// Try to get JWT settings from Parameter Store first, fallback to env vars
 	jwtSecretParam := getEnv("JWT_SECRET_PARAMETER_NAME", "")
+	jwtPrivateKeyParam := getEnv("JWT_PRIVATE_KEY_PARAMETER_NAME", "")
+	jwtKeyRingParam := getEnv("JWT_KEYRING_PARAMETER_NAME", "")
 	jwtExpirationParam := getEnv("JWT_EXPIRATION_PARAMETER_NAME", "")
+	jwtAlg := strings.ToUpper(getEnv("JWT_ALGORITHM", "HS256"))
 
-	var jwtSecret, jwtExpStr string
+	var jwtSecret, jwtPrivateKey, jwtKeyRing, jwtExpStr string
 
-	if jwtSecretParam != "" {
-		jwtSecret = paramstore.GetParameterWithFallback(ctx, jwtSecretParam, "")
+	if jwtKeyRingParam != "" {
+		jwtKeyRing = paramstore.GetParameterWithFallback(ctx, jwtKeyRingParam, "")
 	}
-	if jwtSecret == "" {
-		// Fallback to environment variable
-		var ok bool
-		jwtSecret, ok = os.LookupEnv("JWT_SECRET")
-		if !ok || jwtSecret == "" {
-			log.Fatal("JWT_SECRET must be provided via Parameter Store or environment variable")
+	if jwtKeyRing == "" {
+		jwtKeyRing = getEnv("JWT_KEYRING", "")
+	}
+
+	switch {
+	case jwtKeyRing != "":
+		// keys come from the ring; single key settings are ignored
+	case jwtAlg == "HS256":
+		if jwtSecretParam != "" {
+			jwtSecret = paramstore.GetParameterWithFallback(ctx, jwtSecretParam, "")
+		}
+		if jwtSecret == "" {
+			// Fallback to environment variable
+			var ok bool
+			jwtSecret, ok = os.LookupEnv("JWT_SECRET")
+			if !ok || jwtSecret == "" {
+				log.Fatal("JWT_SECRET must be provided via Parameter Store or environment variable")
+			}
+		}
+	default:
+		if jwtPrivateKeyParam != "" {
+			jwtPrivateKey = paramstore.GetParameterWithFallback(ctx, jwtPrivateKeyParam, "")
+		}
+		if jwtPrivateKey == "" {
+			jwtPrivateKey = getEnv("JWT_PRIVATE_KEY", "")
+			if jwtPrivateKey == "" {
+				log.Fatalf("JWT_PRIVATE_KEY must be provided via Parameter Store or environment variable when JWT_ALGORITHM is %s", jwtAlg)
+			}
 		}
 	}
 
@@ -60,13 +194,90 @@ func Load(ctx context.Context) *Config {
 		exp = 24 * time.Hour
 	}
 
+	introspectionSecret := paramstore.GetParameterWithFallback(ctx,
+		getEnv("INTROSPECTION_CLIENT_SECRET_PARAMETER_NAME", ""), getEnv("INTROSPECTION_CLIENT_SECRET", ""))
+
+	identityProviders := paramstore.GetParameterWithFallback(ctx,
+		getEnv("IDENTITY_PROVIDERS_PARAMETER_NAME", ""), getEnv("IDENTITY_PROVIDERS", ""))
+
+	mfaKey := paramstore.GetParameterWithFallback(ctx,
+		getEnv("MFA_ENCRYPTION_KEY_PARAMETER_NAME", ""), getEnv("MFA_ENCRYPTION_KEY", ""))
+
 	return &Config{
-		Environment:    getEnv("ENVIRONMENT", "development"),
-		AWSRegion:      getEnv("AWS_REGION", "us-east-1"),
-		UsersTableName: getEnv("USERS_TABLE_NAME", "hackathon_users"),
-		IdsTableName:   getEnv("IDS_TABLE_NAME", "hackathon_ids"),
-		JWTSecret:      jwtSecret,
-		JWTExpiration:  exp,
+		Environment:                    getEnv("ENVIRONMENT", "development"),
+		AWSRegion:                      getEnv("AWS_REGION", "us-east-1"),
+		UsersTableName:                 getEnv("USERS_TABLE_NAME", "hackathon_users"),
+		IdsTableName:                   getEnv("IDS_TABLE_NAME", "hackathon_ids"),
+		RefreshTokensTableName:         getEnv("REFRESH_TOKENS_TABLE_NAME", "hackathon_refresh_tokens"),
+		RevokedTokensTableName:         getEnv("REVOKED_TOKENS_TABLE_NAME", "hackathon_revoked_tokens"),
+		OAuthClientsTableName:          getEnv("OAUTH_CLIENTS_TABLE_NAME", "hackathon_oauth_clients"),
+		OneTimeTokensTableName:         getEnv("ONE_TIME_TOKENS_TABLE_NAME", "hackathon_one_time_tokens"),
+		PasskeyCredentialsTableName:    getEnv("PASSKEY_CREDENTIALS_TABLE_NAME", "hackathon_passkey_credentials"),
+		RateLimitsTableName:            getEnv("RATE_LIMITS_TABLE_NAME", "hackathon_rate_limits"),
+		SessionsTableName:              getEnv("SESSIONS_TABLE_NAME", "hackathon_sessions"),
+		EmailsTableName:                getEnv("EMAILS_TABLE_NAME", "hackathon_emails"),
+		AuthorizationCodesTableName:    getEnv("AUTHORIZATION_CODES_TABLE_NAME", "hackathon_authorization_codes"),
+		FederatedIdentitiesTableName:   getEnv("FEDERATED_IDENTITIES_TABLE_NAME", "hackathon_federated_identities"),
+		PersonalAccessTokensTableName:  getEnv("PERSONAL_ACCESS_TOKENS_TABLE_NAME", "hackathon_personal_access_tokens"),
+		JWTAlgorithm:                   jwtAlg,
+		JWTSecret:                      jwtSecret,
+		JWTPrivateKey:                  jwtPrivateKey,
+		JWTIssuer:                      getEnv("JWT_ISSUER", ""),
+		JWTAudience:                    getListEnv("JWT_AUDIENCE"),
+		JWTKeyRing:                     jwtKeyRing,
+		JWTKeyRingParameterName:        jwtKeyRingParam,
+		JWTKeyRingRefresh:              getDurationEnv("JWT_KEYRING_REFRESH", 5*time.Minute),
+		JWTExpiration:                  exp,
+		RefreshTokenExpiration:         getDurationEnv("REFRESH_TOKEN_EXPIRATION", 30*24*time.Hour),
+		PasswordResetExpiration:        getDurationEnv("PASSWORD_RESET_EXPIRATION", time.Hour),
+		MagicLinkExpiration:            getDurationEnv("MAGIC_LINK_EXPIRATION", 15*time.Minute),
+		MagicLinkURL:                   getEnv("MAGIC_LINK_URL", ""),
+		EmailVerificationExpiration:    getDurationEnv("EMAIL_VERIFICATION_EXPIRATION", 24*time.Hour),
+		RequireEmailVerification:       getBoolEnv("REQUIRE_EMAIL_VERIFICATION", false),
+		EmailChangeExpiration:          getDurationEnv("EMAIL_CHANGE_EXPIRATION", 24*time.Hour),
+		EnumerationSafeRegistration:    getBoolEnv("ENUMERATION_SAFE_REGISTRATION", false),
+		PasswordHashAlgorithm:          strings.ToLower(getEnv("PASSWORD_HASH_ALGORITHM", "argon2id")),
+		BcryptCost:                     getIntEnv("BCRYPT_COST", 12),
+		Argon2Memory:                   getIntEnv("ARGON2_MEMORY", 19*1024),
+		Argon2Iterations:               getIntEnv("ARGON2_ITERATIONS", 2),
+		Argon2Parallelism:              getIntEnv("ARGON2_PARALLELISM", 1),
+		PasswordMinLength:              getIntEnv("PASSWORD_MIN_LENGTH", 8),
+		PasswordMaxLength:              getIntEnv("PASSWORD_MAX_LENGTH", 72),
+		PasswordRequireLower:           getBoolEnv("PASSWORD_REQUIRE_LOWER", false),
+		PasswordRequireUpper:           getBoolEnv("PASSWORD_REQUIRE_UPPER", false),
+		PasswordRequireDigit:           getBoolEnv("PASSWORD_REQUIRE_DIGIT", false),
+		PasswordRequireSymbol:          getBoolEnv("PASSWORD_REQUIRE_SYMBOL", false),
+		PasswordRejectPersonal:         getBoolEnv("PASSWORD_REJECT_PERSONAL", true),
+		PasswordRejectCommon:           getBoolEnv("PASSWORD_REJECT_COMMON", true),
+		RateLimitStore:                 strings.ToLower(getEnv("RATE_LIMIT_STORE", "dynamodb")),
+		IPRateLimitBurst:               getIntEnv("IP_RATE_LIMIT_BURST", 20),
+		IPRateLimitInterval:            getDurationEnv("IP_RATE_LIMIT_INTERVAL", 6*time.Second),
+		EmailRateLimitBurst:            getIntEnv("EMAIL_RATE_LIMIT_BURST", 5),
+		EmailRateLimitInterval:         getDurationEnv("EMAIL_RATE_LIMIT_INTERVAL", time.Minute),
+		LockoutThreshold:               getIntEnv("LOCKOUT_THRESHOLD", 5),
+		LockoutDuration:                getDurationEnv("LOCKOUT_DURATION", time.Minute),
+		LockoutMaxDuration:             getDurationEnv("LOCKOUT_MAX_DURATION", time.Hour),
+		MFAEncryptionKey:               mfaKey,
+		MFAIssuer:                      getEnv("MFA_ISSUER", "hackathon-user-service"),
+		MFAChallengeExpiration:         getDurationEnv("MFA_CHALLENGE_EXPIRATION", 5*time.Minute),
+		WebAuthnRPID:                   getEnv("WEBAUTHN_RP_ID", ""),
+		WebAuthnRPName:                 getEnv("WEBAUTHN_RP_NAME", "Hackathon"),
+		WebAuthnOrigins:                getListEnv("WEBAUTHN_ORIGINS"),
+		WebAuthnChallengeExpiration:    getDurationEnv("WEBAUTHN_CHALLENGE_EXPIRATION", 5*time.Minute),
+		ClientTokenExpiration:          getDurationEnv("CLIENT_TOKEN_EXPIRATION", time.Hour),
+		OIDCEnabled:                    getBoolEnv("OIDC_ENABLED", false),
+		OIDCLoginURL:                   getEnv("OIDC_LOGIN_URL", ""),
+		AuthorizationCodeExpiration:    getDurationEnv("AUTHORIZATION_CODE_EXPIRATION", time.Minute),
+		IdentityProviders:              identityProviders,
+		FederatedLoginExpiration:       getDurationEnv("FEDERATED_LOGIN_EXPIRATION", 10*time.Minute),
+		PersonalAccessTokensEnabled:    getBoolEnv("PERSONAL_ACCESS_TOKENS_ENABLED", false),
+		PersonalAccessTokenMaxLifetime: getDurationEnv("PERSONAL_ACCESS_TOKEN_MAX_LIFETIME", 0),
+		ImpersonationEnabled:           getBoolEnv("IMPERSONATION_ENABLED", false),
+		ImpersonationTokenExpiration:   getDurationEnv("IMPERSONATION_TOKEN_EXPIRATION", 15*time.Minute),
+		Notifier:                       strings.ToLower(getEnv("NOTIFIER", "")),
+		EmailFrom:                      getEnv("EMAIL_FROM", ""),
+		IntrospectionClientID:          getEnv("INTROSPECTION_CLIENT_ID", ""),
+		IntrospectionClientSecret:      introspectionSecret,
 	}
 }
 
@@ -76,3 +287,53 @@ func getEnv(key, def string) string {
 	}
 	return def
 }
+
+// getListEnv splits a comma-separated variable, dropping empty entries.
+func getListEnv(key string) []string {
+	var out []string
+	for _, v := range strings.Split(getEnv(key, ""), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func getBoolEnv(key string, def bool) bool {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Printf("Warning: invalid %s %q, defaulting to %t", key, v, def)
+		return def
+	}
+	return b
+}
+
+func getIntEnv(key string, def int) int {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("Warning: invalid %s %q, defaulting to %d", key, v, def)
+		return def
+	}
+	return n
+}
+
+func getDurationEnv(key string, def time.Duration) time.Duration {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("Warning: invalid %s %q, defaulting to %s", key, v, def)
+		return def
+	}
+	return d
+}
@@ -0,0 +1,109 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+)
+
+type dynamoAuthorizationCodeRepo struct {
+	cli   *dynamodb.Client
+	table string
+}
+
+// authorizationCodeItem is keyed by codeHash; expiresAt doubles as the table's TTL attribute.
+type authorizationCodeItem struct {
+	CodeHash      string   `dynamodbav:"codeHash"`
+	ClientID      string   `dynamodbav:"clientId"`
+	UserID        int64    `dynamodbav:"userId"`
+	RedirectURI   string   `dynamodbav:"redirectUri"`
+	Scopes        []string `dynamodbav:"scopes,omitempty"`
+	Nonce         string   `dynamodbav:"nonce,omitempty"`
+	CodeChallenge string   `dynamodbav:"codeChallenge"`
+	AuthTime      int64    `dynamodbav:"authTime"`
+	CreatedAt     int64    `dynamodbav:"createdAt"`
+	ExpiresAt     int64    `dynamodbav:"expiresAt"`
+	UsedAt        int64    `dynamodbav:"usedAt,omitempty"`
+}
+
+func NewDynamoAuthorizationCodeRepository(ctx context.Context, cfg *config.Config) (port.AuthorizationCodeRepository, error) {
+	awsCfg, err := awscfg.LoadDefaultConfig(ctx, awscfg.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		return nil, err
+	}
+	return &dynamoAuthorizationCodeRepo{cli: dynamodb.NewFromConfig(awsCfg), table: cfg.AuthorizationCodesTableName}, nil
+}
+
+func (r *dynamoAuthorizationCodeRepo) Create(ctx context.Context, c *domain.AuthorizationCode) error {
+	av, err := attributevalue.MarshalMap(authorizationCodeItem{
+		CodeHash:      c.CodeHash,
+		ClientID:      c.ClientID,
+		UserID:        c.UserID,
+		RedirectURI:   c.RedirectURI,
+		Scopes:        c.Scopes,
+		Nonce:         c.Nonce,
+		CodeChallenge: c.CodeChallenge,
+		AuthTime:      c.AuthTime,
+		CreatedAt:     c.CreatedAt,
+		ExpiresAt:     c.ExpiresAt,
+		UsedAt:        c.UsedAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.cli.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.table),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(codeHash)"),
+	})
+	return err
+}
+
+// Consume works like the one-time token repository's: a conditional update lets only one
+// of several concurrent redemptions of the same code succeed.
+func (r *dynamoAuthorizationCodeRepo) Consume(ctx context.Context, codeHash string, now int64) (*domain.AuthorizationCode, error) {
+	res, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.table),
+		Key:                 map[string]types.AttributeValue{"codeHash": &types.AttributeValueMemberS{Value: codeHash}},
+		UpdateExpression:    aws.String("SET usedAt = :now"),
+		ConditionExpression: aws.String("attribute_exists(codeHash) AND attribute_not_exists(usedAt) AND expiresAt > :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(now, 10)},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	if err != nil {
+		var cce *types.ConditionalCheckFailedException
+		if errors.As(err, &cce) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var it authorizationCodeItem
+	if err := attributevalue.UnmarshalMap(res.Attributes, &it); err != nil {
+		return nil, err
+	}
+	return &domain.AuthorizationCode{
+		CodeHash:      it.CodeHash,
+		ClientID:      it.ClientID,
+		UserID:        it.UserID,
+		RedirectURI:   it.RedirectURI,
+		Scopes:        it.Scopes,
+		Nonce:         it.Nonce,
+		CodeChallenge: it.CodeChallenge,
+		AuthTime:      it.AuthTime,
+		CreatedAt:     it.CreatedAt,
+		ExpiresAt:     it.ExpiresAt,
+		UsedAt:        it.UsedAt,
+	}, nil
+}
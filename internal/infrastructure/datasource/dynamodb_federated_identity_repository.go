@@ -0,0 +1,92 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+)
+
+type dynamoFederatedIdentityRepo struct {
+	cli   *dynamodb.Client
+	table string
+}
+
+// federatedIdentityItem is keyed by provider (partition key) and subject (sort key).
+type federatedIdentityItem struct {
+	Provider  string `dynamodbav:"provider"`
+	Subject   string `dynamodbav:"subject"`
+	UserID    int64  `dynamodbav:"userId"`
+	Email     string `dynamodbav:"email,omitempty"`
+	CreatedAt int64  `dynamodbav:"createdAt"`
+}
+
+func NewDynamoFederatedIdentityRepository(ctx context.Context, cfg *config.Config) (port.FederatedIdentityRepository, error) {
+	awsCfg, err := awscfg.LoadDefaultConfig(ctx, awscfg.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		return nil, err
+	}
+	return &dynamoFederatedIdentityRepo{cli: dynamodb.NewFromConfig(awsCfg), table: cfg.FederatedIdentitiesTableName}, nil
+}
+
+func (r *dynamoFederatedIdentityRepo) Create(ctx context.Context, f *domain.FederatedIdentity) (bool, error) {
+	av, err := attributevalue.MarshalMap(federatedIdentityItem{
+		Provider:  f.Provider,
+		Subject:   f.Subject,
+		UserID:    f.UserID,
+		Email:     f.Email,
+		CreatedAt: f.CreatedAt,
+	})
+	if err != nil {
+		return false, err
+	}
+	_, err = r.cli.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.table),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(subject)"),
+	})
+	if err != nil {
+		var cce *types.ConditionalCheckFailedException
+		if errors.As(err, &cce) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *dynamoFederatedIdentityRepo) Get(ctx context.Context, provider, subject string) (*domain.FederatedIdentity, error) {
+	res, err := r.cli.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.table),
+		Key: map[string]types.AttributeValue{
+			"provider": &types.AttributeValueMemberS{Value: provider},
+			"subject":  &types.AttributeValueMemberS{Value: subject},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.Item == nil {
+		return nil, nil
+	}
+	var it federatedIdentityItem
+	if err := attributevalue.UnmarshalMap(res.Item, &it); err != nil {
+		return nil, err
+	}
+	return &domain.FederatedIdentity{
+		Provider:  it.Provider,
+		Subject:   it.Subject,
+		UserID:    it.UserID,
+		Email:     it.Email,
+		CreatedAt: it.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package datasource
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+)
+
+type dynamoOAuthClientRepo struct {
+	cli   *dynamodb.Client
+	table string
+}
+
+// oauthClientItem is keyed by clientId. Clients are provisioned out of band; secretHash is
+// the hex SHA-256 of the client secret, absent for public clients.
+type oauthClientItem struct {
+	ClientID     string   `dynamodbav:"clientId"`
+	SecretHash   string   `dynamodbav:"secretHash,omitempty"`
+	Name         string   `dynamodbav:"name"`
+	Scopes       []string `dynamodbav:"scopes,omitempty"`
+	RedirectURIs []string `dynamodbav:"redirectUris,omitempty"`
+	CreatedAt    int64    `dynamodbav:"createdAt"`
+}
+
+func NewDynamoOAuthClientRepository(ctx context.Context, cfg *config.Config) (port.OAuthClientRepository, error) {
+	awsCfg, err := awscfg.LoadDefaultConfig(ctx, awscfg.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		return nil, err
+	}
+	return &dynamoOAuthClientRepo{cli: dynamodb.NewFromConfig(awsCfg), table: cfg.OAuthClientsTableName}, nil
+}
+
+func (r *dynamoOAuthClientRepo) GetByID(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+	res, err := r.cli.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.table),
+		Key:       map[string]types.AttributeValue{"clientId": &types.AttributeValueMemberS{Value: clientID}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.Item == nil {
+		return nil, nil
+	}
+	var it oauthClientItem
+	if err := attributevalue.UnmarshalMap(res.Item, &it); err != nil {
+		return nil, err
+	}
+	return &domain.OAuthClient{
+		ClientID:     it.ClientID,
+		SecretHash:   it.SecretHash,
+		Name:         it.Name,
+		Scopes:       it.Scopes,
+		RedirectURIs: it.RedirectURIs,
+		CreatedAt:    it.CreatedAt,
+	}, nil
+}
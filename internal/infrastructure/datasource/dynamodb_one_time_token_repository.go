@@ -0,0 +1,108 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+)
+
+type dynamoOneTimeTokenRepo struct {
+	cli   *dynamodb.Client
+	table string
+}
+
+// oneTimeTokenItem is keyed by tokenHash; expiresAt doubles as the table's TTL attribute.
+type oneTimeTokenItem struct {
+	TokenHash string `dynamodbav:"tokenHash"`
+	Purpose   string `dynamodbav:"purpose"`
+	UserID    int64  `dynamodbav:"userId"`
+	Email     string `dynamodbav:"email,omitempty"`
+	CreatedAt int64  `dynamodbav:"createdAt"`
+	ExpiresAt int64  `dynamodbav:"expiresAt"`
+	UsedAt    int64  `dynamodbav:"usedAt,omitempty"`
+	// federated login state
+	Provider     string `dynamodbav:"provider,omitempty"`
+	Nonce        string `dynamodbav:"nonce,omitempty"`
+	CodeVerifier string `dynamodbav:"codeVerifier,omitempty"`
+}
+
+func NewDynamoOneTimeTokenRepository(ctx context.Context, cfg *config.Config) (port.OneTimeTokenRepository, error) {
+	awsCfg, err := awscfg.LoadDefaultConfig(ctx, awscfg.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		return nil, err
+	}
+	return &dynamoOneTimeTokenRepo{cli: dynamodb.NewFromConfig(awsCfg), table: cfg.OneTimeTokensTableName}, nil
+}
+
+func (r *dynamoOneTimeTokenRepo) Create(ctx context.Context, t *domain.OneTimeToken) error {
+	av, err := attributevalue.MarshalMap(oneTimeTokenItem{
+		TokenHash:    t.TokenHash,
+		Purpose:      t.Purpose,
+		UserID:       t.UserID,
+		Email:        t.Email,
+		CreatedAt:    t.CreatedAt,
+		ExpiresAt:    t.ExpiresAt,
+		UsedAt:       t.UsedAt,
+		Provider:     t.Provider,
+		Nonce:        t.Nonce,
+		CodeVerifier: t.CodeVerifier,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.cli.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.table),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(tokenHash)"),
+	})
+	return err
+}
+
+// Consume relies on a conditional update so that two concurrent requests presenting the
+// same token cannot both succeed. TTL deletion is lazy, hence the explicit expiry check.
+func (r *dynamoOneTimeTokenRepo) Consume(ctx context.Context, tokenHash, purpose string, now int64) (*domain.OneTimeToken, error) {
+	res, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.table),
+		Key:                 map[string]types.AttributeValue{"tokenHash": &types.AttributeValueMemberS{Value: tokenHash}},
+		UpdateExpression:    aws.String("SET usedAt = :now"),
+		ConditionExpression: aws.String("attribute_exists(tokenHash) AND attribute_not_exists(usedAt) AND purpose = :p AND expiresAt > :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(now, 10)},
+			":p":   &types.AttributeValueMemberS{Value: purpose},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	if err != nil {
+		var cce *types.ConditionalCheckFailedException
+		if errors.As(err, &cce) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var it oneTimeTokenItem
+	if err := attributevalue.UnmarshalMap(res.Attributes, &it); err != nil {
+		return nil, err
+	}
+	return &domain.OneTimeToken{
+		TokenHash:    it.TokenHash,
+		Purpose:      it.Purpose,
+		UserID:       it.UserID,
+		Email:        it.Email,
+		CreatedAt:    it.CreatedAt,
+		ExpiresAt:    it.ExpiresAt,
+		UsedAt:       it.UsedAt,
+		Provider:     it.Provider,
+		Nonce:        it.Nonce,
+		CodeVerifier: it.CodeVerifier,
+	}, nil
+}
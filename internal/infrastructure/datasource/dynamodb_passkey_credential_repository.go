@@ -0,0 +1,150 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+)
+
+type dynamoPasskeyCredentialRepo struct {
+	cli   *dynamodb.Client
+	table string
+}
+
+// passkeyCredentialItem is keyed by credentialId; userId is the partition key of the
+// user_index GSI used to list a user's passkeys.
+type passkeyCredentialItem struct {
+	CredentialID string `dynamodbav:"credentialId"`
+	UserID       int64  `dynamodbav:"userId"`
+	PublicKey    []byte `dynamodbav:"publicKey"`
+	SignCount    uint32 `dynamodbav:"signCount"`
+	Name         string `dynamodbav:"name"`
+	CreatedAt    int64  `dynamodbav:"createdAt"`
+	LastUsedAt   int64  `dynamodbav:"lastUsedAt,omitempty"`
+}
+
+func NewDynamoPasskeyCredentialRepository(ctx context.Context, cfg *config.Config) (port.PasskeyCredentialRepository, error) {
+	awsCfg, err := awscfg.LoadDefaultConfig(ctx, awscfg.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		return nil, err
+	}
+	return &dynamoPasskeyCredentialRepo{cli: dynamodb.NewFromConfig(awsCfg), table: cfg.PasskeyCredentialsTableName}, nil
+}
+
+func (r *dynamoPasskeyCredentialRepo) Create(ctx context.Context, c *domain.PasskeyCredential) (bool, error) {
+	av, err := attributevalue.MarshalMap(passkeyCredentialItem{
+		CredentialID: c.CredentialID,
+		UserID:       c.UserID,
+		PublicKey:    c.PublicKey,
+		SignCount:    c.SignCount,
+		Name:         c.Name,
+		CreatedAt:    c.CreatedAt,
+		LastUsedAt:   c.LastUsedAt,
+	})
+	if err != nil {
+		return false, err
+	}
+	_, err = r.cli.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.table),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(credentialId)"),
+	})
+	if err != nil {
+		var cce *types.ConditionalCheckFailedException
+		if errors.As(err, &cce) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *dynamoPasskeyCredentialRepo) GetByID(ctx context.Context, credentialID string) (*domain.PasskeyCredential, error) {
+	res, err := r.cli.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.table),
+		Key:            map[string]types.AttributeValue{"credentialId": &types.AttributeValueMemberS{Value: credentialID}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.Item == nil {
+		return nil, nil
+	}
+	var it passkeyCredentialItem
+	if err := attributevalue.UnmarshalMap(res.Item, &it); err != nil {
+		return nil, err
+	}
+	return it.toDomain(), nil
+}
+
+func (r *dynamoPasskeyCredentialRepo) ListByUser(ctx context.Context, userID int64) ([]*domain.PasskeyCredential, error) {
+	p := dynamodb.NewQueryPaginator(r.cli, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table),
+		IndexName:                 aws.String("user_index"),
+		KeyConditionExpression:    aws.String("userId = :u"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":u": &types.AttributeValueMemberN{Value: strconv.FormatInt(userID, 10)}},
+	})
+	var out []*domain.PasskeyCredential
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var items []passkeyCredentialItem
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, err
+		}
+		for _, it := range items {
+			out = append(out, it.toDomain())
+		}
+	}
+	return out, nil
+}
+
+// UpdateSignCount compares and sets the counter in one conditional write, so two assertions
+// carrying the same counter cannot both pass. A zero counter only matches a stored zero;
+// it must not reset a counter the authenticator started using.
+func (r *dynamoPasskeyCredentialRepo) UpdateSignCount(ctx context.Context, credentialID string, signCount uint32, usedAt int64) (bool, error) {
+	cond := "attribute_exists(credentialId) AND signCount < :c"
+	if signCount == 0 {
+		cond = "attribute_exists(credentialId) AND signCount = :c"
+	}
+	_, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.table),
+		Key:                 map[string]types.AttributeValue{"credentialId": &types.AttributeValueMemberS{Value: credentialID}},
+		UpdateExpression:    aws.String("SET signCount = :c, lastUsedAt = :u"),
+		ConditionExpression: aws.String(cond),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":c": &types.AttributeValueMemberN{Value: strconv.FormatUint(uint64(signCount), 10)},
+			":u": &types.AttributeValueMemberN{Value: strconv.FormatInt(usedAt, 10)},
+		},
+	})
+	var cce *types.ConditionalCheckFailedException
+	if errors.As(err, &cce) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (it passkeyCredentialItem) toDomain() *domain.PasskeyCredential {
+	return &domain.PasskeyCredential{
+		CredentialID: it.CredentialID,
+		UserID:       it.UserID,
+		PublicKey:    it.PublicKey,
+		SignCount:    it.SignCount,
+		Name:         it.Name,
+		CreatedAt:    it.CreatedAt,
+		LastUsedAt:   it.LastUsedAt,
+	}
+}
@@ -0,0 +1,151 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+)
+
+type dynamoPersonalAccessTokenRepo struct {
+	cli   *dynamodb.Client
+	table string
+}
+
+// personalAccessTokenItem is keyed by tokenHash; userId is the partition key of the
+// user_index GSI used to list a user's tokens. expiresAt is the table's TTL attribute and
+// is left out for tokens that do not expire.
+type personalAccessTokenItem struct {
+	TokenHash  string   `dynamodbav:"tokenHash"`
+	TokenID    string   `dynamodbav:"tokenId"`
+	UserID     int64    `dynamodbav:"userId"`
+	Name       string   `dynamodbav:"name"`
+	Scopes     []string `dynamodbav:"scopes"`
+	CreatedAt  int64    `dynamodbav:"createdAt"`
+	ExpiresAt  int64    `dynamodbav:"expiresAt,omitempty"`
+	LastUsedAt int64    `dynamodbav:"lastUsedAt,omitempty"`
+}
+
+func NewDynamoPersonalAccessTokenRepository(ctx context.Context, cfg *config.Config) (port.PersonalAccessTokenRepository, error) {
+	awsCfg, err := awscfg.LoadDefaultConfig(ctx, awscfg.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		return nil, err
+	}
+	return &dynamoPersonalAccessTokenRepo{cli: dynamodb.NewFromConfig(awsCfg), table: cfg.PersonalAccessTokensTableName}, nil
+}
+
+func (r *dynamoPersonalAccessTokenRepo) Create(ctx context.Context, t *domain.PersonalAccessToken) error {
+	av, err := attributevalue.MarshalMap(personalAccessTokenItem{
+		TokenHash:  t.TokenHash,
+		TokenID:    t.TokenID,
+		UserID:     t.UserID,
+		Name:       t.Name,
+		Scopes:     t.Scopes,
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.cli.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.table),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(tokenHash)"),
+	})
+	return err
+}
+
+func (r *dynamoPersonalAccessTokenRepo) GetByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	res, err := r.cli.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.table),
+		Key:            personalAccessTokenKey(tokenHash),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.Item == nil {
+		return nil, nil
+	}
+	var it personalAccessTokenItem
+	if err := attributevalue.UnmarshalMap(res.Item, &it); err != nil {
+		return nil, err
+	}
+	return it.toDomain(), nil
+}
+
+func (r *dynamoPersonalAccessTokenRepo) ListByUser(ctx context.Context, userID int64) ([]*domain.PersonalAccessToken, error) {
+	p := dynamodb.NewQueryPaginator(r.cli, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table),
+		IndexName:                 aws.String("user_index"),
+		KeyConditionExpression:    aws.String("userId = :u"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":u": &types.AttributeValueMemberN{Value: strconv.FormatInt(userID, 10)}},
+	})
+	var out []*domain.PersonalAccessToken
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var items []personalAccessTokenItem
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, err
+		}
+		for _, it := range items {
+			out = append(out, it.toDomain())
+		}
+	}
+	return out, nil
+}
+
+func (r *dynamoPersonalAccessTokenRepo) Touch(ctx context.Context, tokenHash string, lastUsedAt int64) error {
+	_, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.table),
+		Key:                 personalAccessTokenKey(tokenHash),
+		UpdateExpression:    aws.String("SET lastUsedAt = :t"),
+		ConditionExpression: aws.String("attribute_exists(tokenHash)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":t": &types.AttributeValueMemberN{Value: strconv.FormatInt(lastUsedAt, 10)},
+		},
+	})
+	var cce *types.ConditionalCheckFailedException
+	if errors.As(err, &cce) {
+		return nil
+	}
+	return err
+}
+
+func (r *dynamoPersonalAccessTokenRepo) Delete(ctx context.Context, tokenHash string) error {
+	_, err := r.cli.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.table),
+		Key:       personalAccessTokenKey(tokenHash),
+	})
+	return err
+}
+
+func personalAccessTokenKey(tokenHash string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{"tokenHash": &types.AttributeValueMemberS{Value: tokenHash}}
+}
+
+func (it personalAccessTokenItem) toDomain() *domain.PersonalAccessToken {
+	return &domain.PersonalAccessToken{
+		TokenHash:  it.TokenHash,
+		TokenID:    it.TokenID,
+		UserID:     it.UserID,
+		Name:       it.Name,
+		Scopes:     it.Scopes,
+		CreatedAt:  it.CreatedAt,
+		ExpiresAt:  it.ExpiresAt,
+		LastUsedAt: it.LastUsedAt,
+	}
+}
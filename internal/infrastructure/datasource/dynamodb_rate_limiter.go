@@ -0,0 +1,101 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+)
+
+// rateLimitMaxAttempts bounds the optimistic-locking retries of a contended bucket.
+const rateLimitMaxAttempts = 3
+
+type dynamoRateLimiter struct {
+	cli   *dynamodb.Client
+	table string
+}
+
+// rateLimitItem is keyed by bucketKey. expiresAt is the table's TTL attribute: it is set to
+// when the bucket will be full again, after which a missing item means the same thing.
+type rateLimitItem struct {
+	Key       string  `dynamodbav:"bucketKey"`
+	Tokens    float64 `dynamodbav:"tokens"`
+	UpdatedAt int64   `dynamodbav:"updatedAt"` // unix milliseconds
+	ExpiresAt int64   `dynamodbav:"expiresAt"`
+}
+
+func NewDynamoRateLimiter(ctx context.Context, cfg *config.Config) (port.RateLimiter, error) {
+	awsCfg, err := awscfg.LoadDefaultConfig(ctx, awscfg.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		return nil, err
+	}
+	return &dynamoRateLimiter{cli: dynamodb.NewFromConfig(awsCfg), table: cfg.RateLimitsTableName}, nil
+}
+
+// Allow reads the bucket, takes a token and writes it back on the condition that nobody
+// updated it meanwhile, retrying a few times under contention.
+func (l *dynamoRateLimiter) Allow(ctx context.Context, key string, limit domain.RateLimit) (bool, time.Duration, error) {
+	for range rateLimitMaxAttempts {
+		res, err := l.cli.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName:      aws.String(l.table),
+			Key:            map[string]types.AttributeValue{"bucketKey": &types.AttributeValueMemberS{Value: key}},
+			ConsistentRead: aws.Bool(true),
+		})
+		if err != nil {
+			return false, 0, err
+		}
+		now := time.Now()
+		tokens, last := float64(limit.Burst), now
+		condition := aws.String("attribute_not_exists(bucketKey)")
+		var values map[string]types.AttributeValue
+		if res.Item != nil {
+			var it rateLimitItem
+			if err := attributevalue.UnmarshalMap(res.Item, &it); err != nil {
+				return false, 0, err
+			}
+			tokens, last = it.Tokens, time.UnixMilli(it.UpdatedAt)
+			condition = aws.String("updatedAt = :prev")
+			values = map[string]types.AttributeValue{":prev": &types.AttributeValueMemberN{Value: strconv.FormatInt(it.UpdatedAt, 10)}}
+		}
+
+		remaining, allowed, retryAfter := limit.Take(tokens, last, now)
+		if !allowed {
+			return false, retryAfter, nil
+		}
+		full := now.Add(time.Duration((float64(limit.Burst) - remaining) * float64(limit.Interval)))
+		av, err := attributevalue.MarshalMap(rateLimitItem{
+			Key:       key,
+			Tokens:    remaining,
+			UpdatedAt: now.UnixMilli(),
+			ExpiresAt: full.Unix() + 1,
+		})
+		if err != nil {
+			return false, 0, err
+		}
+		_, err = l.cli.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName:                 aws.String(l.table),
+			Item:                      av,
+			ConditionExpression:       condition,
+			ExpressionAttributeValues: values,
+		})
+		if err == nil {
+			return true, 0, nil
+		}
+		var cce *types.ConditionalCheckFailedException
+		if !errors.As(err, &cce) {
+			return false, 0, err
+		}
+	}
+	// still contended after several attempts: the key is clearly busy, so throttle it
+	return false, limit.Interval, nil
+}
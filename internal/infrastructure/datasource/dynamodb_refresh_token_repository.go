@@ -0,0 +1,136 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+)
+
+type dynamoRefreshTokenRepo struct {
+	cli   *dynamodb.Client
+	table string
+}
+
+// refreshTokenItem is keyed by tokenHash; expiresAt doubles as the table's TTL attribute
+// and familyId is the partition key of the family_index GSI.
+type refreshTokenItem struct {
+	TokenHash string `dynamodbav:"tokenHash"`
+	FamilyID  string `dynamodbav:"familyId"`
+	UserID    int64  `dynamodbav:"userId"`
+	CreatedAt int64  `dynamodbav:"createdAt"`
+	ExpiresAt int64  `dynamodbav:"expiresAt"`
+	UsedAt    int64  `dynamodbav:"usedAt,omitempty"`
+	Revoked   bool   `dynamodbav:"revoked"`
+}
+
+func NewDynamoRefreshTokenRepository(ctx context.Context, cfg *config.Config) (port.RefreshTokenRepository, error) {
+	awsCfg, err := awscfg.LoadDefaultConfig(ctx, awscfg.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		return nil, err
+	}
+	return &dynamoRefreshTokenRepo{cli: dynamodb.NewFromConfig(awsCfg), table: cfg.RefreshTokensTableName}, nil
+}
+
+func (r *dynamoRefreshTokenRepo) Create(ctx context.Context, t *domain.RefreshToken) error {
+	av, err := attributevalue.MarshalMap(refreshTokenItem{
+		TokenHash: t.TokenHash,
+		FamilyID:  t.FamilyID,
+		UserID:    t.UserID,
+		CreatedAt: t.CreatedAt,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+		Revoked:   t.Revoked,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.cli.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.table),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(tokenHash)"),
+	})
+	return err
+}
+
+func (r *dynamoRefreshTokenRepo) GetByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	res, err := r.cli.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.table),
+		Key:            map[string]types.AttributeValue{"tokenHash": &types.AttributeValueMemberS{Value: tokenHash}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.Item == nil {
+		return nil, nil
+	}
+	var it refreshTokenItem
+	if err := attributevalue.UnmarshalMap(res.Item, &it); err != nil {
+		return nil, err
+	}
+	return &domain.RefreshToken{
+		TokenHash: it.TokenHash,
+		FamilyID:  it.FamilyID,
+		UserID:    it.UserID,
+		CreatedAt: it.CreatedAt,
+		ExpiresAt: it.ExpiresAt,
+		UsedAt:    it.UsedAt,
+		Revoked:   it.Revoked,
+	}, nil
+}
+
+func (r *dynamoRefreshTokenRepo) MarkUsed(ctx context.Context, tokenHash string, usedAt int64) (bool, error) {
+	_, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.table),
+		Key:                       map[string]types.AttributeValue{"tokenHash": &types.AttributeValueMemberS{Value: tokenHash}},
+		UpdateExpression:          aws.String("SET usedAt = :u"),
+		ConditionExpression:       aws.String("attribute_exists(tokenHash) AND attribute_not_exists(usedAt)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":u": &types.AttributeValueMemberN{Value: strconv.FormatInt(usedAt, 10)}},
+	})
+	if err != nil {
+		var cce *types.ConditionalCheckFailedException
+		if errors.As(err, &cce) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *dynamoRefreshTokenRepo) RevokeFamily(ctx context.Context, familyID string) error {
+	p := dynamodb.NewQueryPaginator(r.cli, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table),
+		IndexName:                 aws.String("family_index"),
+		KeyConditionExpression:    aws.String("familyId = :f"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":f": &types.AttributeValueMemberS{Value: familyID}},
+		ProjectionExpression:      aws.String("tokenHash"),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, item := range page.Items {
+			_, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+				TableName:                 aws.String(r.table),
+				Key:                       map[string]types.AttributeValue{"tokenHash": item["tokenHash"]},
+				UpdateExpression:          aws.String("SET revoked = :t"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{":t": &types.AttributeValueMemberBOOL{Value: true}},
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+)
+
+type dynamoSessionRepo struct {
+	cli   *dynamodb.Client
+	table string
+}
+
+// sessionItem is keyed by sessionId; userId is the partition key of the user_index GSI used
+// to list a user's sessions. expiresAt is the table's TTL attribute.
+type sessionItem struct {
+	SessionID  string `dynamodbav:"sessionId"`
+	UserID     int64  `dynamodbav:"userId"`
+	Device     string `dynamodbav:"device,omitempty"`
+	UserAgent  string `dynamodbav:"userAgent,omitempty"`
+	IPAddress  string `dynamodbav:"ipAddress,omitempty"`
+	CreatedAt  int64  `dynamodbav:"createdAt"`
+	LastSeenAt int64  `dynamodbav:"lastSeenAt"`
+	ExpiresAt  int64  `dynamodbav:"expiresAt"`
+}
+
+func NewDynamoSessionRepository(ctx context.Context, cfg *config.Config) (port.SessionRepository, error) {
+	awsCfg, err := awscfg.LoadDefaultConfig(ctx, awscfg.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		return nil, err
+	}
+	return &dynamoSessionRepo{cli: dynamodb.NewFromConfig(awsCfg), table: cfg.SessionsTableName}, nil
+}
+
+func (r *dynamoSessionRepo) Create(ctx context.Context, s *domain.Session) error {
+	av, err := attributevalue.MarshalMap(sessionItem{
+		SessionID:  s.SessionID,
+		UserID:     s.UserID,
+		Device:     s.Device,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.cli.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.table),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(sessionId)"),
+	})
+	return err
+}
+
+func (r *dynamoSessionRepo) GetByID(ctx context.Context, sessionID string) (*domain.Session, error) {
+	res, err := r.cli.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.table),
+		Key:            sessionKey(sessionID),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.Item == nil {
+		return nil, nil
+	}
+	var it sessionItem
+	if err := attributevalue.UnmarshalMap(res.Item, &it); err != nil {
+		return nil, err
+	}
+	return it.toDomain(), nil
+}
+
+func (r *dynamoSessionRepo) ListByUser(ctx context.Context, userID int64) ([]*domain.Session, error) {
+	p := dynamodb.NewQueryPaginator(r.cli, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table),
+		IndexName:                 aws.String("user_index"),
+		KeyConditionExpression:    aws.String("userId = :u"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":u": &types.AttributeValueMemberN{Value: strconv.FormatInt(userID, 10)}},
+	})
+	var out []*domain.Session
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var items []sessionItem
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, err
+		}
+		for _, it := range items {
+			out = append(out, it.toDomain())
+		}
+	}
+	return out, nil
+}
+
+func (r *dynamoSessionRepo) Touch(ctx context.Context, sessionID string, lastSeenAt, expiresAt int64) error {
+	_, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.table),
+		Key:                 sessionKey(sessionID),
+		UpdateExpression:    aws.String("SET lastSeenAt = :s, expiresAt = :e"),
+		ConditionExpression: aws.String("attribute_exists(sessionId)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":s": &types.AttributeValueMemberN{Value: strconv.FormatInt(lastSeenAt, 10)},
+			":e": &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt, 10)},
+		},
+	})
+	var cce *types.ConditionalCheckFailedException
+	if errors.As(err, &cce) {
+		return nil
+	}
+	return err
+}
+
+func (r *dynamoSessionRepo) Delete(ctx context.Context, sessionID string) error {
+	_, err := r.cli.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.table),
+		Key:       sessionKey(sessionID),
+	})
+	return err
+}
+
+func sessionKey(sessionID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{"sessionId": &types.AttributeValueMemberS{Value: sessionID}}
+}
+
+func (it sessionItem) toDomain() *domain.Session {
+	return &domain.Session{
+		SessionID:  it.SessionID,
+		UserID:     it.UserID,
+		Device:     it.Device,
+		UserAgent:  it.UserAgent,
+		IPAddress:  it.IPAddress,
+		CreatedAt:  it.CreatedAt,
+		LastSeenAt: it.LastSeenAt,
+		ExpiresAt:  it.ExpiresAt,
+	}
+}
@@ -0,0 +1,71 @@
+package datasource
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+)
+
+type dynamoTokenRevocationStore struct {
+	cli   *dynamodb.Client
+	table string
+}
+
+// revokedTokenItem is keyed by jti; expiresAt is the table's TTL attribute so entries
+// disappear once the token they refer to could no longer be used anyway.
+type revokedTokenItem struct {
+	TokenID   string `dynamodbav:"jti"`
+	ExpiresAt int64  `dynamodbav:"expiresAt"`
+}
+
+func NewDynamoTokenRevocationStore(ctx context.Context, cfg *config.Config) (port.TokenRevocationStore, error) {
+	awsCfg, err := awscfg.LoadDefaultConfig(ctx, awscfg.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		return nil, err
+	}
+	return &dynamoTokenRevocationStore{cli: dynamodb.NewFromConfig(awsCfg), table: cfg.RevokedTokensTableName}, nil
+}
+
+func (s *dynamoTokenRevocationStore) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	av, err := attributevalue.MarshalMap(revokedTokenItem{TokenID: tokenID, ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		return err
+	}
+	_, err = s.cli.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.table),
+		Item:      av,
+	})
+	return err
+}
+
+func (s *dynamoTokenRevocationStore) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	res, err := s.cli.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.table),
+		Key:       map[string]types.AttributeValue{"jti": &types.AttributeValueMemberS{Value: tokenID}},
+	})
+	if err != nil {
+		return false, err
+	}
+	if res.Item == nil {
+		return false, nil
+	}
+	// TTL deletion is best-effort, so expired entries may still be returned
+	exp, ok := res.Item["expiresAt"].(*types.AttributeValueMemberN)
+	if !ok {
+		return true, nil
+	}
+	ts, err := strconv.ParseInt(exp.Value, 10, 64)
+	if err != nil {
+		return true, nil
+	}
+	return ts > time.Now().Unix(), nil
+}
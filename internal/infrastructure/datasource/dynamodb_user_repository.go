@@ -17,18 +17,39 @@ import (
 )
 
 type dynamoUserRepo struct {
-	cli        *dynamodb.Client
-	usersTable string
-	idsTable   string
+	cli         *dynamodb.Client
+	usersTable  string
+	idsTable    string
+	emailsTable string
 }
 
+// emailItem claims an email address for one user. The email_index GSI cannot enforce
+// uniqueness, so every write that sets an email also puts its claim, conditionally, in the
+// same transaction. Accounts created before claims existed have none; callers still check
+// the GSI for them.
+type emailItem struct {
+	Email  string `dynamodbav:"email"`
+	UserID int64  `dynamodbav:"userId"`
+}
+
+// userItem.EmailVerified is nil for accounts created before email verification existed;
+// they are treated as verified so that turning verification on does not lock them out.
 type userItem struct {
-	UserID    int64  `dynamodbav:"userId"`
-	Name      string `dynamodbav:"name"`
-	Email     string `dynamodbav:"email"`
-	Password  string `dynamodbav:"password"`
-	CreatedAt int64  `dynamodbav:"createdAt"`
-	UpdatedAt int64  `dynamodbav:"updatedAt"`
+	UserID           int64    `dynamodbav:"userId"`
+	Name             string   `dynamodbav:"name"`
+	Email            string   `dynamodbav:"email"`
+	PendingEmail     string   `dynamodbav:"pendingEmail,omitempty"`
+	Password         string   `dynamodbav:"password"`
+	EmailVerified    *bool    `dynamodbav:"emailVerified,omitempty"`
+	Roles            []string `dynamodbav:"roles,omitempty"`
+	MFAEnabled       bool     `dynamodbav:"mfaEnabled,omitempty"`
+	TOTPSecret       string   `dynamodbav:"totpSecret,omitempty"`
+	TOTPLastStep     int64    `dynamodbav:"totpLastStep,omitempty"`
+	FailedLogins     int      `dynamodbav:"failedLogins,omitempty"`
+	LockedUntil      int64    `dynamodbav:"lockedUntil,omitempty"`
+	TokensValidAfter int64    `dynamodbav:"tokensValidAfter,omitempty"`
+	CreatedAt        int64    `dynamodbav:"createdAt"`
+	UpdatedAt        int64    `dynamodbav:"updatedAt"`
 }
 
 func NewDynamoUserRepository(ctx context.Context, cfg *config.Config) (port.UserRepository, error) {
@@ -36,7 +57,12 @@ func NewDynamoUserRepository(ctx context.Context, cfg *config.Config) (port.User
 	if err != nil {
 		return nil, err
 	}
-	return &dynamoUserRepo{cli: dynamodb.NewFromConfig(awsCfg), usersTable: cfg.UsersTableName, idsTable: cfg.IdsTableName}, nil
+	return &dynamoUserRepo{
+		cli:         dynamodb.NewFromConfig(awsCfg),
+		usersTable:  cfg.UsersTableName,
+		idsTable:    cfg.IdsTableName,
+		emailsTable: cfg.EmailsTableName,
+	}, nil
 }
 
 func (r *dynamoUserRepo) nextID(ctx context.Context, seq string) (int64, error) {
@@ -70,26 +96,31 @@ func (r *dynamoUserRepo) Create(ctx context.Context, u *domain.User) error {
 		return err
 	}
 	u.UserID = id
-	item := userItem{
-		UserID:    u.UserID,
-		Name:      u.Name,
-		Email:     u.Email,
-		Password:  u.Password,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
-	}
-	av, err := attributevalue.MarshalMap(item)
+	av, err := attributevalue.MarshalMap(newUserItem(u))
 	if err != nil {
 		return err
 	}
-	_, err = r.cli.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName:           aws.String(r.usersTable),
-		Item:                av,
-		ConditionExpression: aws.String("attribute_not_exists(userId)"),
+	claim, err := attributevalue.MarshalMap(emailItem{Email: u.Email, UserID: u.UserID})
+	if err != nil {
+		return err
+	}
+	_, err = r.cli.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Put: &types.Put{
+				TableName:           aws.String(r.usersTable),
+				Item:                av,
+				ConditionExpression: aws.String("attribute_not_exists(userId)"),
+			}},
+			{Put: &types.Put{
+				TableName:           aws.String(r.emailsTable),
+				Item:                claim,
+				ConditionExpression: aws.String("attribute_not_exists(email)"),
+			}},
+		},
 	})
 	if err != nil {
-		var cce *types.ConditionalCheckFailedException
-		if errors.As(err, &cce) {
+		var tce *types.TransactionCanceledException
+		if errors.As(err, &tce) {
 			return errors.New("user already exists")
 		}
 	}
@@ -113,8 +144,7 @@ func (r *dynamoUserRepo) GetByID(ctx context.Context, userID int64) (*domain.Use
 	if err := attributevalue.UnmarshalMap(res.Item, &it); err != nil {
 		return nil, err
 	}
-	u := &domain.User{UserID: it.UserID, Name: it.Name, Email: it.Email, Password: it.Password, CreatedAt: it.CreatedAt, UpdatedAt: it.UpdatedAt}
-	return u, nil
+	return it.toDomain(), nil
 }
 
 func (r *dynamoUserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
@@ -137,6 +167,258 @@ func (r *dynamoUserRepo) GetByEmail(ctx context.Context, email string) (*domain.
 	if err := attributevalue.UnmarshalMap(res.Items[0], &it); err != nil {
 		return nil, err
 	}
-	u := &domain.User{UserID: it.UserID, Name: it.Name, Email: it.Email, Password: it.Password, CreatedAt: it.CreatedAt, UpdatedAt: it.UpdatedAt}
-	return u, nil
+	return it.toDomain(), nil
+}
+
+// SetPassword, SetPendingEmail, SetTOTPSecret, MarkEmailVerified and EnableMFA write only
+// the attributes they change, so they cannot undo concurrent writes to others, such as
+// failed login counts or an email change.
+func (r *dynamoUserRepo) SetPassword(ctx context.Context, userID int64, hash string, now int64) error {
+	_, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.usersTable),
+		Key:                 userKey(userID),
+		UpdateExpression:    aws.String("SET password = :p, tokensValidAfter = :now, updatedAt = :now REMOVE failedLogins, lockedUntil"),
+		ConditionExpression: aws.String("attribute_exists(userId)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":p":   &types.AttributeValueMemberS{Value: hash},
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(now, 10)},
+		},
+	})
+	return err
+}
+
+func (r *dynamoUserRepo) SetPendingEmail(ctx context.Context, userID int64, email, pendingEmail string, now int64) (bool, error) {
+	_, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.usersTable),
+		Key:                 userKey(userID),
+		UpdateExpression:    aws.String("SET pendingEmail = :p, updatedAt = :now"),
+		ConditionExpression: aws.String("email = :e"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":e":   &types.AttributeValueMemberS{Value: email},
+			":p":   &types.AttributeValueMemberS{Value: pendingEmail},
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(now, 10)},
+		},
+	})
+	var cce *types.ConditionalCheckFailedException
+	if errors.As(err, &cce) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *dynamoUserRepo) SetTOTPSecret(ctx context.Context, userID int64, secret string, now int64) (bool, error) {
+	_, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.usersTable),
+		Key:                 userKey(userID),
+		UpdateExpression:    aws.String("SET totpSecret = :s, updatedAt = :now REMOVE totpLastStep"),
+		ConditionExpression: aws.String("attribute_exists(userId) AND (attribute_not_exists(mfaEnabled) OR mfaEnabled = :false)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":s":     &types.AttributeValueMemberS{Value: secret},
+			":false": &types.AttributeValueMemberBOOL{Value: false},
+			":now":   &types.AttributeValueMemberN{Value: strconv.FormatInt(now, 10)},
+		},
+	})
+	var cce *types.ConditionalCheckFailedException
+	if errors.As(err, &cce) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *dynamoUserRepo) MarkEmailVerified(ctx context.Context, userID int64, now int64) error {
+	_, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.usersTable),
+		Key:                 userKey(userID),
+		UpdateExpression:    aws.String("SET emailVerified = :true, updatedAt = :now"),
+		ConditionExpression: aws.String("attribute_exists(userId)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":true": &types.AttributeValueMemberBOOL{Value: true},
+			":now":  &types.AttributeValueMemberN{Value: strconv.FormatInt(now, 10)},
+		},
+	})
+	return err
+}
+
+func (r *dynamoUserRepo) EnableMFA(ctx context.Context, userID int64, now int64) error {
+	_, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.usersTable),
+		Key:                 userKey(userID),
+		UpdateExpression:    aws.String("SET mfaEnabled = :true, updatedAt = :now"),
+		ConditionExpression: aws.String("attribute_exists(totpSecret)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":true": &types.AttributeValueMemberBOOL{Value: true},
+			":now":  &types.AttributeValueMemberN{Value: strconv.FormatInt(now, 10)},
+		},
+	})
+	return err
+}
+
+func (r *dynamoUserRepo) RecordLoginFailure(ctx context.Context, userID int64) (int, error) {
+	out, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.usersTable),
+		Key:                       userKey(userID),
+		UpdateExpression:          aws.String("ADD failedLogins :one"),
+		ConditionExpression:       aws.String("attribute_exists(userId)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":one": &types.AttributeValueMemberN{Value: "1"}},
+		ReturnValues:              types.ReturnValueUpdatedNew,
+	})
+	if err != nil {
+		return 0, err
+	}
+	attr, ok := out.Attributes["failedLogins"].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, errors.New("invalid failed logins response")
+	}
+	return strconv.Atoi(attr.Value)
+}
+
+func (r *dynamoUserRepo) LockUntil(ctx context.Context, userID int64, until int64) error {
+	_, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.usersTable),
+		Key:                       userKey(userID),
+		UpdateExpression:          aws.String("SET lockedUntil = :u"),
+		ConditionExpression:       aws.String("attribute_exists(userId)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":u": &types.AttributeValueMemberN{Value: strconv.FormatInt(until, 10)}},
+	})
+	return err
+}
+
+func (r *dynamoUserRepo) ResetLoginFailures(ctx context.Context, userID int64) error {
+	_, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.usersTable),
+		Key:                 userKey(userID),
+		UpdateExpression:    aws.String("REMOVE failedLogins, lockedUntil"),
+		ConditionExpression: aws.String("attribute_exists(userId)"),
+	})
+	return err
+}
+
+// UseTOTPStep only moves totpLastStep forward, so of two requests presenting the same code
+// only one gets through.
+func (r *dynamoUserRepo) UseTOTPStep(ctx context.Context, userID int64, step int64) (bool, error) {
+	_, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.usersTable),
+		Key:                       userKey(userID),
+		UpdateExpression:          aws.String("SET totpLastStep = :s"),
+		ConditionExpression:       aws.String("attribute_exists(userId) AND (attribute_not_exists(totpLastStep) OR totpLastStep < :s)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":s": &types.AttributeValueMemberN{Value: strconv.FormatInt(step, 10)}},
+	})
+	var cce *types.ConditionalCheckFailedException
+	if errors.As(err, &cce) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *dynamoUserRepo) ReplacePasswordHash(ctx context.Context, userID int64, oldHash, newHash string, updatedAt int64) error {
+	_, err := r.cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.usersTable),
+		Key:                 userKey(userID),
+		UpdateExpression:    aws.String("SET password = :new, updatedAt = :now"),
+		ConditionExpression: aws.String("password = :old"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":old": &types.AttributeValueMemberS{Value: oldHash},
+			":new": &types.AttributeValueMemberS{Value: newHash},
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(updatedAt, 10)},
+		},
+	})
+	var cce *types.ConditionalCheckFailedException
+	if errors.As(err, &cce) {
+		return nil
+	}
+	return err
+}
+
+// ChangeEmail claims the new address, swaps it in and releases the old claim in a single
+// transaction, so two accounts can never end up with the same email.
+func (r *dynamoUserRepo) ChangeEmail(ctx context.Context, userID int64, newEmail string, updatedAt int64) (bool, error) {
+	u, err := r.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if u == nil || u.PendingEmail != newEmail {
+		return false, nil
+	}
+	claim, err := attributevalue.MarshalMap(emailItem{Email: newEmail, UserID: userID})
+	if err != nil {
+		return false, err
+	}
+	_, err = r.cli.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Put: &types.Put{
+				TableName:           aws.String(r.emailsTable),
+				Item:                claim,
+				ConditionExpression: aws.String("attribute_not_exists(email)"),
+			}},
+			{Update: &types.Update{
+				TableName:           aws.String(r.usersTable),
+				Key:                 userKey(userID),
+				UpdateExpression:    aws.String("SET email = :new, emailVerified = :true, updatedAt = :now REMOVE pendingEmail"),
+				ConditionExpression: aws.String("email = :old AND pendingEmail = :new"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":old":  &types.AttributeValueMemberS{Value: u.Email},
+					":new":  &types.AttributeValueMemberS{Value: newEmail},
+					":true": &types.AttributeValueMemberBOOL{Value: true},
+					":now":  &types.AttributeValueMemberN{Value: strconv.FormatInt(updatedAt, 10)},
+				},
+			}},
+			{Delete: &types.Delete{
+				TableName:           aws.String(r.emailsTable),
+				Key:                 map[string]types.AttributeValue{"email": &types.AttributeValueMemberS{Value: u.Email}},
+				ConditionExpression: aws.String("attribute_not_exists(email) OR userId = :uid"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":uid": &types.AttributeValueMemberN{Value: strconv.FormatInt(userID, 10)},
+				},
+			}},
+		},
+	})
+	var tce *types.TransactionCanceledException
+	if errors.As(err, &tce) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func userKey(userID int64) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{"userId": &types.AttributeValueMemberN{Value: strconv.FormatInt(userID, 10)}}
+}
+
+func newUserItem(u *domain.User) userItem {
+	return userItem{
+		UserID:           u.UserID,
+		Name:             u.Name,
+		Email:            u.Email,
+		PendingEmail:     u.PendingEmail,
+		Password:         u.Password,
+		EmailVerified:    &u.EmailVerified,
+		Roles:            u.Roles,
+		MFAEnabled:       u.MFAEnabled,
+		TOTPSecret:       u.TOTPSecret,
+		TOTPLastStep:     u.TOTPLastStep,
+		FailedLogins:     u.FailedLogins,
+		LockedUntil:      u.LockedUntil,
+		TokensValidAfter: u.TokensValidAfter,
+		CreatedAt:        u.CreatedAt,
+		UpdatedAt:        u.UpdatedAt,
+	}
+}
+
+func (it userItem) toDomain() *domain.User {
+	return &domain.User{
+		UserID:           it.UserID,
+		Name:             it.Name,
+		Email:            it.Email,
+		PendingEmail:     it.PendingEmail,
+		Password:         it.Password,
+		EmailVerified:    it.EmailVerified == nil || *it.EmailVerified,
+		Roles:            it.Roles,
+		MFAEnabled:       it.MFAEnabled,
+		TOTPSecret:       it.TOTPSecret,
+		TOTPLastStep:     it.TOTPLastStep,
+		FailedLogins:     it.FailedLogins,
+		LockedUntil:      it.LockedUntil,
+		TokensValidAfter: it.TokensValidAfter,
+		CreatedAt:        it.CreatedAt,
+		UpdatedAt:        it.UpdatedAt,
+	}
 }
@@ -0,0 +1,60 @@
+package datasource
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+)
+
+// memoryPruneThreshold is the bucket count above which full buckets are dropped.
+const memoryPruneThreshold = 10000
+
+type memoryBucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // the bucket holds Burst tokens again from then on
+}
+
+// memoryRateLimiter keeps buckets in process memory, so each Lambda container enforces its
+// own limits. It is meant for tests and local development.
+type memoryRateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+}
+
+func NewMemoryRateLimiter() port.RateLimiter {
+	return &memoryRateLimiter{buckets: map[string]*memoryBucket{}}
+}
+
+func (l *memoryRateLimiter) Allow(_ context.Context, key string, limit domain.RateLimit) (bool, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	b, ok := l.buckets[key]
+	if !ok {
+		l.prune(now)
+		b = &memoryBucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+	tokens, allowed, retryAfter := limit.Take(b.tokens, b.last, now)
+	if !allowed {
+		return false, retryAfter, nil
+	}
+	b.tokens, b.last = tokens, now
+	b.full = now.Add(time.Duration((float64(limit.Burst) - tokens) * float64(limit.Interval)))
+	return true, 0, nil
+}
+
+func (l *memoryRateLimiter) prune(now time.Time) {
+	if len(l.buckets) < memoryPruneThreshold {
+		return
+	}
+	for k, b := range l.buckets {
+		if !now.Before(b.full) {
+			delete(l.buckets, k)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package datasource
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+)
+
+// rewind moves a bucket's clock back, as if d had passed since its last request.
+func (l *memoryRateLimiter) rewind(key string, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.buckets[key]
+	b.last = b.last.Add(-d)
+	b.full = b.full.Add(-d)
+}
+
+func TestMemoryRateLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	limit := domain.RateLimit{Burst: 2, Interval: time.Minute}
+
+	tests := []struct {
+		name  string
+		steps func(*testing.T, *memoryRateLimiter)
+	}{
+		{
+			name: "should allow a burst and then refuse with the time to the next token",
+			steps: func(t *testing.T, l *memoryRateLimiter) {
+				for i := 0; i < limit.Burst; i++ {
+					ok, _, err := l.Allow(ctx, "ip:login:1.2.3.4", limit)
+					assert.NoError(t, err)
+					assert.True(t, ok)
+				}
+				ok, retry, err := l.Allow(ctx, "ip:login:1.2.3.4", limit)
+				assert.NoError(t, err)
+				assert.False(t, ok)
+				assert.InDelta(t, float64(time.Minute), float64(retry), float64(time.Second))
+			},
+		},
+		{
+			name: "should allow one more request per elapsed interval",
+			steps: func(t *testing.T, l *memoryRateLimiter) {
+				for i := 0; i < limit.Burst; i++ {
+					_, _, _ = l.Allow(ctx, "k", limit)
+				}
+				l.rewind("k", time.Minute)
+				ok, _, _ := l.Allow(ctx, "k", limit)
+				assert.True(t, ok)
+				ok, _, _ = l.Allow(ctx, "k", limit)
+				assert.False(t, ok)
+			},
+		},
+		{
+			name: "should refill only up to the burst after a long pause",
+			steps: func(t *testing.T, l *memoryRateLimiter) {
+				_, _, _ = l.Allow(ctx, "k", limit)
+				l.rewind("k", time.Hour)
+				for i := 0; i < limit.Burst; i++ {
+					ok, _, _ := l.Allow(ctx, "k", limit)
+					assert.True(t, ok)
+				}
+				ok, _, _ := l.Allow(ctx, "k", limit)
+				assert.False(t, ok)
+			},
+		},
+		{
+			name: "should not let refused requests consume tokens",
+			steps: func(t *testing.T, l *memoryRateLimiter) {
+				for i := 0; i < limit.Burst+3; i++ {
+					_, _, _ = l.Allow(ctx, "k", limit)
+				}
+				l.rewind("k", time.Minute)
+				ok, _, _ := l.Allow(ctx, "k", limit)
+				assert.True(t, ok)
+			},
+		},
+		{
+			name: "should keep separate buckets per key",
+			steps: func(t *testing.T, l *memoryRateLimiter) {
+				for i := 0; i < limit.Burst; i++ {
+					_, _, _ = l.Allow(ctx, "a", limit)
+				}
+				ok, _, _ := l.Allow(ctx, "a", limit)
+				assert.False(t, ok)
+				ok, _, _ = l.Allow(ctx, "b", limit)
+				assert.True(t, ok)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			l := NewMemoryRateLimiter().(*memoryRateLimiter)
+
+			// Act & Assert
+			tt.steps(t, l)
+		})
+	}
+}
+
+func TestMemoryRateLimiter_PrunesFullBuckets(t *testing.T) {
+	// Arrange
+	l := NewMemoryRateLimiter().(*memoryRateLimiter)
+	limit := domain.RateLimit{Burst: 1, Interval: time.Second}
+	past := time.Now().Add(-time.Minute)
+	for i := 0; i < memoryPruneThreshold; i++ {
+		l.buckets[strconv.Itoa(i)] = &memoryBucket{last: past, full: past}
+	}
+
+	// Act
+	ok, _, err := l.Allow(context.Background(), "new", limit)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Len(t, l.buckets, 1)
+}
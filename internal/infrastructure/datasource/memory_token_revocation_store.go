@@ -0,0 +1,41 @@
+package datasource
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+)
+
+// memoryTokenRevocationStore keeps the denylist in process memory. It is meant for tests and
+// local development; entries are dropped lazily once their expiration has passed.
+type memoryTokenRevocationStore struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+}
+
+func NewMemoryTokenRevocationStore() port.TokenRevocationStore {
+	return &memoryTokenRevocationStore{revoked: map[string]time.Time{}}
+}
+
+func (s *memoryTokenRevocationStore) Revoke(_ context.Context, tokenID string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked[tokenID] = expiresAt
+	return nil
+}
+
+func (s *memoryTokenRevocationStore) IsRevoked(_ context.Context, tokenID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	exp, ok := s.revoked[tokenID]
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(exp) {
+		delete(s.revoked, tokenID)
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,31 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/logger"
+)
+
+type logNotifier struct {
+	log *logger.Logger
+}
+
+// NewLogNotifier writes notifications to the log instead of delivering them. It is meant
+// for development only. One-time tokens are left out, since anyone who can read the logs
+// could use them to take over the account.
+func NewLogNotifier(log *logger.Logger) port.Notifier {
+	return &logNotifier{log: log}
+}
+
+func (n *logNotifier) Notify(ctx context.Context, msg domain.Notification) error {
+	n.log.InfoContext(ctx, "notification",
+		"type", msg.Type,
+		"userId", msg.UserID,
+		"to", msg.To,
+		"expiresAt", time.Unix(msg.ExpiresAt, 0).UTC().Format(time.RFC3339),
+	)
+	return nil
+}
@@ -0,0 +1,82 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/domain"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/core/port"
+	"github.com/FIAP-SOAT-G20/hackathon-user-lambda/internal/infrastructure/config"
+)
+
+type sesNotifier struct {
+	cli  *sesv2.Client
+	from string
+}
+
+// NewSESNotifier emails notifications through Amazon SES from cfg.EmailFrom, which must be
+// a verified identity.
+func NewSESNotifier(ctx context.Context, cfg *config.Config) (port.Notifier, error) {
+	if cfg.EmailFrom == "" {
+		return nil, fmt.Errorf("EMAIL_FROM must be set to send notifications through SES")
+	}
+	awsCfg, err := awscfg.LoadDefaultConfig(ctx, awscfg.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		return nil, err
+	}
+	return &sesNotifier{cli: sesv2.NewFromConfig(awsCfg), from: cfg.EmailFrom}, nil
+}
+
+func (n *sesNotifier) Notify(ctx context.Context, msg domain.Notification) error {
+	subject, body := emailContent(msg)
+	_, err := n.cli.SendEmail(ctx, &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(n.from),
+		Destination:      &types.Destination{ToAddresses: []string{msg.To}},
+		Content: &types.EmailContent{
+			Simple: &types.Message{
+				Subject: &types.Content{Data: aws.String(subject), Charset: aws.String("UTF-8")},
+				Body: &types.Body{
+					Text: &types.Content{Data: aws.String(body), Charset: aws.String("UTF-8")},
+				},
+			},
+		},
+	})
+	return err
+}
+
+// emailContent renders the subject and plain-text body of a notification.
+func emailContent(msg domain.Notification) (subject, body string) {
+	greeting := "Hello,"
+	if msg.Name != "" {
+		greeting = fmt.Sprintf("Hello %s,", msg.Name)
+	}
+	expires := time.Unix(msg.ExpiresAt, 0).UTC().Format("2006-01-02 15:04 MST")
+	switch msg.Type {
+	case domain.NotificationPasswordReset:
+		return "Reset your password", fmt.Sprintf("%s\n\nUse this code to reset your password: %s\n\nIt expires at %s. If you did not ask for a password reset, you can ignore this email.\n",
+			greeting, msg.Token, expires)
+	case domain.NotificationEmailVerification:
+		return "Verify your email address", fmt.Sprintf("%s\n\nUse this code to verify your email address: %s\n\nIt expires at %s.\n",
+			greeting, msg.Token, expires)
+	case domain.NotificationEmailChange:
+		return "Confirm your new email address", fmt.Sprintf("%s\n\nUse this code to confirm your new email address: %s\n\nIt expires at %s. If you did not ask for this change, you can ignore this email.\n",
+			greeting, msg.Token, expires)
+	case domain.NotificationEmailChangeNotice:
+		return "Your email address is being changed", fmt.Sprintf("%s\n\nSomeone asked to change the email address of your account. If this was not you, change your password now.\n",
+			greeting)
+	case domain.NotificationMagicLink:
+		return "Your sign-in link", fmt.Sprintf("%s\n\nOpen this link to sign in:\n\n%s\n\nIt expires at %s and works only once. If you did not try to sign in, you can ignore this email.\n",
+			greeting, msg.Link, expires)
+	case domain.NotificationAccountExists:
+		return "You already have an account", fmt.Sprintf("%s\n\nSomeone tried to register with this email address, but you already have an account. If this was you, sign in or reset your password instead.\n",
+			greeting)
+	default:
+		return "Account notification", fmt.Sprintf("%s\n\nThere is news about your account.\n", greeting)
+	}
+}